@@ -0,0 +1,123 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/transfer"
+)
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "usage: %s [-config config.json] [command [command flags]]\n\n", os.Args[0])
+	fmt.Fprintln(out, "without command, the service is started. commands:")
+	fmt.Fprintln(out, "  dump     write all variables of the configured database to a NDJSON file")
+	fmt.Fprintln(out, "  restore  write the variables of a NDJSON file to the configured database")
+	fmt.Fprintln(out, "  copy     copy all variables of the configured database to the database of -target-config")
+	fmt.Fprintln(out, "  verify   compare the variable counts per user of the configured database with a file or another database")
+	fmt.Fprintln(out, "\nflags:")
+	flag.PrintDefaults()
+}
+
+// runCommand runs the offline command and returns the exit code of the process
+func runCommand(config configuration.Config, command string, args []string) int {
+	logger := config.GetLogger()
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	defer cancel()
+
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	file := flags.String("file", "", "location of the NDJSON dump")
+	targetConfigLocation := flags.String("target-config", "", "configuration file of the target database (copy, verify)")
+	options := transfer.Options{Logger: logger}
+	flags.BoolVar(&options.Resume, "resume", false, "continue an interrupted dump, restore or copy")
+	flags.IntVar(&options.BatchSize, "batch-size", 1000, "variables read per database request")
+	flags.IntVar(&options.ProgressInterval, "progress", 10000, "log progress after every n variables")
+	verify := flags.Bool("verify", true, "compare the variable counts per user after restore or copy")
+	err := flags.Parse(args)
+	if err != nil {
+		return 2
+	}
+
+	db, err := database.New(ctx, wg, config)
+	if err != nil {
+		logger.Error("unable to connect to database", "error", err)
+		return 1
+	}
+	var target database.Database
+	if *targetConfigLocation != "" {
+		targetConfig, err := configuration.Load(*targetConfigLocation)
+		if err != nil {
+			logger.Error("unable to load target config", "error", err)
+			return 1
+		}
+		target, err = database.New(ctx, wg, targetConfig)
+		if err != nil {
+			logger.Error("unable to connect to target database", "error", err)
+			return 1
+		}
+	}
+
+	var stats transfer.Stats
+	var mismatches []transfer.Mismatch
+	switch {
+	case command == "dump" && *file != "":
+		stats, err = transfer.Dump(ctx, db, *file, options)
+	case command == "restore" && *file != "":
+		stats, err = transfer.Restore(ctx, db, *file, options)
+		if err == nil && *verify {
+			mismatches, err = transfer.VerifyDump(ctx, db, *file)
+		}
+	case command == "copy" && target != nil:
+		stats, err = transfer.Copy(ctx, db, target, options)
+		if err == nil && *verify {
+			mismatches, err = transfer.Verify(ctx, db, target)
+		}
+	case command == "verify" && *file != "":
+		mismatches, err = transfer.VerifyDump(ctx, db, *file)
+	case command == "verify" && target != nil:
+		mismatches, err = transfer.Verify(ctx, db, target)
+	default:
+		err = errors.New("unknown command or missing -file/-target-config flag")
+		fmt.Fprintln(flags.Output(), err)
+		flags.Usage()
+		return 2
+	}
+	if err != nil {
+		logger.Error(command+" failed", "error", err, "users", stats.Users, "variables", stats.Variables, "skipped", stats.Skipped)
+		return 1
+	}
+	logger.Info(command+" finished", "users", stats.Users, "variables", stats.Variables, "skipped", stats.Skipped)
+	for _, mismatch := range mismatches {
+		logger.Error("variable count mismatch", "user", mismatch.UserId, "expected", mismatch.Expected, "actual", mismatch.Actual)
+	}
+	if len(mismatches) > 0 {
+		return 1
+	}
+	return 0
+}
@@ -20,6 +20,120 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/browse/variables": {
+            "get": {
+                "description": "lists the variables with keys starting with prefix and without delimiter after the prefix, and the common prefixes of the other variables (the key up to and including the first delimiter after the prefix), e.g. prefix=plant1/\u0026delimiter=/ lists the variable plant1/name and the common prefix plant1/line3/; limit and offset apply to variables and common prefixes in key order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "lists variables like a folder tree",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "only variables with keys starting with this prefix (case-sensitive); alias of key_prefix",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "groups keys containing the delimiter after the prefix into common prefixes, usually /; no grouping if empty",
+                        "name": "delimiter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "limits the number of variables and common prefixes; 0 means unlimited",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset to be used in combination with limit",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "only variables with one of these keys; may be repeated",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\\\' escapes the next character",
+                        "name": "key_glob",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\\\d, \\\\s, \\\\w and their negations); case-insensitive unless key_regex_case_sensitive is set",
+                        "name": "key_regex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "match key_regex case-sensitive",
+                        "name": "key_regex_case_sensitive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process instance id",
+                        "name": "process_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process definition id",
+                        "name": "process_definition_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "json encoded model.ValueFilter, e.g. {\\",
+                        "name": "value_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s greater than this unix timestamp in s",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s less than this unix timestamp in s",
+                        "name": "updated_before",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VariablesListing"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
         "/bulk": {
             "post": {
                 "description": "bulk write of variables and read of values",
@@ -35,7 +149,7 @@ const docTemplate = `{
                 "summary": "bulk write of variables and read of values",
                 "parameters": [
                     {
-                        "description": "model.BulkRequest; 'get' contains a list of value keys; 'set' contains a list of model.Variable",
+                        "description": "model.BulkRequest; 'get' contains a list of value keys; 'set' contains a list of model.Variable with optional 'if_match'/'if_none_match' preconditions (like the If-Match/If-None-Match headers of PUT /values/{key}); 'atomic' executes the request in a single transaction (all or nothing)",
                         "name": "message",
                         "in": "body",
                         "required": true,
@@ -54,8 +168,20 @@ const docTemplate = `{
                             }
                         }
                     },
+                    "412": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "the value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the write exceeds max_variables or max_total_bytes of the quota"
+                    },
                     "500": {
                         "description": ""
+                    },
+                    "501": {
+                        "description": "atomic requests are not supported by the database (mongodb without replica set)"
                     }
                 }
             }
@@ -72,6 +198,40 @@ const docTemplate = `{
                 ],
                 "summary": "counts variables",
                 "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "only variables with one of these keys; may be repeated",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys starting with this prefix (case-sensitive)",
+                        "name": "key_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\\\' escapes the next character",
+                        "name": "key_glob",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\\\d, \\\\s, \\\\w and their negations); case-insensitive unless key_regex_case_sensitive is set",
+                        "name": "key_regex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "match key_regex case-sensitive",
+                        "name": "key_regex_case_sensitive",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "filter by process instance id",
@@ -83,6 +243,28 @@ const docTemplate = `{
                         "description": "filter by process definition id",
                         "name": "process_definition_id",
                         "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "json encoded model.ValueFilter, e.g. {\\",
+                        "name": "value_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s greater than this unix timestamp in s",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s less than this unix timestamp in s",
+                        "name": "updated_before",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -101,27 +283,34 @@ const docTemplate = `{
                 }
             }
         },
-        "/process-definitions/{definitionId}": {
-            "delete": {
-                "description": "deletes all variables associated with the definitionId; requesting user must be admin",
+        "/export": {
+            "get": {
+                "description": "streams all variables of the user, ordered by key",
+                "produces": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
                 "tags": [
-                    "values",
-                    "variables",
-                    "process-definitions"
+                    "export"
                 ],
-                "summary": "deletes all variables associated with the definitionId",
+                "summary": "export variables",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "definitionId associated with value",
-                        "name": "definitionId",
-                        "in": "path",
-                        "required": true
+                        "description": "ndjson (default; one variable per line) or json (array)",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                            }
+                        }
                     },
                     "400": {
                         "description": ""
@@ -132,122 +321,770 @@ const docTemplate = `{
                 }
             }
         },
-        "/process-definitions/{definitionId}/process-instances/{instanceId}/values/{key}": {
-            "put": {
-                "description": "set the value associated with the given key",
-                "consumes": [
+        "/health/live": {
+            "get": {
+                "description": "responds as long as the service is running; does not check the database; no authentication required",
+                "tags": [
+                    "health"
+                ],
+                "summary": "liveness check",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/health/ready": {
+            "get": {
+                "description": "checks if the database is reachable within the configured readiness_timeout; no authentication required",
+                "tags": [
+                    "health"
+                ],
+                "summary": "readiness check",
+                "responses": {
+                    "200": {
+                        "description": ""
+                    },
+                    "503": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/history/variables/{key}": {
+            "get": {
+                "description": "returns the retained versions of the variable, newest first (requires history_enabled)",
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "values",
-                    "process-definitions",
-                    "process-instances"
+                    "variables",
+                    "history"
                 ],
-                "summary": "set the value associated with the given key",
+                "summary": "returns the history of the variable associated with the given key",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "key of value",
+                        "description": "key of variable",
                         "name": "key",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "definitionId associated with value",
-                        "name": "definitionId",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "instanceId associated with value",
-                        "name": "instanceId",
-                        "in": "path",
-                        "required": true
+                        "type": "integer",
+                        "description": "limits size of result; 0 means unlimited",
+                        "name": "limit",
+                        "in": "query"
                     },
                     {
-                        "description": "Anything",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
-                        "schema": {}
+                        "type": "integer",
+                        "description": "offset to be used in combination with limit",
+                        "name": "offset",
+                        "in": "query"
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VariableVersion"
+                            }
+                        }
                     },
                     "400": {
                         "description": ""
                     },
                     "500": {
                         "description": ""
+                    },
+                    "501": {
+                        "description": "history is disabled"
                     }
                 }
             }
         },
-        "/process-definitions/{definitionId}/values/{key}": {
-            "put": {
-                "description": "set the value associated with the given key",
+        "/import": {
+            "post": {
+                "description": "writes the variables of an export (ndjson or json array) for the user and returns the imported keys by result",
                 "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "values",
-                    "process-definitions"
+                    "export"
                 ],
-                "summary": "set the value associated with the given key",
+                "summary": "import variables",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "key of value",
-                        "name": "key",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "definitionId associated with value",
-                        "name": "definitionId",
-                        "in": "path",
-                        "required": true
+                        "description": "handling of existing keys: fail (default; nothing is written if any key exists), skip or overwrite",
+                        "name": "conflict",
+                        "in": "query"
                     },
                     {
-                        "description": "Anything",
+                        "description": "ndjson or json array of variables; variables without unix_timestamp_in_s get the time of the import",
                         "name": "message",
                         "in": "body",
                         "required": true,
-                        "schema": {}
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                            }
+                        }
                     }
                 ],
                 "responses": {
-                    "204": {
-                        "description": ""
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ImportSummary"
+                        }
                     },
                     "400": {
                         "description": ""
                     },
+                    "409": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "a value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the import exceeds max_variables or max_total_bytes of the quota"
+                    },
                     "500": {
                         "description": ""
                     }
                 }
             }
         },
-        "/process-instances/{instanceId}": {
-            "delete": {
-                "description": "deletes all variables associated with the instanceId; requesting user must be admin",
-                "tags": [
-                    "values",
-                    "variables",
-                    "process-instances"
-                ],
-                "summary": "deletes all variables associated with the instanceId",
+        "/increment/values/{key}": {
+            "post": {
+                "description": "atomically adds delta to the numeric value associated with the given key; a missing value is created with delta as value",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values"
+                ],
+                "summary": "increment the value associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of value",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "model.IncrementRequest; a negative delta decrements the value",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.IncrementRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "the new value",
+                        "schema": {
+                            "type": "number"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the value"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "the stored value is not numeric"
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/process-definitions/{definitionId}": {
+            "delete": {
+                "description": "deletes all variables associated with the definitionId; requesting user must be admin; responds with the number of deleted variables if count is set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values",
+                    "variables",
+                    "process-definitions"
+                ],
+                "summary": "deletes all variables associated with the definitionId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "definitionId associated with value",
+                        "name": "definitionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "respond with the number of deleted variables",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteResult"
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/process-definitions/{definitionId}/process-instances/{instanceId}/values/{key}": {
+            "put": {
+                "description": "set the value associated with the given key",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values",
+                    "process-definitions",
+                    "process-instances"
+                ],
+                "summary": "set the value associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of value",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "definitionId associated with value",
+                        "name": "definitionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "instanceId associated with value",
+                        "name": "instanceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Anything",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {}
+                    },
+                    {
+                        "type": "string",
+                        "description": "only set if the current revision (ETag) matches; '*' requires an existing value",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only sets the value if it does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the value expires after the given seconds; expired values behave as missing",
+                        "name": "ttl_in_s",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix timestamp in s at which the value expires; ignored if ttl_in_s is set",
+                        "name": "expires_at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "the value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the write exceeds max_variables or max_total_bytes of the quota"
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/process-definitions/{definitionId}/values/{key}": {
+            "put": {
+                "description": "set the value associated with the given key",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values",
+                    "process-definitions"
+                ],
+                "summary": "set the value associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of value",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "definitionId associated with value",
+                        "name": "definitionId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Anything",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {}
+                    },
+                    {
+                        "type": "string",
+                        "description": "only set if the current revision (ETag) matches; '*' requires an existing value",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only sets the value if it does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the value expires after the given seconds; expired values behave as missing",
+                        "name": "ttl_in_s",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix timestamp in s at which the value expires; ignored if ttl_in_s is set",
+                        "name": "expires_at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "412": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "the value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the write exceeds max_variables or max_total_bytes of the quota"
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/process-instances/{instanceId}": {
+            "delete": {
+                "description": "deletes all variables associated with the instanceId; requesting user must be admin; responds with the number of deleted variables if count is set",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values",
+                    "variables",
+                    "process-instances"
+                ],
+                "summary": "deletes all variables associated with the instanceId",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "instanceId associated with value",
+                        "name": "instanceId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "respond with the number of deleted variables",
+                        "name": "count",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.DeleteResult"
+                        }
+                    },
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/quota": {
+            "get": {
+                "description": "returns the quota of the requesting user and the current usage; limits of 0 are unlimited",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "returns the quota of the user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.QuotaStatus"
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/quota/{userId}": {
+            "get": {
+                "description": "returns the quota of the given user and the current usage; requesting user must be admin",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "returns the quota of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.QuotaStatus"
+                        }
+                    },
+                    "403": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "put": {
+                "description": "replaces the configured limits for the given user; limits of 0 are unlimited; requesting user must be admin",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "quota"
+                ],
+                "summary": "override the quota of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "model.Quota; user_id is set from the path",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Quota"
+                        }
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "description": "removes the quota override of the given user, so that the configured limits apply again; requesting user must be admin",
+                "tags": [
+                    "quota"
+                ],
+                "summary": "remove the quota override of a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "id of the user",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": ""
+                    },
+                    "403": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/restore/variables/{key}": {
+            "post": {
+                "description": "writes the version with the given revision from the history as new version of the variable (requires history_enabled)",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables",
+                    "history"
+                ],
+                "summary": "restore a version of the variable associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of variable",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "revision to restore",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RestoreRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "only restore if the current revision (ETag) matches; '*' requires an existing variable",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only restores if the variable does not exist",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "new revision of the variable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "404": {
+                        "description": "the revision is not retained in the history"
+                    },
+                    "412": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    },
+                    "501": {
+                        "description": "history is disabled"
+                    }
+                }
+            }
+        },
+        "/shares": {
+            "get": {
+                "description": "returns the shares created by the requesting user, ordered by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "returns the shares of the user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Share"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "post": {
+                "description": "shares the variables of the requesting user with the given key or key_prefix with a user (user_id) or with all users with a role (group); write grants read/write access, read access only otherwise",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "share variables",
+                "parameters": [
+                    {
+                        "description": "model.Share; id and owner_id are set by the service",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.Share"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Share"
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/shares/received": {
+            "get": {
+                "description": "returns the shares granted to the requesting user or to one of the roles of the user, ordered by id",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "shares"
+                ],
+                "summary": "returns the shares granted to the user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Share"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            }
+        },
+        "/shares/{id}": {
+            "delete": {
+                "description": "deletes a share created by the requesting user; the shared variables are not changed",
+                "tags": [
+                    "shares"
+                ],
+                "summary": "delete a share",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "instanceId associated with value",
-                        "name": "instanceId",
+                        "description": "id of the share",
+                        "name": "id",
                         "in": "path",
                         "required": true
                     }
@@ -256,7 +1093,7 @@ const docTemplate = `{
                     "204": {
                         "description": ""
                     },
-                    "400": {
+                    "404": {
                         "description": ""
                     },
                     "500": {
@@ -267,9 +1104,10 @@ const docTemplate = `{
         },
         "/values/{key}": {
             "get": {
-                "description": "returns the value associated with the given key",
+                "description": "returns the value associated with the given key; binary values are returned as stored, with their Content-Type",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "values"
@@ -282,25 +1120,41 @@ const docTemplate = `{
                         "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json pointer (RFC 6901) to return only a part of the value, e.g. /settings/threshold; not supported for binary values",
+                        "name": "pointer",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
-                        "schema": {}
+                        "schema": {},
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the value; missing if the value does not exist"
+                            }
+                        }
                     },
                     "400": {
                         "description": ""
                     },
+                    "404": {
+                        "description": "the pointer references a missing part of the value"
+                    },
                     "500": {
                         "description": ""
                     }
                 }
             },
             "put": {
-                "description": "set the value associated with the given key",
+                "description": "set the value associated with the given key; bodies without Content-Type, with a json Content-Type or with form-urlencoded or text/plain Content-Type and valid json are stored as json value, all other bodies as binary value with their Content-Type",
                 "consumes": [
-                    "application/json"
+                    "application/json",
+                    "application/octet-stream"
                 ],
                 "tags": [
                     "values"
@@ -320,6 +1174,30 @@ const docTemplate = `{
                         "in": "body",
                         "required": true,
                         "schema": {}
+                    },
+                    {
+                        "type": "string",
+                        "description": "only set if the current revision (ETag) matches; '*' requires an existing value",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only sets the value if it does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the value expires after the given seconds; expired values behave as missing",
+                        "name": "ttl_in_s",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix timestamp in s at which the value expires; ignored if ttl_in_s is set",
+                        "name": "expires_at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -329,6 +1207,15 @@ const docTemplate = `{
                     "400": {
                         "description": ""
                     },
+                    "412": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "the value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the write exceeds max_variables or max_total_bytes of the quota"
+                    },
                     "500": {
                         "description": ""
                     }
@@ -360,6 +1247,75 @@ const docTemplate = `{
                         "description": ""
                     }
                 }
+            },
+            "patch": {
+                "description": "patch the value associated with the given key; the patch is applied atomically to the stored value (a missing value is patched as null)",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "values"
+                ],
+                "summary": "patch the value associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of value",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) depending on the Content-Type header",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {}
+                    },
+                    {
+                        "type": "string",
+                        "description": "only patch if the current revision (ETag) matches; '*' requires an existing value",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only patches if the value does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {},
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the value"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "the patch can not be applied to the stored value"
+                    },
+                    "412": {
+                        "description": ""
+                    },
+                    "415": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
             }
         },
         "/variables": {
@@ -375,32 +1331,219 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "limits size of result; 0 means unlimited",
-                        "name": "limit",
+                        "description": "limits size of result; 0 means unlimited",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "offset to be used in combination with limit",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "comma separated sort fields with .asc (default) or .desc suffix, e.g. process_instance_id.asc,unix_timestamp_in_s.desc; fields: key, unix_timestamp_in_s (alias timestamp), process_definition_id, process_instance_id and value\u003cjson pointer\u003e for an element of the value, e.g. value/status.desc; ties are ordered by key; default key.asc",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "only variables with one of these keys; may be repeated",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys starting with this prefix (case-sensitive)",
+                        "name": "key_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\\\' escapes the next character",
+                        "name": "key_glob",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\\\d, \\\\s, \\\\w and their negations); case-insensitive unless key_regex_case_sensitive is set",
+                        "name": "key_regex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "match key_regex case-sensitive",
+                        "name": "key_regex_case_sensitive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process instance id",
+                        "name": "process_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process definition id",
+                        "name": "process_definition_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "json encoded model.ValueFilter, e.g. {\\",
+                        "name": "value_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s greater than this unix timestamp in s",
+                        "name": "updated_after",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s less than this unix timestamp in s",
+                        "name": "updated_before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "X-Next-Cursor header of the previous page; lists the variables after the previous page; may not be combined with offset or changed sort; not supported for sorts by value elements",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                            }
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "link to the next page (rel=next), if X-Next-Cursor is set"
+                            },
+                            "X-Next-Cursor": {
+                                "type": "string",
+                                "description": "cursor of the next page; only set if limit is set, the page is full and the sort supports cursors"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
+            },
+            "delete": {
+                "description": "delete the variables of the requesting user matching all given filters; deleting all variables of the user without filters requires all=true; use dry_run to check which variables would be deleted",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "delete the variables matching the filters",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "only variables with one of these keys; may be repeated",
+                        "name": "key",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys starting with this prefix (case-sensitive)",
+                        "name": "key_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\\\' escapes the next character",
+                        "name": "key_glob",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\\\d, \\\\s, \\\\w and their negations); case-insensitive unless key_regex_case_sensitive is set",
+                        "name": "key_regex",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "match key_regex case-sensitive",
+                        "name": "key_regex_case_sensitive",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process instance id",
+                        "name": "process_instance_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "filter by process definition id",
+                        "name": "process_definition_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "json encoded model.ValueFilter, e.g. {\\",
+                        "name": "value_filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "only variables with unix_timestamp_in_s greater than this unix timestamp in s",
+                        "name": "updated_after",
                         "in": "query"
                     },
                     {
                         "type": "integer",
-                        "description": "offset to be used in combination with limit",
-                        "name": "offset",
+                        "description": "only variables with unix_timestamp_in_s less than this unix timestamp in s",
+                        "name": "updated_before",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "describes the sorting in the form of key.asc",
-                        "name": "sort",
+                        "type": "boolean",
+                        "description": "only count the matching variables without deleting them",
+                        "name": "dry_run",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "filter by process instance id",
-                        "name": "process_instance_id",
+                        "type": "boolean",
+                        "description": "list the keys of the matching variables; only with dry_run",
+                        "name": "include_keys",
                         "in": "query"
                     },
                     {
-                        "type": "string",
-                        "description": "filter by process definition id",
-                        "name": "process_definition_id",
+                        "type": "boolean",
+                        "description": "required to delete all variables of the user, if no filter is given",
+                        "name": "all",
                         "in": "query"
                     }
                 ],
@@ -408,10 +1551,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.VariableWithUnixTimestamp"
-                            }
+                            "$ref": "#/definitions/model.DeleteResult"
                         }
                     },
                     "400": {
@@ -440,6 +1580,12 @@ const docTemplate = `{
                         "name": "key",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix timestamp in seconds; returns the variable as it was stored at this time (requires history_enabled)",
+                        "name": "at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -447,6 +1593,12 @@ const docTemplate = `{
                         "description": "OK",
                         "schema": {
                             "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the variable; missing if the variable does not exist"
+                            }
                         }
                     },
                     "400": {
@@ -454,6 +1606,9 @@ const docTemplate = `{
                     },
                     "500": {
                         "description": ""
+                    },
+                    "501": {
+                        "description": "history is disabled"
                     }
                 }
             },
@@ -482,6 +1637,30 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.Variable"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "only set if the current revision (ETag) matches; '*' requires an existing value",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only sets the value if it does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "the variable expires after the given seconds; expired variables behave as missing",
+                        "name": "ttl_in_s",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "unix timestamp in s at which the variable expires; overwrites the expires_at of the body; ignored if ttl_in_s is set",
+                        "name": "expires_at",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -491,6 +1670,15 @@ const docTemplate = `{
                     "400": {
                         "description": ""
                     },
+                    "412": {
+                        "description": ""
+                    },
+                    "413": {
+                        "description": "the value exceeds max_value_bytes of the quota"
+                    },
+                    "429": {
+                        "description": "the write exceeds max_variables or max_total_bytes of the quota"
+                    },
                     "500": {
                         "description": ""
                     }
@@ -522,6 +1710,77 @@ const docTemplate = `{
                         "description": ""
                     }
                 }
+            },
+            "patch": {
+                "description": "patch the value of the variable associated with the given key; the patch is applied atomically to the stored variable (a missing variable is patched as null)",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "variables"
+                ],
+                "summary": "patch the value of the variable associated with the given key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "key of variable",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) depending on the Content-Type header",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {}
+                    },
+                    {
+                        "type": "string",
+                        "description": "only patch if the current revision (ETag) matches; '*' requires an existing variable",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "'*' only patches if the variable does not exist yet",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "revision of the variable"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": ""
+                    },
+                    "409": {
+                        "description": "the patch can not be applied to the stored variable"
+                    },
+                    "412": {
+                        "description": ""
+                    },
+                    "415": {
+                        "description": ""
+                    },
+                    "500": {
+                        "description": ""
+                    }
+                }
             }
         }
     },
@@ -529,6 +1788,10 @@ const docTemplate = `{
         "model.BulkRequest": {
             "type": "object",
             "properties": {
+                "atomic": {
+                    "description": "if true, the request is executed in a single database transaction: if any part fails, no variable is written",
+                    "type": "boolean"
+                },
                 "get": {
                     "type": "array",
                     "items": {
@@ -538,11 +1801,46 @@ const docTemplate = `{
                 "set": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/model.Variable"
+                        "$ref": "#/definitions/model.BulkSetItem"
                     }
                 }
             }
         },
+        "model.BulkSetItem": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "media type of binary values, which are stored as base64 encoded string; empty for json values",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "unix timestamp in s after which the variable behaves as missing; 0 = never",
+                    "type": "integer"
+                },
+                "if_match": {
+                    "description": "the variable must exist and, if not \"*\", have one of the listed revisions",
+                    "type": "string"
+                },
+                "if_none_match": {
+                    "description": "the variable must not exist or, if not \"*\", must not have one of the listed revisions",
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "process_definition_id": {
+                    "type": "string"
+                },
+                "process_instance_id": {
+                    "type": "string"
+                },
+                "ttl_in_s": {
+                    "description": "if set, overwrites expires_at with the time of the write plus ttl_in_s",
+                    "type": "integer"
+                },
+                "value": {}
+            }
+        },
         "model.Count": {
             "type": "object",
             "properties": {
@@ -551,9 +1849,156 @@ const docTemplate = `{
                 }
             }
         },
+        "model.DeleteResult": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "keys": {
+                    "description": "only set for dry runs with DeleteOptions.IncludeKeys and matching variables",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.ImportSummary": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.IncrementRequest": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "description": "may be negative to decrement",
+                    "type": "number"
+                }
+            }
+        },
+        "model.Quota": {
+            "type": "object",
+            "properties": {
+                "max_total_bytes": {
+                    "description": "max sum of the value sizes of all variables",
+                    "type": "integer"
+                },
+                "max_value_bytes": {
+                    "description": "max json size of a single value",
+                    "type": "integer"
+                },
+                "max_variables": {
+                    "description": "max number of not expired variables",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "set by the service; empty for the configured limits",
+                    "type": "string"
+                }
+            }
+        },
+        "model.QuotaStatus": {
+            "type": "object",
+            "properties": {
+                "override": {
+                    "description": "the quota is an admin override of the configured limits",
+                    "type": "boolean"
+                },
+                "quota": {
+                    "$ref": "#/definitions/model.Quota"
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.Usage"
+                }
+            }
+        },
+        "model.RestoreRequest": {
+            "type": "object",
+            "properties": {
+                "revision": {
+                    "description": "revision of the version in the history which is written as new version of the variable",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Share": {
+            "type": "object",
+            "properties": {
+                "group": {
+                    "description": "grantee group, matched against the roles of the jwt",
+                    "type": "string"
+                },
+                "id": {
+                    "description": "set by the service",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "shared key; either Key or KeyPrefix must be set",
+                    "type": "string"
+                },
+                "key_prefix": {
+                    "description": "shared key prefix, e.g. \"plant1/\"",
+                    "type": "string"
+                },
+                "owner_id": {
+                    "description": "set by the service",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "grantee; either UserId or Group must be set",
+                    "type": "string"
+                },
+                "write": {
+                    "description": "grants read/write access; read access only otherwise",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Usage": {
+            "type": "object",
+            "properties": {
+                "total_bytes": {
+                    "type": "integer"
+                },
+                "variables": {
+                    "type": "integer"
+                }
+            }
+        },
         "model.Variable": {
             "type": "object",
             "properties": {
+                "content_type": {
+                    "description": "media type of binary values, which are stored as base64 encoded string; empty for json values",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "unix timestamp in s after which the variable behaves as missing; 0 = never",
+                    "type": "integer"
+                },
                 "key": {
                     "type": "string"
                 },
@@ -566,12 +2011,61 @@ const docTemplate = `{
                 "value": {}
             }
         },
+        "model.VariableVersion": {
+            "type": "object",
+            "properties": {
+                "content_type": {
+                    "description": "media type of binary values, which are stored as base64 encoded string; empty for json values",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "unix timestamp in s after which the variable behaves as missing; 0 = never",
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "id of the user who shared the variable; only set for variables of other users, see Share",
+                    "type": "string"
+                },
+                "process_definition_id": {
+                    "type": "string"
+                },
+                "process_instance_id": {
+                    "type": "string"
+                },
+                "revision": {
+                    "type": "integer"
+                },
+                "unix_timestamp_in_s": {
+                    "type": "integer"
+                },
+                "value": {},
+                "writer": {
+                    "description": "id of the user who wrote this version",
+                    "type": "string"
+                }
+            }
+        },
         "model.VariableWithUnixTimestamp": {
             "type": "object",
             "properties": {
+                "content_type": {
+                    "description": "media type of binary values, which are stored as base64 encoded string; empty for json values",
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "unix timestamp in s after which the variable behaves as missing; 0 = never",
+                    "type": "integer"
+                },
                 "key": {
                     "type": "string"
                 },
+                "owner": {
+                    "description": "id of the user who shared the variable; only set for variables of other users, see Share",
+                    "type": "string"
+                },
                 "process_definition_id": {
                     "type": "string"
                 },
@@ -583,6 +2077,24 @@ const docTemplate = `{
                 },
                 "value": {}
             }
+        },
+        "model.VariablesListing": {
+            "type": "object",
+            "properties": {
+                "common_prefixes": {
+                    "description": "prefix up to and including the first delimiter after it, like \"plant1/line3/\"",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "variables": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.VariableWithUnixTimestamp"
+                    }
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -31,6 +31,7 @@ import (
 
 func main() {
 	configLocation := flag.String("config", "config.json", "configuration file")
+	flag.Usage = usage
 	flag.Parse()
 
 	config, err := configuration.Load(*configLocation)
@@ -38,6 +39,10 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(config, flag.Arg(0), flag.Args()[1:]))
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	wg := &sync.WaitGroup{}
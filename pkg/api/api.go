@@ -30,18 +30,39 @@ import (
 	"github.com/SENERGY-Platform/process-io-api/pkg/controller/metrics"
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 	"github.com/SENERGY-Platform/service-commons/pkg/accesslog"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"github.com/julienschmidt/httprouter"
 )
 
 type Controller interface {
-	List(userid string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
-	Get(userid string, key string) (model.VariableWithUnixTimestamp, error)
-	Set(userid string, variable model.Variable) error
-	Delete(userid string, key string) error
-	Bulk(userid string, bulk model.BulkRequest) (model.BulkResponse, error)
-	DeleteProcessDefinition(userid string, definitionId string) error
-	DeleteProcessInstance(userid string, instanceId string) error
-	Count(userid string, query model.VariablesQueryOptions) (model.Count, error)
+	List(ctx context.Context, userid string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
+	Get(ctx context.Context, userid string, key string) (model.VariableWithUnixTimestamp, error)
+	GetWithRevision(ctx context.Context, userid string, key string) (model.VariableWithUnixTimestamp, int64, error)
+	GetAtPointer(ctx context.Context, userid string, key string, pointer string) (value interface{}, revision int64, err error)
+	Set(ctx context.Context, userid string, variable model.Variable) error
+	CompareAndSet(ctx context.Context, userid string, variable model.Variable, precondition model.Precondition) error
+	Patch(ctx context.Context, userid string, key string, patch model.Patch, precondition model.Precondition) (model.VariableWithUnixTimestamp, int64, error)
+	Increment(ctx context.Context, userid string, key string, delta float64) (value interface{}, revision int64, err error)
+	Delete(ctx context.Context, userid string, key string) error
+	GetAt(ctx context.Context, userid string, key string, unixTimestampInS int64) (model.VariableWithUnixTimestamp, int64, error)
+	History(ctx context.Context, userid string, key string, query model.HistoryQueryOptions) ([]model.VariableVersion, error)
+	Restore(ctx context.Context, userid string, key string, revision int64, precondition model.Precondition) (model.VariableWithUnixTimestamp, int64, error)
+	Bulk(ctx context.Context, userid string, bulk model.BulkRequest) (model.BulkResponse, error)
+	DeleteVariables(ctx context.Context, userid string, query model.VariablesQueryOptions, options model.DeleteOptions) (model.DeleteResult, error)
+	DeleteProcessDefinition(ctx context.Context, userid string, definitionId string) (model.DeleteResult, error)
+	DeleteProcessInstance(ctx context.Context, userid string, instanceId string) (model.DeleteResult, error)
+	Count(ctx context.Context, userid string, query model.VariablesQueryOptions) (model.Count, error)
+	Browse(ctx context.Context, userid string, query model.VariablesQueryOptions, delimiter string) (model.VariablesListing, error)
+	Export(ctx context.Context, userid string, f func(variable model.VariableWithUnixTimestamp) error) error
+	Import(ctx context.Context, userid string, variables []model.VariableWithUnixTimestamp, conflict string) (model.ImportSummary, error)
+	Ping(ctx context.Context) error
+	ListShares(ctx context.Context, userid string) ([]model.Share, error)
+	ListReceivedShares(ctx context.Context, userid string) ([]model.Share, error)
+	CreateShare(ctx context.Context, userid string, share model.Share) (model.Share, error)
+	DeleteShare(ctx context.Context, userid string, id string) error
+	GetQuota(ctx context.Context, userid string) (model.QuotaStatus, error)
+	SetQuota(ctx context.Context, userid string, quotaUserId string, quota model.Quota) error
+	DeleteQuota(ctx context.Context, userid string, quotaUserId string) error
 }
 
 type ControllerWithMetrics interface {
@@ -99,16 +120,42 @@ func GetRouter(config configuration.Config, command ControllerWithMetrics) http.
 		}
 	}
 
-	var handler http.Handler
+	var handler http.Handler = withGroups(router)
+	if config.MaxRequestBodyBytes > 0 {
+		handler = withMaxBodySize(handler, config.MaxRequestBodyBytes)
+	}
 	if config.DisableHttpLogger {
-		handler = util.NewCors(router)
+		handler = util.NewCors(handler)
 	} else {
-		handler = accesslog.New(util.NewCors(router))
+		handler = accesslog.New(util.NewCors(handler))
 	}
 
 	return handler
 }
 
+// withGroups adds the roles of the jwt to the request context as groups of the user, to resolve shares granted to groups (see model.Share)
+func withGroups(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		token, err := jwt.GetParsedToken(request)
+		if err == nil {
+			request = request.WithContext(model.WithGroups(request.Context(), token.GetRoles()))
+		}
+		handler.ServeHTTP(writer, request)
+	})
+}
+
+// withMaxBodySize rejects requests with a larger Content-Length and limits the read body of requests without Content-Length
+func withMaxBodySize(handler http.Handler, maxBytes int64) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if request.ContentLength > maxBytes {
+			http.Error(writer, "request body exceeds max_request_body_bytes", http.StatusRequestEntityTooLarge)
+			return
+		}
+		request.Body = http.MaxBytesReader(writer, request.Body, maxBytes)
+		handler.ServeHTTP(writer, request)
+	})
+}
+
 func getEndpointMethods(e interface{}) map[string]func(config configuration.Config, router *httprouter.Router, ctrl Controller) {
 	result := map[string]EndpointMethod{}
 	objRef := reflect.ValueOf(e)
@@ -37,9 +37,13 @@ type Bulk struct{}
 // @Tags         bulk
 // @Accept       json
 // @Produce      json
-// @Param        message body model.BulkRequest true "model.BulkRequest; 'get' contains a list of value keys; 'set' contains a list of model.Variable"
+// @Param        message body model.BulkRequest true "model.BulkRequest; 'get' contains a list of value keys; 'set' contains a list of model.Variable with optional 'if_match'/'if_none_match' preconditions (like the If-Match/If-None-Match headers of PUT /values/{key}); 'atomic' executes the request in a single transaction (all or nothing)"
 // @Success      200 {object} model.BulkResponse
+// @Failure      412
+// @Failure      413 "the value exceeds max_value_bytes of the quota"
+// @Failure      429 "the write exceeds max_variables or max_total_bytes of the quota"
 // @Failure      500
+// @Failure      501 "atomic requests are not supported by the database (mongodb without replica set)"
 // @Router       /bulk [post]
 func (this *Bulk) Bulk(config configuration.Config, router *httprouter.Router, ctrl Controller) {
 	router.POST("/bulk", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -51,13 +55,13 @@ func (this *Bulk) Bulk(config configuration.Config, router *httprouter.Router, c
 		msg := model.BulkRequest{}
 		err = json.NewDecoder(request.Body).Decode(&msg)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusBadRequest)
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
 			return
 		}
 
-		result, err := ctrl.Bulk(token.GetUserId(), msg)
+		result, err := ctrl.Bulk(request.Context(), token.GetUserId(), msg)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
 
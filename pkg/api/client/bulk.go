@@ -18,6 +18,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -29,7 +30,7 @@ import (
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 )
 
-func (this *Client) Bulk(userid string, bulk model.BulkRequest) (outputs model.BulkResponse, err error) {
+func (this *Client) Bulk(ctx context.Context, userid string, bulk model.BulkRequest) (outputs model.BulkResponse, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
 		return outputs, err
@@ -42,7 +43,8 @@ func (this *Client) Bulk(userid string, bulk model.BulkRequest) (outputs model.B
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		this.apiUrl+"/bulk",
 		bytes.NewBuffer(body),
@@ -60,6 +62,22 @@ func (this *Client) Bulk(userid string, bulk model.BulkRequest) (outputs model.B
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		temp, _ := io.ReadAll(resp.Body)
+		return outputs, fmt.Errorf("%w: %v", model.ErrPreconditionFailed, string(temp))
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		temp, _ := io.ReadAll(resp.Body)
+		return outputs, fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		temp, _ := io.ReadAll(resp.Body)
+		return outputs, fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+	}
+	if resp.StatusCode == http.StatusNotImplemented {
+		temp, _ := io.ReadAll(resp.Body)
+		return outputs, fmt.Errorf("%w: %v", model.ErrTransactionsUnsupported, string(temp))
+	}
 	if resp.StatusCode >= 300 {
 		debug.PrintStack()
 		temp, _ := io.ReadAll(resp.Body)
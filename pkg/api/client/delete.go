@@ -17,6 +17,8 @@
 package client
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
@@ -24,9 +26,11 @@ import (
 	"net/url"
 	"runtime/debug"
 	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 )
 
-func (this *Client) Delete(userid string, key string) error {
+func (this *Client) Delete(ctx context.Context, userid string, key string) error {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
 		return err
@@ -35,7 +39,8 @@ func (this *Client) Delete(userid string, key string) error {
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"DELETE",
 		this.apiUrl+"/variables/"+url.PathEscape(key),
 		nil,
@@ -61,72 +66,127 @@ func (this *Client) Delete(userid string, key string) error {
 	return nil
 }
 
-func (this *Client) DeleteProcessDefinition(userid string, definitionId string) error {
+func (this *Client) DeleteProcessDefinition(ctx context.Context, userid string, definitionId string) (result model.DeleteResult, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
-		return err
+		return result, err
 	}
 	slog.Debug("delete process-definition", "userid", userid, "definitionId", definitionId)
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"DELETE",
-		this.apiUrl+"/process-definitions/"+url.PathEscape(definitionId),
+		this.apiUrl+"/process-definitions/"+url.PathEscape(definitionId)+"?count=true",
 		nil,
 	)
 	if err != nil {
 		debug.PrintStack()
-		return err
+		return result, err
 	}
 	req.Header.Set("Authorization", token)
 	req.Header.Set("X-UserId", userid)
 	resp, err := client.Do(req)
 	if err != nil {
 		debug.PrintStack()
-		return err
+		return result, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
 		debug.PrintStack()
 		temp, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
 	}
-	return nil
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
 }
 
-func (this *Client) DeleteProcessInstance(userid string, instanceId string) error {
+func (this *Client) DeleteProcessInstance(ctx context.Context, userid string, instanceId string) (result model.DeleteResult, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
-		return err
+		return result, err
 	}
 	slog.Debug("delete process-instance", "userid", userid, "instanceId", instanceId)
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"DELETE",
-		this.apiUrl+"/process-instances/"+url.PathEscape(instanceId),
+		this.apiUrl+"/process-instances/"+url.PathEscape(instanceId)+"?count=true",
 		nil,
 	)
 	if err != nil {
 		debug.PrintStack()
-		return err
+		return result, err
 	}
 	req.Header.Set("Authorization", token)
 	req.Header.Set("X-UserId", userid)
 	resp, err := client.Do(req)
 	if err != nil {
 		debug.PrintStack()
-		return err
+		return result, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
 		debug.PrintStack()
 		temp, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
 	}
-	return nil
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+func (this *Client) DeleteVariables(ctx context.Context, userid string, query model.VariablesQueryOptions, options model.DeleteOptions) (result model.DeleteResult, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("delete variables", "userid", userid, "query", fmt.Sprintf("%#v", query), "options", fmt.Sprintf("%#v", options))
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	values, err := url.ParseQuery(query.Encode())
+	if err != nil {
+		return result, err
+	}
+	if options.DryRun {
+		values.Set("dry_run", "true")
+	}
+	if options.IncludeKeys {
+		values.Set("include_keys", "true")
+	}
+	if options.All {
+		values.Set("all", "true")
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"DELETE",
+		this.apiUrl+"/variables?"+values.Encode(),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
 }
@@ -0,0 +1,142 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// Export calls f for every variable of the user, ordered by key, while the export is received
+func (this *Client) Export(ctx context.Context, userid string, f func(variable model.VariableWithUnixTimestamp) error) error {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return err
+	}
+	slog.Debug("export", "userid", userid)
+	client := http.Client{
+		Timeout: time.Minute,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/export?format="+model.ExportFormatNdjson,
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		variable := model.VariableWithUnixTimestamp{}
+		err = decoder.Decode(&variable)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		err = f(variable)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Import writes the variables for the user; conflict is one of model.ImportConflictOverwrite, model.ImportConflictSkip or model.ImportConflictFail
+func (this *Client) Import(ctx context.Context, userid string, variables []model.VariableWithUnixTimestamp, conflict string) (result model.ImportSummary, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	if variables == nil {
+		variables = []model.VariableWithUnixTimestamp{}
+	}
+	body, err := json.Marshal(variables)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("import", "userid", userid, "conflict", conflict, "variables", len(variables))
+	client := http.Client{
+		Timeout: time.Minute,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		this.apiUrl+"/import?conflict="+url.QueryEscape(conflict),
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		temp, _ := io.ReadAll(resp.Body)
+		switch resp.StatusCode {
+		case http.StatusConflict:
+			return result, fmt.Errorf("%w: %v", model.ErrImportConflict, string(temp))
+		case http.StatusBadRequest:
+			return result, fmt.Errorf("%w: %v", model.ErrInvalidImport, string(temp))
+		case http.StatusRequestEntityTooLarge:
+			return result, fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+		case http.StatusTooManyRequests:
+			return result, fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+		}
+		debug.PrintStack()
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
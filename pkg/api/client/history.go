@@ -0,0 +1,193 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"strconv"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// GetAt returns the variable as it was stored at the given time and its revision at that time;
+// returns an error wrapping model.ErrHistoryDisabled if the service does not retain a history
+func (this *Client) GetAt(ctx context.Context, userid string, key string, unixTimestampInS int64) (value model.VariableWithUnixTimestamp, revision int64, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return value, revision, err
+	}
+	slog.Debug("read", "userid", userid, "key", key, "at", unixTimestampInS)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/variables/"+url.PathEscape(key)+"?"+url.Values{"at": {strconv.FormatInt(unixTimestampInS, 10)}}.Encode(),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotImplemented {
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("%w: %v", model.ErrHistoryDisabled, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		revision, err = model.ETagToRevision(etag)
+		if err != nil {
+			return value, revision, err
+		}
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	return value, revision, err
+}
+
+// History returns the retained versions of the variable, newest first;
+// returns an error wrapping model.ErrHistoryDisabled if the service does not retain a history
+func (this *Client) History(ctx context.Context, userid string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("history", "userid", userid, "key", key, "query", fmt.Sprintf("%#v", query))
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/history/variables/"+url.PathEscape(key)+"?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotImplemented {
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("%w: %v", model.ErrHistoryDisabled, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+// Restore writes the version with the given revision from the history as new version of the variable,
+// if the stored variable matches the precondition; returns the new variable and revision
+func (this *Client) Restore(ctx context.Context, userid string, key string, revision int64, precondition model.Precondition) (value model.VariableWithUnixTimestamp, newRevision int64, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return value, newRevision, err
+	}
+	body, err := json.Marshal(model.RestoreRequest{Revision: revision})
+	if err != nil {
+		return value, newRevision, err
+	}
+	slog.Debug("restore", "userid", userid, "key", key, "revision", revision)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		this.apiUrl+"/restore/variables/"+url.PathEscape(key),
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return value, newRevision, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	if precondition.IfMatch != "" {
+		req.Header.Set("If-Match", precondition.IfMatch)
+	}
+	if precondition.IfNoneMatch != "" {
+		req.Header.Set("If-None-Match", precondition.IfNoneMatch)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return value, newRevision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		temp, _ := io.ReadAll(resp.Body)
+		switch resp.StatusCode {
+		case http.StatusPreconditionFailed:
+			return value, newRevision, fmt.Errorf("%w: %v", model.ErrPreconditionFailed, string(temp))
+		case http.StatusNotFound:
+			return value, newRevision, fmt.Errorf("%w: %v", model.ErrVersionNotFound, string(temp))
+		case http.StatusNotImplemented:
+			return value, newRevision, fmt.Errorf("%w: %v", model.ErrHistoryDisabled, string(temp))
+		case http.StatusRequestEntityTooLarge:
+			return value, newRevision, fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+		case http.StatusTooManyRequests:
+			return value, newRevision, fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+		}
+		debug.PrintStack()
+		return value, newRevision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	newRevision, err = model.ETagToRevision(resp.Header.Get("ETag"))
+	if err != nil {
+		return value, newRevision, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	return value, newRevision, err
+}
@@ -17,18 +17,20 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"runtime/debug"
 	"time"
 
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 )
 
-func (this *Client) List(userid string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+func (this *Client) List(ctx context.Context, userid string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
 		return result, err
@@ -37,7 +39,8 @@ func (this *Client) List(userid string, query model.VariablesQueryOptions) (resu
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		this.apiUrl+"/variables?"+query.Encode(),
 		nil,
@@ -65,7 +68,7 @@ func (this *Client) List(userid string, query model.VariablesQueryOptions) (resu
 	return result, err
 }
 
-func (this *Client) Count(userid string, query model.VariablesQueryOptions) (result model.Count, err error) {
+func (this *Client) Count(ctx context.Context, userid string, query model.VariablesQueryOptions) (result model.Count, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
 		return result, err
@@ -74,7 +77,8 @@ func (this *Client) Count(userid string, query model.VariablesQueryOptions) (res
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		this.apiUrl+"/count/variables?"+query.Encode(),
 		nil,
@@ -101,3 +105,41 @@ func (this *Client) Count(userid string, query model.VariablesQueryOptions) (res
 	err = json.NewDecoder(resp.Body).Decode(&result)
 	return result, err
 }
+
+func (this *Client) Browse(ctx context.Context, userid string, query model.VariablesQueryOptions, delimiter string) (result model.VariablesListing, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("browse", "userid", userid, "query", fmt.Sprintf("%#v", query), "delimiter", delimiter)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/browse/variables?"+query.Encode()+"&"+url.Values{"delimiter": {delimiter}}.Encode(),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func (this *Client) GetQuota(ctx context.Context, userid string) (result model.QuotaStatus, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("get quota", "userid", userid)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/quota",
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+// SetQuota overrides the quota of quotaUserId; userid must be an admin
+func (this *Client) SetQuota(ctx context.Context, userid string, quotaUserId string, quota model.Quota) error {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	slog.Debug("set quota", "userid", userid, "quotaUserId", quotaUserId, "body", string(body))
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"PUT",
+		this.apiUrl+"/quota/"+url.PathEscape(quotaUserId),
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %v", model.ErrInvalidQuota, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+	return nil
+}
+
+// DeleteQuota removes the quota override of quotaUserId; userid must be an admin
+func (this *Client) DeleteQuota(ctx context.Context, userid string, quotaUserId string) error {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return err
+	}
+	slog.Debug("delete quota", "userid", userid, "quotaUserId", quotaUserId)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"DELETE",
+		this.apiUrl+"/quota/"+url.PathEscape(quotaUserId),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+	return nil
+}
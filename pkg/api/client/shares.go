@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func (this *Client) ListShares(ctx context.Context, userid string) (result []model.Share, err error) {
+	return this.listShares(ctx, userid, "/shares")
+}
+
+func (this *Client) ListReceivedShares(ctx context.Context, userid string) (result []model.Share, err error) {
+	return this.listShares(ctx, userid, "/shares/received")
+}
+
+func (this *Client) listShares(ctx context.Context, userid string, path string) (result []model.Share, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("list shares", "userid", userid, "path", path)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+path,
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+func (this *Client) CreateShare(ctx context.Context, userid string, share model.Share) (result model.Share, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return result, err
+	}
+	body, err := json.Marshal(share)
+	if err != nil {
+		return result, err
+	}
+	slog.Debug("create share", "userid", userid, "body", string(body))
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		this.apiUrl+"/shares",
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return result, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest {
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("%w: %v", model.ErrInvalidShare, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return result, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+func (this *Client) DeleteShare(ctx context.Context, userid string, id string) error {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return err
+	}
+	slog.Debug("delete share", "userid", userid, "id", id)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"DELETE",
+		this.apiUrl+"/shares/"+url.PathEscape(id),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%w: %v", model.ErrShareNotFound, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+	return nil
+}
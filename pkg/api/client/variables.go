@@ -18,6 +18,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -30,7 +31,13 @@ import (
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 )
 
-func (this *Client) Set(userid string, variable model.Variable) (err error) {
+func (this *Client) Set(ctx context.Context, userid string, variable model.Variable) (err error) {
+	return this.CompareAndSet(ctx, userid, variable, model.Precondition{})
+}
+
+// CompareAndSet sets the variable, if the stored variable matches the precondition;
+// returns an error wrapping model.ErrPreconditionFailed otherwise
+func (this *Client) CompareAndSet(ctx context.Context, userid string, variable model.Variable, precondition model.Precondition) (err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
 		return err
@@ -43,7 +50,8 @@ func (this *Client) Set(userid string, variable model.Variable) (err error) {
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"PUT",
 		this.apiUrl+"/variables/"+url.PathEscape(variable.Key),
 		bytes.NewBuffer(body),
@@ -54,6 +62,12 @@ func (this *Client) Set(userid string, variable model.Variable) (err error) {
 	}
 	req.Header.Set("Authorization", token)
 	req.Header.Set("X-UserId", userid)
+	if precondition.IfMatch != "" {
+		req.Header.Set("If-Match", precondition.IfMatch)
+	}
+	if precondition.IfNoneMatch != "" {
+		req.Header.Set("If-None-Match", precondition.IfNoneMatch)
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		debug.PrintStack()
@@ -62,6 +76,15 @@ func (this *Client) Set(userid string, variable model.Variable) (err error) {
 	defer resp.Body.Close()
 
 	temp, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusPreconditionFailed {
+		return fmt.Errorf("%w: %v", model.ErrPreconditionFailed, string(temp))
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		return fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+	}
 	if resp.StatusCode >= 300 {
 		debug.PrintStack()
 		return fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
@@ -69,39 +92,229 @@ func (this *Client) Set(userid string, variable model.Variable) (err error) {
 	return err
 }
 
-func (this *Client) Get(userid string, key string) (value model.VariableWithUnixTimestamp, err error) {
+func (this *Client) Get(ctx context.Context, userid string, key string) (value model.VariableWithUnixTimestamp, err error) {
+	value, _, err = this.GetWithRevision(ctx, userid, key)
+	return value, err
+}
+
+// GetWithRevision returns the variable and its revision (read from the ETag header); the revision is 0 if the variable does not exist
+func (this *Client) GetWithRevision(ctx context.Context, userid string, key string) (value model.VariableWithUnixTimestamp, revision int64, err error) {
 	token, err := this.auth.ExchangeUserToken(userid)
 	if err != nil {
-		return value, err
+		return value, revision, err
 	}
 	slog.Debug("read", "userid", userid, "key", key)
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
-	req, err := http.NewRequest(
+	req, err := http.NewRequestWithContext(
+		ctx,
 		"GET",
 		this.apiUrl+"/variables/"+url.PathEscape(key),
 		nil,
 	)
 	if err != nil {
 		debug.PrintStack()
-		return value, err
+		return value, revision, err
 	}
 	req.Header.Set("Authorization", token)
 	req.Header.Set("X-UserId", userid)
 	resp, err := client.Do(req)
 	if err != nil {
 		debug.PrintStack()
-		return value, err
+		return value, revision, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 300 {
 		debug.PrintStack()
 		temp, _ := io.ReadAll(resp.Body)
-		return value, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+		return value, revision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
 	}
 
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		revision, err = model.ETagToRevision(etag)
+		if err != nil {
+			return value, revision, err
+		}
+	}
 	err = json.NewDecoder(resp.Body).Decode(&value)
-	return value, err
+	return value, revision, err
+}
+
+// Increment atomically adds delta to the numeric value of the variable and returns the new value and revision;
+// returns an error wrapping model.ErrNotNumeric if the stored value is not a number
+func (this *Client) Increment(ctx context.Context, userid string, key string, delta float64) (value interface{}, revision int64, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return value, revision, err
+	}
+	body, err := json.Marshal(model.IncrementRequest{Delta: delta})
+	if err != nil {
+		return value, revision, err
+	}
+	slog.Debug("increment", "userid", userid, "key", key, "delta", delta)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"POST",
+		this.apiUrl+"/increment/values/"+url.PathEscape(key),
+		bytes.NewBuffer(body),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusConflict {
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("%w: %v", model.ErrNotNumeric, string(temp))
+	}
+	if resp.StatusCode == http.StatusRequestEntityTooLarge {
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+	}
+	if resp.StatusCode >= 300 {
+		debug.PrintStack()
+		temp, _ := io.ReadAll(resp.Body)
+		return value, revision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	revision, err = model.ETagToRevision(resp.Header.Get("ETag"))
+	if err != nil {
+		return value, revision, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	return value, revision, err
+}
+
+// Patch applies a JSON Merge Patch or JSON Patch to the stored value, if the stored variable matches the precondition;
+// returns the patched variable and its revision
+func (this *Client) Patch(ctx context.Context, userid string, key string, patch model.Patch, precondition model.Precondition) (value model.VariableWithUnixTimestamp, revision int64, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return value, revision, err
+	}
+	slog.Debug("patch", "userid", userid, "key", key, "type", patch.Type, "patch", string(patch.Patch))
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"PATCH",
+		this.apiUrl+"/variables/"+url.PathEscape(key),
+		bytes.NewBuffer(patch.Patch),
+	)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	req.Header.Set("Content-Type", patch.Type)
+	if precondition.IfMatch != "" {
+		req.Header.Set("If-Match", precondition.IfMatch)
+	}
+	if precondition.IfNoneMatch != "" {
+		req.Header.Set("If-None-Match", precondition.IfNoneMatch)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		temp, _ := io.ReadAll(resp.Body)
+		switch resp.StatusCode {
+		case http.StatusPreconditionFailed:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrPreconditionFailed, string(temp))
+		case http.StatusConflict:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrPatchFailed, string(temp))
+		case http.StatusUnsupportedMediaType:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrUnsupportedPatchType, string(temp))
+		case http.StatusBadRequest:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrInvalidPatch, string(temp))
+		case http.StatusRequestEntityTooLarge:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrValueTooLarge, string(temp))
+		case http.StatusTooManyRequests:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrQuotaExceeded, string(temp))
+		}
+		debug.PrintStack()
+		return value, revision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	revision, err = model.ETagToRevision(resp.Header.Get("ETag"))
+	if err != nil {
+		return value, revision, err
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	return value, revision, err
+}
+
+// GetAtPointer returns the element of the value referenced by the json pointer (RFC 6901) and the revision of the variable;
+// returns an error wrapping model.ErrPointerNotFound if the referenced element does not exist
+func (this *Client) GetAtPointer(ctx context.Context, userid string, key string, pointer string) (value interface{}, revision int64, err error) {
+	token, err := this.auth.ExchangeUserToken(userid)
+	if err != nil {
+		return value, revision, err
+	}
+	slog.Debug("read", "userid", userid, "key", key, "pointer", pointer)
+	client := http.Client{
+		Timeout: 5 * time.Second,
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		this.apiUrl+"/values/"+url.PathEscape(key)+"?"+url.Values{"pointer": {pointer}}.Encode(),
+		nil,
+	)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("X-UserId", userid)
+	resp, err := client.Do(req)
+	if err != nil {
+		debug.PrintStack()
+		return value, revision, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		temp, _ := io.ReadAll(resp.Body)
+		switch resp.StatusCode {
+		case http.StatusNotFound:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrPointerNotFound, string(temp))
+		case http.StatusBadRequest:
+			return value, revision, fmt.Errorf("%w: %v", model.ErrInvalidPointer, string(temp))
+		}
+		debug.PrintStack()
+		return value, revision, fmt.Errorf("unexpected response: %v, %v", resp.StatusCode, string(temp))
+	}
+
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		revision, err = model.ETagToRevision(etag)
+		if err != nil {
+			return value, revision, err
+		}
+	}
+	err = json.NewDecoder(resp.Body).Decode(&value)
+	return value, revision, err
 }
@@ -0,0 +1,186 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, &Export{})
+}
+
+type Export struct{}
+
+// Export godoc
+// @Summary      export variables
+// @Description  streams all variables of the user, ordered by key
+// @Tags         export
+// @Param        format query string false "ndjson (default; one variable per line) or json (array)"
+// @Produce      json,application/x-ndjson
+// @Success      200 {array} model.VariableWithUnixTimestamp
+// @Failure      400
+// @Failure      500
+// @Router       /export [get]
+func (this *Export) Export(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/export", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		format := request.URL.Query().Get("format")
+		if format == "" {
+			format = model.ExportFormatNdjson
+		}
+		if format != model.ExportFormatNdjson && format != model.ExportFormatJson {
+			http.Error(writer, "unknown format", http.StatusBadRequest)
+			return
+		}
+
+		//the response is written while the variables are read; errors after the first variable can only abort the response
+		started := false
+		encoder := json.NewEncoder(writer)
+		err = ctrl.Export(request.Context(), token.GetUserId(), func(variable model.VariableWithUnixTimestamp) error {
+			if !started {
+				started = true
+				startExport(writer, format)
+			} else if format == model.ExportFormatJson {
+				writer.Write([]byte(","))
+			}
+			return encoder.Encode(variable)
+		})
+		if err != nil && !started {
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
+			return
+		}
+		if err != nil {
+			config.GetLogger().Error("unable to finish export", "error", err)
+			return
+		}
+		if !started {
+			startExport(writer, format)
+		}
+		if format == model.ExportFormatJson {
+			writer.Write([]byte("]\n"))
+		}
+	})
+}
+
+func startExport(writer http.ResponseWriter, format string) {
+	if format == model.ExportFormatJson {
+		writer.Header().Set("Content-Type", "application/json")
+		writer.WriteHeader(http.StatusOK)
+		writer.Write([]byte("["))
+		return
+	}
+	writer.Header().Set("Content-Type", model.NdjsonContentType)
+	writer.WriteHeader(http.StatusOK)
+}
+
+// Import godoc
+// @Summary      import variables
+// @Description  writes the variables of an export (ndjson or json array) for the user and returns the imported keys by result
+// @Tags         export
+// @Param        conflict query string false "handling of existing keys: fail (default; nothing is written if any key exists), skip or overwrite"
+// @Param        message body []model.VariableWithUnixTimestamp true "ndjson or json array of variables; variables without unix_timestamp_in_s get the time of the import"
+// @Accept       json,application/x-ndjson
+// @Produce      json
+// @Success      200 {object} model.ImportSummary
+// @Failure      400
+// @Failure      409
+// @Failure      413 "a value exceeds max_value_bytes of the quota"
+// @Failure      429 "the import exceeds max_variables or max_total_bytes of the quota"
+// @Failure      500
+// @Router       /import [post]
+func (this *Export) Import(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.POST("/import", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		conflict := request.URL.Query().Get("conflict")
+		if conflict == "" {
+			conflict = model.ImportConflictFail
+		}
+		variables, err := readImport(request.Body)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		result, err := ctrl.Import(request.Context(), token.GetUserId(), variables, conflict)
+		if err != nil {
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// readImport reads a json array or a sequence of json objects (ndjson) of variables
+func readImport(body io.Reader) (result []model.VariableWithUnixTimestamp, err error) {
+	reader := bufio.NewReader(body)
+	for {
+		b, err := reader.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidImport, err)
+		}
+		if !bytes.ContainsRune([]byte(" \t\r\n"), rune(b)) {
+			err = reader.UnreadByte()
+			if err != nil {
+				return nil, err
+			}
+			if b == '[' {
+				err = json.NewDecoder(reader).Decode(&result)
+				if err != nil {
+					return nil, errors.Join(model.ErrInvalidImport, err)
+				}
+				return result, nil
+			}
+			break
+		}
+	}
+	decoder := json.NewDecoder(reader)
+	for {
+		variable := model.VariableWithUnixTimestamp{}
+		err = decoder.Decode(&variable)
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidImport, err)
+		}
+		result = append(result, variable)
+	}
+}
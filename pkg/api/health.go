@@ -0,0 +1,61 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, &Health{})
+}
+
+type Health struct{}
+
+// Live godoc
+// @Summary      liveness check
+// @Description  responds as long as the service is running; does not check the database; no authentication required
+// @Tags         health
+// @Success      200
+// @Router       /health/live [get]
+func (this *Health) Live(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/health/live", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		writer.WriteHeader(http.StatusOK)
+	})
+}
+
+// Ready godoc
+// @Summary      readiness check
+// @Description  checks if the database is reachable within the configured readiness_timeout; no authentication required
+// @Tags         health
+// @Success      200
+// @Failure      503
+// @Router       /health/ready [get]
+func (this *Health) Ready(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/health/ready", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		err := ctrl.Ping(request.Context())
+		if err != nil {
+			config.GetLogger().Warn("readiness check failed", "error", err)
+			http.Error(writer, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		writer.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/julienschmidt/httprouter"
+)
+
+// getPatch reads the patch document of the request; the patch type is the media type of the request
+func getPatch(request *http.Request) (result model.Patch, err error) {
+	result.Type, _, err = mime.ParseMediaType(request.Header.Get("Content-Type"))
+	if err != nil {
+		return result, errors.Join(model.ErrUnsupportedPatchType, err)
+	}
+	result.Patch, err = io.ReadAll(request.Body)
+	return result, err
+}
+
+// textMediaTypes are sent by clients, which do not set a Content-Type explicitly (e.g. curl -d sends application/x-www-form-urlencoded);
+// bodies with these media types are json values if they are valid json, like before binary values were supported
+var textMediaTypes = []string{"application/x-www-form-urlencoded", "text/plain"}
+
+// getValueVariable reads the body of PUT /values/{key}: bodies without Content-Type, with a json media type
+// or with one of the textMediaTypes and valid json are json values,
+// all other bodies are stored as binary value with the Content-Type of the request (see model.NewBinaryVariable)
+func getValueVariable(request *http.Request, key string) (variable model.Variable, err error) {
+	contentType := request.Header.Get("Content-Type")
+	mediaType := ""
+	if contentType != "" {
+		mediaType, _, err = mime.ParseMediaType(contentType)
+		if err != nil {
+			return variable, err
+		}
+	}
+	if mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		variable.Key = key
+		err = json.NewDecoder(request.Body).Decode(&variable.Value)
+		return variable, err
+	}
+	data, err := io.ReadAll(request.Body)
+	if err != nil {
+		return variable, err
+	}
+	if slices.Contains(textMediaTypes, mediaType) && json.Valid(data) {
+		variable.Key = key
+		err = json.Unmarshal(data, &variable.Value)
+		return variable, err
+	}
+	return model.NewBinaryVariable(key, data, contentType), nil
+}
+
+// getBodyErrorStatusCode returns 413 if the body exceeds max_request_body_bytes, 415 for unsupported patch types
+// and 400 for all other errors reading the body
+func getBodyErrorStatusCode(err error) int {
+	var maxBytesErr *http.MaxBytesError
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, model.ErrUnsupportedPatchType):
+		return http.StatusUnsupportedMediaType
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// getExpiresAt reads the optional ttl_in_s or expires_at query parameter of write requests;
+// returns defaultExpiresAt if neither is set and prefers ttl_in_s if both are set
+func getExpiresAt(request *http.Request, defaultExpiresAt int64) (int64, error) {
+	query := request.URL.Query()
+	if query.Has("ttl_in_s") {
+		ttl, err := strconv.ParseInt(query.Get("ttl_in_s"), 10, 64)
+		if err != nil {
+			return 0, errors.Join(model.ErrInvalidExpiry, err)
+		}
+		return model.ExpiresAtForTtl(configuration.TimeNow().Unix(), ttl)
+	}
+	if query.Has("expires_at") {
+		expiresAt, err := strconv.ParseInt(query.Get("expires_at"), 10, 64)
+		if err != nil || expiresAt < 0 {
+			return 0, errors.Join(model.ErrInvalidExpiry, err)
+		}
+		return expiresAt, nil
+	}
+	return defaultExpiresAt, nil
+}
+
+// getValueFilters reads the value_filter query parameters; each parameter is a json encoded model.ValueFilter
+func getValueFilters(request *http.Request) (result []model.ValueFilter, err error) {
+	for _, param := range request.URL.Query()["value_filter"] {
+		filter := model.ValueFilter{}
+		err = json.Unmarshal([]byte(param), &filter)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidValueFilter, err)
+		}
+		result = append(result, filter)
+	}
+	return result, nil
+}
+
+// getTimestampRange reads the updated_after and updated_before query parameters (unix timestamps in s) into the query
+func getTimestampRange(request *http.Request, query *model.VariablesQueryOptions) (err error) {
+	if after := request.URL.Query().Get("updated_after"); after != "" {
+		query.UpdatedAfter, err = strconv.ParseInt(after, 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	if before := request.URL.Query().Get("updated_before"); before != "" {
+		query.UpdatedBefore, err = strconv.ParseInt(before, 10, 64)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getKeyFilters reads the key, key_prefix, key_glob, key_regex and key_regex_case_sensitive query parameters into the query
+func getKeyFilters(request *http.Request, query *model.VariablesQueryOptions) (err error) {
+	params := request.URL.Query()
+	query.Keys = params["key"]
+	query.KeyPrefix = params.Get("key_prefix")
+	query.KeyGlob = params.Get("key_glob")
+	query.KeyRegex = params.Get("key_regex")
+	if caseSensitive := params.Get("key_regex_case_sensitive"); caseSensitive != "" {
+		query.KeyRegexCaseSensitive, err = strconv.ParseBool(caseSensitive)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getKeyParam returns the key of routes with a catch-all key parameter (e.g. /variables/*key); keys may contain slashes
+func getKeyParam(params httprouter.Params) string {
+	return strings.TrimPrefix(params.ByName("key"), "/")
+}
+
+func getPrecondition(request *http.Request) model.Precondition {
+	return model.Precondition{
+		IfMatch:     request.Header.Get("If-Match"),
+		IfNoneMatch: request.Header.Get("If-None-Match"),
+	}
+}
+
+// setETag sets the revision as ETag header; calculated and unknown variables (revision 0) have no ETag
+func setETag(writer http.ResponseWriter, revision int64) {
+	if revision > 0 {
+		writer.Header().Set("ETag", model.RevisionToETag(revision))
+	}
+}
+
+// setNextCursor sets the X-Next-Cursor header and a Link header to the next page of the listing;
+// nothing is set if there is no next page
+func setNextCursor(writer http.ResponseWriter, request *http.Request, cursor string) {
+	if cursor == "" {
+		return
+	}
+	query := request.URL.Query()
+	query.Del("offset")
+	query.Set("cursor", cursor)
+	next := url.URL{Path: request.URL.Path, RawQuery: query.Encode()}
+	writer.Header().Set("X-Next-Cursor", cursor)
+	writer.Header().Set("Link", "<"+next.String()+">; rel=\"next\"")
+}
+
+func getReadErrorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, model.ErrInvalidPointer), errors.Is(err, model.ErrInvalidValueFilter), errors.Is(err, model.ErrInvalidCursor), errors.Is(err, model.ErrInvalidSort), errors.Is(err, model.ErrInvalidKeyFilter), errors.Is(err, model.ErrMissingDeleteFilter), errors.Is(err, model.ErrInvalidId):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrPointerNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrHistoryDisabled):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func getWriteErrorStatusCode(err error) int {
+	switch {
+	case errors.Is(err, model.ErrPreconditionFailed):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, model.ErrInvalidPrecondition):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrNotNumeric), errors.Is(err, model.ErrPatchFailed), errors.Is(err, model.ErrUpdateConflict):
+		return http.StatusConflict
+	case errors.Is(err, model.ErrInvalidPatch):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrUnsupportedPatchType):
+		return http.StatusUnsupportedMediaType
+	case errors.Is(err, model.ErrVersionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, model.ErrHistoryDisabled), errors.Is(err, model.ErrTransactionsUnsupported):
+		return http.StatusNotImplemented
+	case errors.Is(err, model.ErrInvalidExpiry), errors.Is(err, model.ErrInvalidImport), errors.Is(err, model.ErrInvalidBinaryValue), errors.Is(err, model.ErrInvalidId):
+		return http.StatusBadRequest
+	case errors.Is(err, model.ErrImportConflict):
+		return http.StatusConflict
+	case errors.Is(err, model.ErrValueTooLarge):
+		return http.StatusRequestEntityTooLarge
+	case errors.Is(err, model.ErrQuotaExceeded):
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
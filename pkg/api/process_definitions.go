@@ -23,6 +23,7 @@ import (
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
+	"strconv"
 )
 
 func init() {
@@ -40,18 +41,25 @@ type ProcessDefinitions struct{}
 // @Param        definitionId path string true "definitionId associated with value"
 // @Param        instanceId path string true "instanceId associated with value"
 // @Param        message body Anything true "Anything"
+// @Param        If-Match header string false "only set if the current revision (ETag) matches; '*' requires an existing value"
+// @Param        If-None-Match header string false "'*' only sets the value if it does not exist yet"
+// @Param        ttl_in_s query int false "the value expires after the given seconds; expired values behave as missing"
+// @Param        expires_at query int false "unix timestamp in s at which the value expires; ignored if ttl_in_s is set"
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      413 "the value exceeds max_value_bytes of the quota"
+// @Failure      429 "the write exceeds max_variables or max_total_bytes of the quota"
 // @Failure      500
 // @Router       /process-definitions/{definitionId}/process-instances/{instanceId}/values/{key} [put]
 func (this *ProcessDefinitions) SetWithInstance(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.PUT("/process-definitions/:definitionId/process-instances/:instanceId/values/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.PUT("/process-definitions/:definitionId/process-instances/:instanceId/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
@@ -69,19 +77,26 @@ func (this *ProcessDefinitions) SetWithInstance(config configuration.Config, rou
 
 		var value interface{}
 		err = json.NewDecoder(request.Body).Decode(&value)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		expiresAt, err := getExpiresAt(request, 0)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = ctrl.Set(token.GetUserId(), model.Variable{
+		err = ctrl.CompareAndSet(request.Context(), token.GetUserId(), model.Variable{
 			Key:                 key,
 			Value:               value,
 			ProcessDefinitionId: definitionId,
 			ProcessInstanceId:   instanceId,
-		})
+			ExpiresAt:           expiresAt,
+		}, getPrecondition(request))
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
 		writer.WriteHeader(http.StatusNoContent)
@@ -96,18 +111,25 @@ func (this *ProcessDefinitions) SetWithInstance(config configuration.Config, rou
 // @Param        key path string true "key of value"
 // @Param        definitionId path string true "definitionId associated with value"
 // @Param        message body Anything true "Anything"
+// @Param        If-Match header string false "only set if the current revision (ETag) matches; '*' requires an existing value"
+// @Param        If-None-Match header string false "'*' only sets the value if it does not exist yet"
+// @Param        ttl_in_s query int false "the value expires after the given seconds; expired values behave as missing"
+// @Param        expires_at query int false "unix timestamp in s at which the value expires; ignored if ttl_in_s is set"
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      413 "the value exceeds max_value_bytes of the quota"
+// @Failure      429 "the write exceeds max_variables or max_total_bytes of the quota"
 // @Failure      500
 // @Router       /process-definitions/{definitionId}/values/{key} [put]
 func (this *ProcessDefinitions) Set(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.PUT("/process-definitions/:definitionId/values/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.PUT("/process-definitions/:definitionId/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
@@ -119,19 +141,26 @@ func (this *ProcessDefinitions) Set(config configuration.Config, router *httprou
 		}
 		var value interface{}
 		err = json.NewDecoder(request.Body).Decode(&value)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		expiresAt, err := getExpiresAt(request, 0)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = ctrl.Set(token.GetUserId(), model.Variable{
+		err = ctrl.CompareAndSet(request.Context(), token.GetUserId(), model.Variable{
 			Key:                 key,
 			Value:               value,
 			ProcessDefinitionId: definitionId,
 			ProcessInstanceId:   "",
-		})
+			ExpiresAt:           expiresAt,
+		}, getPrecondition(request))
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
 		writer.WriteHeader(http.StatusNoContent)
@@ -140,9 +169,12 @@ func (this *ProcessDefinitions) Set(config configuration.Config, router *httprou
 
 // DeleteDefinition			 godoc
 // @Summary      deletes all variables associated with the definitionId
-// @Description  deletes all variables associated with the definitionId; requesting user must be admin
+// @Description  deletes all variables associated with the definitionId; requesting user must be admin; responds with the number of deleted variables if count is set
 // @Tags         values, variables, process-definitions
 // @Param        definitionId path string true "definitionId associated with value"
+// @Param        count query boolean false "respond with the number of deleted variables"
+// @Produce      json
+// @Success      200 {object} model.DeleteResult
 // @Success      204
 // @Failure      400
 // @Failure      500
@@ -160,26 +192,43 @@ func (this *ProcessDefinitions) DeleteDefinition(config configuration.Config, ro
 			return
 		}
 
+		count := false
+		if countParam := request.URL.Query().Get("count"); countParam != "" {
+			count, err = strconv.ParseBool(countParam)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
 		definitionId := params.ByName("definitionId")
 		if definitionId == "" {
 			http.Error(writer, "missing definitionId", http.StatusBadRequest)
 			return
 		}
 
-		err = ctrl.DeleteProcessDefinition(token.GetUserId(), definitionId)
+		result, err := ctrl.DeleteProcessDefinition(request.Context(), token.GetUserId(), definitionId)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
-		writer.WriteHeader(http.StatusNoContent)
+		if !count {
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
 	})
 }
 
 // DeleteInstance			 godoc
 // @Summary      deletes all variables associated with the instanceId
-// @Description  deletes all variables associated with the instanceId; requesting user must be admin
+// @Description  deletes all variables associated with the instanceId; requesting user must be admin; responds with the number of deleted variables if count is set
 // @Tags         values, variables, process-instances
 // @Param        instanceId path string true "instanceId associated with value"
+// @Param        count query boolean false "respond with the number of deleted variables"
+// @Produce      json
+// @Success      200 {object} model.DeleteResult
 // @Success      204
 // @Failure      400
 // @Failure      500
@@ -197,17 +246,31 @@ func (this *ProcessDefinitions) DeleteInstance(config configuration.Config, rout
 			return
 		}
 
+		count := false
+		if countParam := request.URL.Query().Get("count"); countParam != "" {
+			count, err = strconv.ParseBool(countParam)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
 		instanceId := params.ByName("instanceId")
 		if instanceId == "" {
 			http.Error(writer, "missing instanceId", http.StatusBadRequest)
 			return
 		}
 
-		err = ctrl.DeleteProcessInstance(token.GetUserId(), instanceId)
+		result, err := ctrl.DeleteProcessInstance(request.Context(), token.GetUserId(), instanceId)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
-		writer.WriteHeader(http.StatusNoContent)
+		if !count {
+			writer.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
 	})
 }
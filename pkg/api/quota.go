@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, &Quota{})
+}
+
+type Quota struct{}
+
+// Get godoc
+// @Summary      returns the quota of the user
+// @Description  returns the quota of the requesting user and the current usage; limits of 0 are unlimited
+// @Tags         quota
+// @Produce      json
+// @Success      200 {object} model.QuotaStatus
+// @Failure      500
+// @Router       /quota [get]
+func (this *Quota) Get(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/quota", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		result, err := ctrl.GetQuota(request.Context(), token.GetUserId())
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// GetUser godoc
+// @Summary      returns the quota of a user
+// @Description  returns the quota of the given user and the current usage; requesting user must be admin
+// @Tags         quota
+// @Param        userId path string true "id of the user"
+// @Produce      json
+// @Success      200 {object} model.QuotaStatus
+// @Failure      403
+// @Failure      500
+// @Router       /quota/{userId} [get]
+func (this *Quota) GetUser(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/quota/:userId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !token.IsAdmin() {
+			http.Error(writer, "not allowed", http.StatusForbidden)
+			return
+		}
+		result, err := ctrl.GetQuota(request.Context(), params.ByName("userId"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// Set godoc
+// @Summary      override the quota of a user
+// @Description  replaces the configured limits for the given user; limits of 0 are unlimited; requesting user must be admin
+// @Tags         quota
+// @Accept       json
+// @Param        userId path string true "id of the user"
+// @Param        message body model.Quota true "model.Quota; user_id is set from the path"
+// @Success      204
+// @Failure      400
+// @Failure      403
+// @Failure      500
+// @Router       /quota/{userId} [put]
+func (this *Quota) Set(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.PUT("/quota/:userId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !token.IsAdmin() {
+			http.Error(writer, "not allowed", http.StatusForbidden)
+			return
+		}
+		msg := model.Quota{}
+		err = json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+		err = ctrl.SetQuota(request.Context(), token.GetUserId(), params.ByName("userId"), msg)
+		if errors.Is(err, model.ErrInvalidQuota) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// Delete godoc
+// @Summary      remove the quota override of a user
+// @Description  removes the quota override of the given user, so that the configured limits apply again; requesting user must be admin
+// @Tags         quota
+// @Param        userId path string true "id of the user"
+// @Success      204
+// @Failure      403
+// @Failure      500
+// @Router       /quota/{userId} [delete]
+func (this *Quota) Delete(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.DELETE("/quota/:userId", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if !token.IsAdmin() {
+			http.Error(writer, "not allowed", http.StatusForbidden)
+			return
+		}
+		err = ctrl.DeleteQuota(request.Context(), token.GetUserId(), params.ByName("userId"))
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -0,0 +1,151 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
+	"github.com/julienschmidt/httprouter"
+)
+
+func init() {
+	endpoints = append(endpoints, &Shares{})
+}
+
+type Shares struct{}
+
+// List godoc
+// @Summary      returns the shares of the user
+// @Description  returns the shares created by the requesting user, ordered by id
+// @Tags         shares
+// @Produce      json
+// @Success      200 {array} model.Share
+// @Failure      500
+// @Router       /shares [get]
+func (this *Shares) List(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/shares", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		result, err := ctrl.ListShares(request.Context(), token.GetUserId())
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// ListReceived godoc
+// @Summary      returns the shares granted to the user
+// @Description  returns the shares granted to the requesting user or to one of the roles of the user, ordered by id
+// @Tags         shares
+// @Produce      json
+// @Success      200 {array} model.Share
+// @Failure      500
+// @Router       /shares/received [get]
+func (this *Shares) ListReceived(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/shares/received", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		result, err := ctrl.ListReceivedShares(request.Context(), token.GetUserId())
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// Create godoc
+// @Summary      share variables
+// @Description  shares the variables of the requesting user with the given key or key_prefix with a user (user_id) or with all users with a role (group); write grants read/write access, read access only otherwise
+// @Tags         shares
+// @Accept       json
+// @Produce      json
+// @Param        message body model.Share true "model.Share; id and owner_id are set by the service"
+// @Success      200 {object} model.Share
+// @Failure      400
+// @Failure      500
+// @Router       /shares [post]
+func (this *Shares) Create(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.POST("/shares", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		msg := model.Share{}
+		err = json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+		result, err := ctrl.CreateShare(request.Context(), token.GetUserId(), msg)
+		if errors.Is(err, model.ErrInvalidShare) {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// Delete godoc
+// @Summary      delete a share
+// @Description  deletes a share created by the requesting user; the shared variables are not changed
+// @Tags         shares
+// @Param        id path string true "id of the share"
+// @Success      204
+// @Failure      404
+// @Failure      500
+// @Router       /shares/{id} [delete]
+func (this *Shares) Delete(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.DELETE("/shares/:id", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		err = ctrl.DeleteShare(request.Context(), token.GetUserId(), params.ByName("id"))
+		if errors.Is(err, model.ErrShareNotFound) {
+			http.Error(writer, err.Error(), http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writer.WriteHeader(http.StatusNoContent)
+	})
+}
@@ -23,7 +23,6 @@ import (
 	"github.com/SENERGY-Platform/service-commons/pkg/jwt"
 	"github.com/julienschmidt/httprouter"
 	"net/http"
-	"strings"
 )
 
 func init() {
@@ -36,12 +35,15 @@ type Anything = interface{}
 
 // Get godoc
 // @Summary      returns the value associated with the given key
-// @Description  returns the value associated with the given key
+// @Description  returns the value associated with the given key; binary values are returned as stored, with their Content-Type
 // @Tags         values
 // @Param        key path string true "key of value"
-// @Produce      json
+// @Param        pointer query string false "json pointer (RFC 6901) to return only a part of the value, e.g. /settings/threshold; not supported for binary values"
+// @Produce      json,octet-stream
 // @Success      200 {object} Anything
+// @Header       200 {string} ETag "revision of the value; missing if the value does not exist"
 // @Failure      400
+// @Failure      404 "the pointer references a missing part of the value"
 // @Failure      500
 // @Router       /values/{key} [get]
 func (this *Values) Get(config configuration.Config, router *httprouter.Router, ctrl Controller) {
@@ -51,67 +53,193 @@ func (this *Values) Get(config configuration.Config, router *httprouter.Router,
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := strings.TrimPrefix(params.ByName("key"), "/")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing id", http.StatusBadRequest)
 			return
 		}
-		result, err := ctrl.Get(token.GetUserId(), key)
+		pointer := request.URL.Query().Get("pointer")
+		if pointer == "" {
+			//the whole variable is read, to know the content type of binary values
+			variable, revision, err := ctrl.GetWithRevision(request.Context(), token.GetUserId(), key)
+			if err != nil {
+				http.Error(writer, err.Error(), getReadErrorStatusCode(err))
+				return
+			}
+			setETag(writer, revision)
+			if variable.IsBinary() {
+				data, err := variable.GetBinary()
+				if err != nil {
+					http.Error(writer, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				writer.Header().Set("Content-Type", variable.ContentType)
+				writer.Write(data)
+				return
+			}
+			writer.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(writer).Encode(variable.Value)
+			return
+		}
+		result, revision, err := ctrl.GetAtPointer(request.Context(), token.GetUserId(), key, pointer)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
 			return
 		}
 
+		setETag(writer, revision)
 		writer.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(writer).Encode(result.Value)
+		json.NewEncoder(writer).Encode(result)
 	})
 }
 
 // Set godoc
 // @Summary      set the value associated with the given key
-// @Description  set the value associated with the given key
+// @Description  set the value associated with the given key; bodies without Content-Type, with a json Content-Type or with form-urlencoded or text/plain Content-Type and valid json are stored as json value, all other bodies as binary value with their Content-Type
 // @Tags         values
-// @Accept       json
+// @Accept       json,octet-stream
 // @Param        key path string true "key of value"
 // @Param        message body Anything true "Anything"
+// @Param        If-Match header string false "only set if the current revision (ETag) matches; '*' requires an existing value"
+// @Param        If-None-Match header string false "'*' only sets the value if it does not exist yet"
+// @Param        ttl_in_s query int false "the value expires after the given seconds; expired values behave as missing"
+// @Param        expires_at query int false "unix timestamp in s at which the value expires; ignored if ttl_in_s is set"
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      413 "the value exceeds max_value_bytes of the quota"
+// @Failure      429 "the write exceeds max_variables or max_total_bytes of the quota"
 // @Failure      500
 // @Router       /values/{key} [put]
 func (this *Values) Set(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.PUT("/values/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.PUT("/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
 		}
 
-		var value interface{}
-		err = json.NewDecoder(request.Body).Decode(&value)
+		variable, err := getValueVariable(request, key)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		variable.ExpiresAt, err = getExpiresAt(request, 0)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
 
-		err = ctrl.Set(token.GetUserId(), model.Variable{
-			Key:                 key,
-			Value:               value,
-			ProcessDefinitionId: "",
-			ProcessInstanceId:   "",
-		})
+		err = ctrl.CompareAndSet(request.Context(), token.GetUserId(), variable, getPrecondition(request))
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
 		writer.WriteHeader(http.StatusNoContent)
 	})
 }
 
+// Patch godoc
+// @Summary      patch the value associated with the given key
+// @Description  patch the value associated with the given key; the patch is applied atomically to the stored value (a missing value is patched as null)
+// @Tags         values
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        key path string true "key of value"
+// @Param        message body Anything true "JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) depending on the Content-Type header"
+// @Param        If-Match header string false "only patch if the current revision (ETag) matches; '*' requires an existing value"
+// @Param        If-None-Match header string false "'*' only patches if the value does not exist yet"
+// @Success      200 {object} Anything
+// @Header       200 {string} ETag "revision of the value"
+// @Failure      400
+// @Failure      409 "the patch can not be applied to the stored value"
+// @Failure      412
+// @Failure      415
+// @Failure      500
+// @Router       /values/{key} [patch]
+func (this *Values) Patch(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.PATCH("/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		key := getKeyParam(params)
+		if key == "" {
+			http.Error(writer, "missing key", http.StatusBadRequest)
+			return
+		}
+		patch, err := getPatch(request)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		result, revision, err := ctrl.Patch(request.Context(), token.GetUserId(), key, patch, getPrecondition(request))
+		if err != nil {
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
+			return
+		}
+
+		setETag(writer, revision)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result.Value)
+	})
+}
+
+// Increment godoc
+// @Summary      increment the value associated with the given key
+// @Description  atomically adds delta to the numeric value associated with the given key; a missing value is created with delta as value
+// @Tags         values
+// @Accept       json
+// @Produce      json
+// @Param        key path string true "key of value"
+// @Param        message body model.IncrementRequest true "model.IncrementRequest; a negative delta decrements the value"
+// @Success      200 {number} number "the new value"
+// @Header       200 {string} ETag "revision of the value"
+// @Failure      400
+// @Failure      409 "the stored value is not numeric"
+// @Failure      500
+// @Router       /increment/values/{key} [post]
+func (this *Values) Increment(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.POST("/increment/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		key := getKeyParam(params)
+		if key == "" {
+			http.Error(writer, "missing key", http.StatusBadRequest)
+			return
+		}
+
+		msg := model.IncrementRequest{}
+		err = json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		result, revision, err := ctrl.Increment(request.Context(), token.GetUserId(), key, msg.Delta)
+		if err != nil {
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
+			return
+		}
+
+		setETag(writer, revision)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
 // Delete godoc
 // @Summary      delete the value associated with the given key
 // @Description  delete the value associated with the given key
@@ -122,18 +250,18 @@ func (this *Values) Set(config configuration.Config, router *httprouter.Router,
 // @Failure      500
 // @Router       /values/{key} [delete]
 func (this *Values) Delete(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.DELETE("/values/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.DELETE("/values/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
 		}
-		err = ctrl.Delete(token.GetUserId(), key)
+		err = ctrl.Delete(request.Context(), token.GetUserId(), key)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
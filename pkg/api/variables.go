@@ -24,7 +24,6 @@ import (
 	"github.com/julienschmidt/httprouter"
 	"net/http"
 	"strconv"
-	"strings"
 )
 
 func init() {
@@ -39,11 +38,22 @@ type Variables struct{}
 // @Tags         variables
 // @Param        limit query integer false "limits size of result; 0 means unlimited"
 // @Param        offset query integer false "offset to be used in combination with limit"
-// @Param        sort query string false "describes the sorting in the form of key.asc"
+// @Param        sort query string false "comma separated sort fields with .asc (default) or .desc suffix, e.g. process_instance_id.asc,unix_timestamp_in_s.desc; fields: key, unix_timestamp_in_s (alias timestamp), process_definition_id, process_instance_id and value<json pointer> for an element of the value, e.g. value/status.desc; ties are ordered by key; default key.asc"
+// @Param        key query []string false "only variables with one of these keys; may be repeated" collectionFormat(multi)
+// @Param        key_prefix query string false "only variables with keys starting with this prefix (case-sensitive)"
+// @Param        key_glob query string false "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\' escapes the next character"
+// @Param        key_regex query string false "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\d, \\s, \\w and their negations); case-insensitive unless key_regex_case_sensitive is set"
+// @Param        key_regex_case_sensitive query boolean false "match key_regex case-sensitive"
 // @Param        process_instance_id query string false "filter by process instance id"
 // @Param        process_definition_id query string false "filter by process definition id"
+// @Param        value_filter query []string false "json encoded model.ValueFilter, e.g. {\"path\":\"/status\",\"operator\":\"eq\",\"value\":\"failed\"}; may be repeated, all filters must match" collectionFormat(multi)
+// @Param        updated_after query integer false "only variables with unix_timestamp_in_s greater than this unix timestamp in s"
+// @Param        updated_before query integer false "only variables with unix_timestamp_in_s less than this unix timestamp in s"
+// @Param        cursor query string false "X-Next-Cursor header of the previous page; lists the variables after the previous page; may not be combined with offset or changed sort; not supported for sorts by value elements"
 // @Produce      json
 // @Success      200 {array} model.VariableWithUnixTimestamp
+// @Header       200 {string} X-Next-Cursor "cursor of the next page; only set if limit is set, the page is full and the sort supports cursors"
+// @Header       200 {string} Link "link to the next page (rel=next), if X-Next-Cursor is set"
 // @Failure      400
 // @Failure      500
 // @Router       /variables [get]
@@ -79,14 +89,30 @@ func (this *Variables) List(config configuration.Config, router *httprouter.Rout
 
 		query.ProcessInstanceId = request.URL.Query().Get("process_instance_id")
 		query.ProcessDefinitionId = request.URL.Query().Get("process_definition_id")
-		query.KeyRegex = request.URL.Query().Get("key_regex")
+		err = getKeyFilters(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.ValueFilters, err = getValueFilters(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = getTimestampRange(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.Cursor = request.URL.Query().Get("cursor")
 
-		result, err := ctrl.List(token.GetUserId(), query)
+		result, err := ctrl.List(request.Context(), token.GetUserId(), query)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
 			return
 		}
 
+		setNextCursor(writer, request, model.NextVariablesCursor(query, result))
 		writer.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(writer).Encode(result)
 
@@ -97,8 +123,16 @@ func (this *Variables) List(config configuration.Config, router *httprouter.Rout
 // @Summary      counts variables
 // @Description  counts variables
 // @Tags         variables, count
+// @Param        key query []string false "only variables with one of these keys; may be repeated" collectionFormat(multi)
+// @Param        key_prefix query string false "only variables with keys starting with this prefix (case-sensitive)"
+// @Param        key_glob query string false "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\' escapes the next character"
+// @Param        key_regex query string false "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\d, \\s, \\w and their negations); case-insensitive unless key_regex_case_sensitive is set"
+// @Param        key_regex_case_sensitive query boolean false "match key_regex case-sensitive"
 // @Param        process_instance_id query string false "filter by process instance id"
 // @Param        process_definition_id query string false "filter by process definition id"
+// @Param        value_filter query []string false "json encoded model.ValueFilter, e.g. {\"path\":\"/status\",\"operator\":\"eq\",\"value\":\"failed\"}; may be repeated, all filters must match" collectionFormat(multi)
+// @Param        updated_after query integer false "only variables with unix_timestamp_in_s greater than this unix timestamp in s"
+// @Param        updated_before query integer false "only variables with unix_timestamp_in_s less than this unix timestamp in s"
 // @Produce      json
 // @Success      200 {object} model.Count
 // @Failure      400
@@ -116,11 +150,25 @@ func (this *Variables) Count(config configuration.Config, router *httprouter.Rou
 
 		query.ProcessInstanceId = request.URL.Query().Get("process_instance_id")
 		query.ProcessDefinitionId = request.URL.Query().Get("process_definition_id")
-		query.KeyRegex = request.URL.Query().Get("key_regex")
+		err = getKeyFilters(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.ValueFilters, err = getValueFilters(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = getTimestampRange(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		result, err := ctrl.Count(token.GetUserId(), query)
+		result, err := ctrl.Count(request.Context(), token.GetUserId(), query)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
 			return
 		}
 
@@ -130,15 +178,98 @@ func (this *Variables) Count(config configuration.Config, router *httprouter.Rou
 	})
 }
 
+// Browse godoc
+// @Summary      lists variables like a folder tree
+// @Description  lists the variables with keys starting with prefix and without delimiter after the prefix, and the common prefixes of the other variables (the key up to and including the first delimiter after the prefix), e.g. prefix=plant1/&delimiter=/ lists the variable plant1/name and the common prefix plant1/line3/; limit and offset apply to variables and common prefixes in key order
+// @Tags         variables
+// @Param        prefix query string false "only variables with keys starting with this prefix (case-sensitive); alias of key_prefix"
+// @Param        delimiter query string false "groups keys containing the delimiter after the prefix into common prefixes, usually /; no grouping if empty"
+// @Param        limit query integer false "limits the number of variables and common prefixes; 0 means unlimited"
+// @Param        offset query integer false "offset to be used in combination with limit"
+// @Param        key query []string false "only variables with one of these keys; may be repeated" collectionFormat(multi)
+// @Param        key_glob query string false "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\' escapes the next character"
+// @Param        key_regex query string false "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\d, \\s, \\w and their negations); case-insensitive unless key_regex_case_sensitive is set"
+// @Param        key_regex_case_sensitive query boolean false "match key_regex case-sensitive"
+// @Param        process_instance_id query string false "filter by process instance id"
+// @Param        process_definition_id query string false "filter by process definition id"
+// @Param        value_filter query []string false "json encoded model.ValueFilter, e.g. {\"path\":\"/status\",\"operator\":\"eq\",\"value\":\"failed\"}; may be repeated, all filters must match" collectionFormat(multi)
+// @Param        updated_after query integer false "only variables with unix_timestamp_in_s greater than this unix timestamp in s"
+// @Param        updated_before query integer false "only variables with unix_timestamp_in_s less than this unix timestamp in s"
+// @Produce      json
+// @Success      200 {object} model.VariablesListing
+// @Failure      400
+// @Failure      500
+// @Router       /browse/variables [get]
+func (this *Variables) Browse(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/browse/variables", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		query := model.VariablesQueryOptions{}
+		limit := request.URL.Query().Get("limit")
+		if limit != "" {
+			query.Limit, err = strconv.Atoi(limit)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		offset := request.URL.Query().Get("offset")
+		if offset != "" {
+			query.Offset, err = strconv.Atoi(offset)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		query.ProcessInstanceId = request.URL.Query().Get("process_instance_id")
+		query.ProcessDefinitionId = request.URL.Query().Get("process_definition_id")
+		err = getKeyFilters(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if prefix := request.URL.Query().Get("prefix"); prefix != "" {
+			query.KeyPrefix = prefix
+		}
+		query.ValueFilters, err = getValueFilters(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = getTimestampRange(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := ctrl.Browse(request.Context(), token.GetUserId(), query, request.URL.Query().Get("delimiter"))
+		if err != nil {
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
 // Get godoc
 // @Summary      returns the variable associated with the given key
 // @Description  returns the variable associated with the given key
 // @Tags         variables
 // @Param        key path string true "key of variable/value"
+// @Param        at query integer false "unix timestamp in seconds; returns the variable as it was stored at this time (requires history_enabled)"
 // @Produce      json
 // @Success      200 {object} model.VariableWithUnixTimestamp
+// @Header       200 {string} ETag "revision of the variable; missing if the variable does not exist"
 // @Failure      400
 // @Failure      500
+// @Failure      501 "history is disabled"
 // @Router       /variables/{key} [get]
 func (this *Variables) Get(config configuration.Config, router *httprouter.Router, ctrl Controller) {
 	router.GET("/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
@@ -147,18 +278,31 @@ func (this *Variables) Get(config configuration.Config, router *httprouter.Route
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := strings.TrimPrefix(params.ByName("key"), "/")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing id", http.StatusBadRequest)
 			return
 		}
 
-		result, err := ctrl.Get(token.GetUserId(), key)
+		var result model.VariableWithUnixTimestamp
+		var revision int64
+		if at := request.URL.Query().Get("at"); at != "" {
+			var timestamp int64
+			timestamp, err = strconv.ParseInt(at, 10, 64)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+			result, revision, err = ctrl.GetAt(request.Context(), token.GetUserId(), key, timestamp)
+		} else {
+			result, revision, err = ctrl.GetWithRevision(request.Context(), token.GetUserId(), key)
+		}
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
 			return
 		}
 
+		setETag(writer, revision)
 		writer.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(writer).Encode(result)
 	})
@@ -171,18 +315,25 @@ func (this *Variables) Get(config configuration.Config, router *httprouter.Route
 // @Accept       json
 // @Param        key path string true "key of variable/value"
 // @Param        message body model.Variable true "model.Variable"
+// @Param        If-Match header string false "only set if the current revision (ETag) matches; '*' requires an existing value"
+// @Param        If-None-Match header string false "'*' only sets the value if it does not exist yet"
+// @Param        ttl_in_s query int false "the variable expires after the given seconds; expired variables behave as missing"
+// @Param        expires_at query int false "unix timestamp in s at which the variable expires; overwrites the expires_at of the body; ignored if ttl_in_s is set"
 // @Success      204
 // @Failure      400
+// @Failure      412
+// @Failure      413 "the value exceeds max_value_bytes of the quota"
+// @Failure      429 "the write exceeds max_variables or max_total_bytes of the quota"
 // @Failure      500
 // @Router       /variables/{key} [put]
 func (this *Variables) Set(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.PUT("/variables/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.PUT("/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
@@ -190,17 +341,22 @@ func (this *Variables) Set(config configuration.Config, router *httprouter.Route
 		msg := model.Variable{}
 		err = json.NewDecoder(request.Body).Decode(&msg)
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusBadRequest)
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
 			return
 		}
 		if msg.Key != key {
 			http.Error(writer, "path.key != body.key", http.StatusBadRequest)
 			return
 		}
+		msg.ExpiresAt, err = getExpiresAt(request, msg.ExpiresAt)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
 
-		err = ctrl.Set(token.GetUserId(), msg)
+		err = ctrl.CompareAndSet(request.Context(), token.GetUserId(), msg, getPrecondition(request))
 		if err != nil {
-			http.Error(writer, err.Error(), http.StatusInternalServerError)
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
 			return
 		}
 
@@ -208,6 +364,158 @@ func (this *Variables) Set(config configuration.Config, router *httprouter.Route
 	})
 }
 
+// Patch godoc
+// @Summary      patch the value of the variable associated with the given key
+// @Description  patch the value of the variable associated with the given key; the patch is applied atomically to the stored variable (a missing variable is patched as null)
+// @Tags         variables
+// @Accept       application/merge-patch+json
+// @Accept       application/json-patch+json
+// @Produce      json
+// @Param        key path string true "key of variable"
+// @Param        message body Anything true "JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) depending on the Content-Type header"
+// @Param        If-Match header string false "only patch if the current revision (ETag) matches; '*' requires an existing variable"
+// @Param        If-None-Match header string false "'*' only patches if the variable does not exist yet"
+// @Success      200 {object} model.VariableWithUnixTimestamp
+// @Header       200 {string} ETag "revision of the variable"
+// @Failure      400
+// @Failure      409 "the patch can not be applied to the stored variable"
+// @Failure      412
+// @Failure      415
+// @Failure      500
+// @Router       /variables/{key} [patch]
+func (this *Variables) Patch(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.PATCH("/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		key := getKeyParam(params)
+		if key == "" {
+			http.Error(writer, "missing key", http.StatusBadRequest)
+			return
+		}
+		patch, err := getPatch(request)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		result, revision, err := ctrl.Patch(request.Context(), token.GetUserId(), key, patch, getPrecondition(request))
+		if err != nil {
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
+			return
+		}
+
+		setETag(writer, revision)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// History godoc
+// @Summary      returns the history of the variable associated with the given key
+// @Description  returns the retained versions of the variable, newest first (requires history_enabled)
+// @Tags         variables, history
+// @Param        key path string true "key of variable"
+// @Param        limit query integer false "limits size of result; 0 means unlimited"
+// @Param        offset query integer false "offset to be used in combination with limit"
+// @Produce      json
+// @Success      200 {array} model.VariableVersion
+// @Failure      400
+// @Failure      500
+// @Failure      501 "history is disabled"
+// @Router       /history/variables/{key} [get]
+func (this *Variables) History(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.GET("/history/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		key := getKeyParam(params)
+		if key == "" {
+			http.Error(writer, "missing id", http.StatusBadRequest)
+			return
+		}
+		query := model.HistoryQueryOptions{}
+		limit := request.URL.Query().Get("limit")
+		if limit != "" {
+			query.Limit, err = strconv.Atoi(limit)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		offset := request.URL.Query().Get("offset")
+		if offset != "" {
+			query.Offset, err = strconv.Atoi(offset)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		result, err := ctrl.History(request.Context(), token.GetUserId(), key, query)
+		if err != nil {
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
+// Restore godoc
+// @Summary      restore a version of the variable associated with the given key
+// @Description  writes the version with the given revision from the history as new version of the variable (requires history_enabled)
+// @Tags         variables, history
+// @Accept       json
+// @Produce      json
+// @Param        key path string true "key of variable"
+// @Param        message body model.RestoreRequest true "revision to restore"
+// @Param        If-Match header string false "only restore if the current revision (ETag) matches; '*' requires an existing variable"
+// @Param        If-None-Match header string false "'*' only restores if the variable does not exist"
+// @Success      200 {object} model.VariableWithUnixTimestamp
+// @Header       200 {string} ETag "new revision of the variable"
+// @Failure      400
+// @Failure      404 "the revision is not retained in the history"
+// @Failure      412
+// @Failure      500
+// @Failure      501 "history is disabled"
+// @Router       /restore/variables/{key} [post]
+func (this *Variables) Restore(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.POST("/restore/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		key := getKeyParam(params)
+		if key == "" {
+			http.Error(writer, "missing key", http.StatusBadRequest)
+			return
+		}
+		msg := model.RestoreRequest{}
+		err = json.NewDecoder(request.Body).Decode(&msg)
+		if err != nil {
+			http.Error(writer, err.Error(), getBodyErrorStatusCode(err))
+			return
+		}
+
+		result, revision, err := ctrl.Restore(request.Context(), token.GetUserId(), key, msg.Revision, getPrecondition(request))
+		if err != nil {
+			http.Error(writer, err.Error(), getWriteErrorStatusCode(err))
+			return
+		}
+
+		setETag(writer, revision)
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
+
 // Delete godoc
 // @Summary      delete the variables associated with the given key
 // @Description  delete the variables associated with the given key
@@ -218,18 +526,18 @@ func (this *Variables) Set(config configuration.Config, router *httprouter.Route
 // @Failure      500
 // @Router       /variables/{key} [delete]
 func (this *Variables) Delete(config configuration.Config, router *httprouter.Router, ctrl Controller) {
-	router.DELETE("/variables/:key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+	router.DELETE("/variables/*key", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
 		token, err := jwt.GetParsedToken(request)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusUnauthorized)
 			return
 		}
-		key := params.ByName("key")
+		key := getKeyParam(params)
 		if key == "" {
 			http.Error(writer, "missing key", http.StatusBadRequest)
 			return
 		}
-		err = ctrl.Delete(token.GetUserId(), key)
+		err = ctrl.Delete(request.Context(), token.GetUserId(), key)
 		if err != nil {
 			http.Error(writer, err.Error(), http.StatusInternalServerError)
 			return
@@ -237,3 +545,89 @@ func (this *Variables) Delete(config configuration.Config, router *httprouter.Ro
 		writer.WriteHeader(http.StatusNoContent)
 	})
 }
+
+// DeleteVariables godoc
+// @Summary      delete the variables matching the filters
+// @Description  delete the variables of the requesting user matching all given filters; deleting all variables of the user without filters requires all=true; use dry_run to check which variables would be deleted
+// @Tags         variables
+// @Produce      json
+// @Param        key query []string false "only variables with one of these keys; may be repeated" collectionFormat(multi)
+// @Param        key_prefix query string false "only variables with keys starting with this prefix (case-sensitive)"
+// @Param        key_glob query string false "only variables with keys matching this glob pattern (case-sensitive); '*' matches any sequence, '?' a single character and '\\' escapes the next character"
+// @Param        key_regex query string false "only variables with keys matching this regular expression (unanchored; only the syntax shared by all databases: no flags, named groups, word boundaries or unicode classes, escapes only for punctuation, \\d, \\s, \\w and their negations); case-insensitive unless key_regex_case_sensitive is set"
+// @Param        key_regex_case_sensitive query boolean false "match key_regex case-sensitive"
+// @Param        process_instance_id query string false "filter by process instance id"
+// @Param        process_definition_id query string false "filter by process definition id"
+// @Param        value_filter query []string false "json encoded model.ValueFilter, e.g. {\"path\":\"/status\",\"operator\":\"eq\",\"value\":\"failed\"}; may be repeated, all filters must match" collectionFormat(multi)
+// @Param        updated_after query integer false "only variables with unix_timestamp_in_s greater than this unix timestamp in s"
+// @Param        updated_before query integer false "only variables with unix_timestamp_in_s less than this unix timestamp in s"
+// @Param        dry_run query boolean false "only count the matching variables without deleting them"
+// @Param        include_keys query boolean false "list the keys of the matching variables; only with dry_run"
+// @Param        all query boolean false "required to delete all variables of the user, if no filter is given"
+// @Success      200 {object} model.DeleteResult
+// @Failure      400
+// @Failure      500
+// @Router       /variables [delete]
+func (this *Variables) DeleteVariables(config configuration.Config, router *httprouter.Router, ctrl Controller) {
+	router.DELETE("/variables", func(writer http.ResponseWriter, request *http.Request, params httprouter.Params) {
+		token, err := jwt.GetParsedToken(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		options := model.DeleteOptions{}
+		dryRun := request.URL.Query().Get("dry_run")
+		if dryRun != "" {
+			options.DryRun, err = strconv.ParseBool(dryRun)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		includeKeys := request.URL.Query().Get("include_keys")
+		if includeKeys != "" {
+			options.IncludeKeys, err = strconv.ParseBool(includeKeys)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		all := request.URL.Query().Get("all")
+		if all != "" {
+			options.All, err = strconv.ParseBool(all)
+			if err != nil {
+				http.Error(writer, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+
+		query := model.VariablesQueryOptions{}
+		query.ProcessInstanceId = request.URL.Query().Get("process_instance_id")
+		query.ProcessDefinitionId = request.URL.Query().Get("process_definition_id")
+		err = getKeyFilters(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		query.ValueFilters, err = getValueFilters(request)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		err = getTimestampRange(request, &query)
+		if err != nil {
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := ctrl.DeleteVariables(request.Context(), token.GetUserId(), query, options)
+		if err != nil {
+			http.Error(writer, err.Error(), getReadErrorStatusCode(err))
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(writer).Encode(result)
+	})
+}
@@ -20,10 +20,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"reflect"
 	"regexp"
 	"runtime/debug"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -38,11 +40,32 @@ type Config struct {
 	EnableSwaggerUi   bool   `json:"enable_swagger_ui"`
 
 	DatabaseSelection string `json:"database_selection"`
+	DatabaseTimeout   string `json:"database_timeout"`
+	ReadinessTimeout  string `json:"readiness_timeout"`
+
+	HistoryEnabled     bool   `json:"history_enabled"`
+	HistoryMaxVersions int64  `json:"history_max_versions"`
+	HistoryMaxAge      string `json:"history_max_age"`
+
+	ExpirySweepInterval string `json:"expiry_sweep_interval"`
+
+	CacheSize int64  `json:"cache_size"`
+	CacheTtl  string `json:"cache_ttl"`
+
+	QuotaMaxVariables   int64 `json:"quota_max_variables"`
+	QuotaMaxValueBytes  int64 `json:"quota_max_value_bytes"`
+	QuotaMaxTotalBytes  int64 `json:"quota_max_total_bytes"`
+	MaxRequestBodyBytes int64 `json:"max_request_body_bytes"`
 
 	MongoUrl                 string `json:"mongo_url"`
 	MongoTable               string `json:"mongo_table"`
 	MongoVariablesCollection string `json:"mongo_variables_collection"`
+	MongoHistoryCollection   string `json:"mongo_history_collection"`
+	MongoShareCollection     string `json:"mongo_share_collection"`
+	MongoQuotaCollection     string `json:"mongo_quota_collection"`
+	MongoRevisionCollection  string `json:"mongo_revision_collection"`
 	PostgresConnString       string `json:"postgres_conn_string"`
+	BoltFile                 string `json:"bolt_file"`
 
 	LogLevel string       `json:"log_level"`
 	logger   *slog.Logger `json:"-"`
@@ -59,6 +82,10 @@ func Load(location string) (config Config, err error) {
 		return config, err
 	}
 	handleEnvironmentVars(&config)
+	err = config.validateDurations()
+	if err != nil {
+		return config, err
+	}
 	return config, nil
 }
 
@@ -128,6 +155,97 @@ func handleEnvironmentVars(config *Config) {
 
 var TimeNow = func() time.Time { return time.Now() }
 
+const defaultDatabaseTimeout = 10 * time.Second
+
+// GetDatabaseTimeout returns the parsed DatabaseTimeout, used as timeout for each database operation; defaults to 10s
+func (this *Config) GetDatabaseTimeout() time.Duration {
+	return this.getDuration("database_timeout")
+}
+
+const defaultReadinessTimeout = time.Second
+
+// GetReadinessTimeout returns the parsed ReadinessTimeout, the time the database may take to respond to the ping of /health/ready
+func (this *Config) GetReadinessTimeout() time.Duration {
+	return this.getDuration("readiness_timeout")
+}
+
+// GetHistoryMaxAge returns the parsed HistoryMaxAge, the duration versions are kept in the history; 0 is unlimited
+func (this *Config) GetHistoryMaxAge() time.Duration {
+	return this.getDuration("history_max_age")
+}
+
+const defaultExpirySweepInterval = time.Minute
+
+// GetExpirySweepInterval returns the parsed ExpirySweepInterval, the interval in which postgres, bolt and memory databases
+// delete expired variables (mongodb uses a ttl index); defaults to 1m
+func (this *Config) GetExpirySweepInterval() time.Duration {
+	return this.getDuration("expiry_sweep_interval")
+}
+
+const defaultCacheTtl = 10 * time.Second
+
+// GetCacheTtl returns the parsed CacheTtl, the duration variables are served from the cache
+// before they are read again from the database
+func (this *Config) GetCacheTtl() time.Duration {
+	return this.getDuration("cache_ttl")
+}
+
+type durationField struct {
+	value        string
+	defaultValue time.Duration
+}
+
+// durationFields returns the duration fields of the config by their json name
+func (this *Config) durationFields() map[string]durationField {
+	return map[string]durationField{
+		"database_timeout":      {value: this.DatabaseTimeout, defaultValue: defaultDatabaseTimeout},
+		"readiness_timeout":     {value: this.ReadinessTimeout, defaultValue: defaultReadinessTimeout},
+		"history_max_age":       {value: this.HistoryMaxAge, defaultValue: 0},
+		"expiry_sweep_interval": {value: this.ExpirySweepInterval, defaultValue: defaultExpirySweepInterval},
+		"cache_ttl":             {value: this.CacheTtl, defaultValue: defaultCacheTtl},
+	}
+}
+
+// validateDurations returns an error for the first invalid duration field, so misconfigurations fail at startup
+func (this *Config) validateDurations() error {
+	fields := this.durationFields()
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		_, err := parseDuration(name, fields[name].value, fields[name].defaultValue)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getDuration returns the parsed duration field; Load rejects invalid durations,
+// so the default is only used for invalid values of configs which are not loaded from a file
+func (this *Config) getDuration(name string) time.Duration {
+	field := this.durationFields()[name]
+	duration, err := parseDuration(name, field.value, field.defaultValue)
+	if err != nil {
+		this.GetLogger().Warn("invalid duration config; use default", "error", err, "default", field.defaultValue.String())
+		return field.defaultValue
+	}
+	return duration
+}
+
+// parseDuration parses value as time.Duration (e.g. "10s"); empty values return defaultValue.
+// durations must be positive, 0 is only valid for fields with default 0, where it means unlimited
+func parseDuration(name string, value string, defaultValue time.Duration) (time.Duration, error) {
+	if value == "" {
+		return defaultValue, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return defaultValue, fmt.Errorf("invalid %v config %q: %w", name, value, err)
+	}
+	if duration < 0 || (duration == 0 && defaultValue != 0) {
+		return defaultValue, fmt.Errorf("invalid %v config %q: must be positive", name, value)
+	}
+	return duration, nil
+}
+
 func (this *Config) GetLogger() *slog.Logger {
 	if this.logger == nil {
 		if this.Debug {
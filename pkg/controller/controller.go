@@ -17,61 +17,259 @@
 package controller
 
 import (
+	"context"
+	"fmt"
 	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
 	"github.com/SENERGY-Platform/process-io-api/pkg/controller/calculate"
 	"github.com/SENERGY-Platform/process-io-api/pkg/controller/metrics"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+	"slices"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
 type Database interface {
-	GetVariable(userId string, key string) (model.VariableWithUser, error)
-	SetVariable(variable model.VariableWithUser) error
-	DeleteVariable(userId string, key string) error
-	ListVariables(userId string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
-	DeleteVariablesOfProcessDefinition(definitionId string) error
-	DeleteVariablesOfProcessInstance(instanceId string) error
-	CountVariables(userId string, query model.VariablesQueryOptions) (model.Count, error)
+	GetVariable(ctx context.Context, userId string, key string) (model.VariableWithUser, error)
+	GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (model.VariableWithUser, error)
+	SetVariable(ctx context.Context, variable model.VariableWithUser) error
+	SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (newRevision int64, ok bool, err error) //ok is false if the stored revision is not revision
+	IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (model.VariableWithUser, error)
+	UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (model.VariableWithUser, error)
+	DeleteVariable(ctx context.Context, userId string, key string) error
+	ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
+	DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (int64, error)
+	DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (int64, error)
+	DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (int64, error)
+	CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (model.Count, error)
+	AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error
+	ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) ([]model.VariableVersion, error)
+	GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (model.VariableVersion, error)
+	GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (model.VariableVersion, error)
+	PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error
+	Transaction(ctx context.Context, f func(ctx context.Context) error) error
+	SetShare(ctx context.Context, share model.Share) error                                    //creates or replaces the share with the id of share
+	DeleteShare(ctx context.Context, ownerId string, id string) (bool, error)                 //false if the owner has no share with the id
+	ListShares(ctx context.Context, ownerId string) ([]model.Share, error)                    //shares of the owner, sorted by id
+	ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) //shares granted to the user or one of the groups, sorted by id
+	GetUsage(ctx context.Context, userId string) (model.Usage, error)                         //not expired variables of the user and the sum of their value sizes
+	GetQuota(ctx context.Context, userId string) (quota model.Quota, found bool, err error)   //found is false if the user has no quota override
+	SetQuota(ctx context.Context, quota model.Quota) error                                    //creates or replaces the quota of quota.UserId
+	DeleteQuota(ctx context.Context, userId string) error                                     //no error if the user has no quota override
 }
 
 func New(config configuration.Config, db Database) *Controller {
-	return &Controller{config: config, db: db, calc: calculate.New(), metrics: metrics.New()}
+	return NewWithMetrics(config, db, metrics.New())
+}
+
+// NewWithMetrics is New with metrics shared with other components, e.g. a cache wrapping db
+func NewWithMetrics(config configuration.Config, db Database, m *metrics.Metrics) *Controller {
+	return &Controller{config: config, db: db, calc: calculate.New(), metrics: m, historyMaxAge: config.GetHistoryMaxAge()}
 }
 
 type Controller struct {
-	config  configuration.Config
-	db      Database
-	calc    *calculate.Calculate
-	metrics *metrics.Metrics
+	config        configuration.Config
+	db            Database
+	calc          *calculate.Calculate
+	metrics       *metrics.Metrics
+	historyMaxAge time.Duration
+}
+
+// Ping checks if the database is reachable within the configured readiness timeout;
+// databases without database.Pinger implementation are always reachable
+func (this *Controller) Ping(ctx context.Context) error {
+	pinger, ok := this.db.(database.Pinger)
+	if !ok {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, this.config.GetReadinessTimeout())
+	defer cancel()
+	return pinger.Ping(ctx)
 }
 
 func (this *Controller) GetMetrics() *metrics.Metrics {
 	return this.metrics
 }
 
-func (this *Controller) List(userid string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
-	result, err = this.db.ListVariables(userid, query)
+// List lists the variables of the user and the variables shared with the user (see model.Share)
+func (this *Controller) List(ctx context.Context, userid string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	result, err = this.list(ctx, userid, query)
 	if err != nil {
 		return []model.VariableWithUnixTimestamp{}, err
 	}
+	for _, variable := range result {
+		this.metrics.LogReadSize(userid, variable.Variable)
+	}
+	return result, nil
+}
+
+// list is List without metrics
+func (this *Controller) list(ctx context.Context, userid string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	shares, err := this.getShares(ctx, userid)
+	if err != nil {
+		return nil, err
+	}
+	if len(shares) == 0 {
+		result, err = this.db.ListVariables(ctx, userid, query)
+	} else {
+		result, err = this.listWithShared(ctx, userid, query, shares)
+	}
+	if err != nil {
+		return nil, err
+	}
 	if result == nil {
 		result = []model.VariableWithUnixTimestamp{}
 	}
-	for _, variable := range result {
-		this.metrics.LogReadSize(userid, variable.Variable)
+	return result, nil
+}
+
+// browsePageSize limits the number of variables read by one database request of Browse
+const browsePageSize = 100
+
+// Browse lists the variables of the user and the variables shared with the user (see model.Share) matching the query like a folder tree:
+// variables with keys containing the delimiter after query.KeyPrefix are grouped into common prefixes
+// (the key up to and including the first delimiter after the prefix); an empty delimiter groups nothing.
+// limit and offset of the query apply to the combined variables and common prefixes in key order (byte-wise),
+// sort and cursor of the query are ignored.
+// the variables are read in pages in key order until limit and offset are reached; the variables of a common prefix,
+// which continue after the page, are skipped with a cursor following the common prefix
+func (this *Controller) Browse(ctx context.Context, userid string, query model.VariablesQueryOptions, delimiter string) (result model.VariablesListing, err error) {
+	limit, offset := query.Limit, query.Offset
+	query.Limit, query.Offset, query.Sort, query.Cursor = browsePageSize, 0, "", ""
+	result.Variables = []model.VariableWithUnixTimestamp{}
+	result.CommonPrefixes = []string{}
+	entries := 0
+	full := func() bool {
+		return limit > 0 && entries >= offset+limit
+	}
+	lastPrefix := ""
+	//visit adds the variable or its common prefix to the result until the limit is reached; returns the common prefix of the variable if it is new
+	visit := func(variable model.VariableWithUnixTimestamp) (prefix string) {
+		if full() {
+			return ""
+		}
+		rest := strings.TrimPrefix(variable.Key, query.KeyPrefix)
+		index := -1
+		if delimiter != "" {
+			index = strings.Index(rest, delimiter)
+		}
+		if index >= 0 {
+			prefix = query.KeyPrefix + rest[:index+len(delimiter)]
+			if prefix == lastPrefix {
+				return ""
+			}
+			lastPrefix = prefix
+		}
+		if entries >= offset {
+			if prefix != "" {
+				result.CommonPrefixes = append(result.CommonPrefixes, prefix)
+			} else {
+				this.metrics.LogReadSize(userid, variable.Variable)
+				result.Variables = append(result.Variables, variable)
+			}
+		}
+		entries++
+		return prefix
+	}
+	skipped := "" //key of the variables visited before the page, see browseAfterPrefix
+	for !full() {
+		page, err := this.list(ctx, userid, query)
+		if err != nil {
+			return result, err
+		}
+		last := len(page) < browsePageSize
+		query.Cursor = model.NextVariablesCursor(query, page)
+		for _, variable := range page {
+			if full() {
+				break
+			}
+			if variable.Key == skipped {
+				continue
+			}
+			prefix := visit(variable)
+			if prefix == "" || last || full() || !strings.HasPrefix(page[len(page)-1].Key, prefix) {
+				continue
+			}
+			//the rest of the page and following pages belong to the common prefix
+			end, ok := prefixEnd(prefix)
+			if !ok {
+				break
+			}
+			skipped, query.Cursor, err = this.browseAfterPrefix(ctx, userid, query, end, visit)
+			if err != nil {
+				return result, err
+			}
+			break
+		}
+		if last {
+			break
+		}
 	}
 	return result, nil
 }
 
-func (this *Controller) Count(userid string, query model.VariablesQueryOptions) (model.Count, error) {
-	return this.db.CountVariables(userid, query)
+// browseAfterPrefix visits the variables with the key end (see prefixEnd) and returns the key and the cursor to continue Browse after it
+func (this *Controller) browseAfterPrefix(ctx context.Context, userid string, query model.VariablesQueryOptions, end string, visit func(model.VariableWithUnixTimestamp) string) (skipped string, cursor string, err error) {
+	if len(query.Keys) == 0 || slices.Contains(query.Keys, end) {
+		endQuery := query
+		endQuery.Keys = []string{end}
+		endQuery.Limit, endQuery.Cursor = 0, ""
+		variables, err := this.list(ctx, userid, endQuery)
+		if err != nil {
+			return "", "", err
+		}
+		for _, variable := range variables {
+			visit(variable)
+		}
+	}
+	sort, err := query.GetSortKeys()
+	if err != nil {
+		return "", "", err
+	}
+	position, _ := model.NewVariablesCursor(sort, model.VariableWithUnixTimestamp{Variable: model.Variable{Key: end}})
+	return end, position.Encode(), nil
+}
+
+// prefixEnd returns the first key sorted (byte-wise) after all keys starting with the prefix;
+// ok is false if the last byte of the prefix can not be incremented to a valid utf-8 key
+func prefixEnd(prefix string) (end string, ok bool) {
+	last := prefix[len(prefix)-1]
+	if last >= utf8.RuneSelf-1 {
+		return "", false
+	}
+	return prefix[:len(prefix)-1] + string(last+1), true
+}
+
+// Count counts the variables of the user and the variables shared with the user (see model.Share)
+func (this *Controller) Count(ctx context.Context, userid string, query model.VariablesQueryOptions) (result model.Count, err error) {
+	shares, err := this.getShares(ctx, userid)
+	if err != nil {
+		return result, err
+	}
+	result, err = this.db.CountVariables(ctx, userid, query)
+	if err != nil || len(shares) == 0 {
+		return result, err
+	}
+	query.Limit, query.Offset, query.Sort, query.Cursor = 0, 0, "", ""
+	shared, err := this.listShared(ctx, query, shares)
+	result.Count += int64(len(shared))
+	return result, err
+}
+
+func (this *Controller) Get(ctx context.Context, userid string, key string) (res model.VariableWithUnixTimestamp, err error) {
+	res, _, err = this.GetWithRevision(ctx, userid, key)
+	return res, err
 }
 
-func (this *Controller) Get(userid string, key string) (res model.VariableWithUnixTimestamp, err error) {
+// GetWithRevision returns the variable and its revision; the revision is 0 if the variable does not exist or is calculated.
+// if the user has no variable with the key, a variable shared with the user is returned (see model.Share)
+func (this *Controller) GetWithRevision(ctx context.Context, userid string, key string) (res model.VariableWithUnixTimestamp, revision int64, err error) {
 	if strings.HasPrefix(key, calculate.Prefix) {
 		val, err := this.calc.Get(key)
 		if err != nil {
-			return res, err
+			return res, revision, err
 		}
 		res = model.VariableWithUnixTimestamp{
 			Variable: model.Variable{
@@ -81,41 +279,343 @@ func (this *Controller) Get(userid string, key string) (res model.VariableWithUn
 			UnixTimestampInS: configuration.TimeNow().Unix(),
 		}
 	} else {
-		variable, err := this.db.GetVariable(userid, key)
+		variable, err := this.db.GetVariable(ctx, userid, key)
 		if err != nil {
-			return res, err
+			return res, revision, err
+		}
+		if variable.Revision == 0 {
+			shares, err := this.getShares(ctx, userid)
+			if err != nil {
+				return res, revision, err
+			}
+			shared, _, err := this.getShared(ctx, key, shares)
+			if err != nil {
+				return res, revision, err
+			}
+			if shared.Revision > 0 {
+				variable = shared
+				variable.Owner = shared.UserId
+			}
 		}
 		res = variable.VariableWithUnixTimestamp
+		revision = variable.Revision
 	}
 	this.metrics.LogReadSize(userid, res.Variable)
 	return
 }
 
-func (this *Controller) Set(userid string, variable model.Variable) error {
+// GetAtPointer returns the element of the value referenced by the json pointer (RFC 6901) and the revision of the variable;
+// the element is selected by the database where possible, to only transfer the referenced part of the value.
+// if the user has no variable with the key, a variable shared with the user is read (see model.Share)
+func (this *Controller) GetAtPointer(ctx context.Context, userid string, key string, pointer string) (value interface{}, revision int64, err error) {
+	tokens, err := jsondoc.ParsePointer(pointer)
+	if err != nil {
+		return nil, 0, err
+	}
+	var variable model.VariableWithUser
+	if strings.HasPrefix(key, calculate.Prefix) {
+		variable.Key = key
+		variable.Value, err = this.calc.Get(key)
+		if err != nil {
+			return nil, 0, err
+		}
+		variable.Value, err = jsondoc.Normalize(variable.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		variable.Value, err = jsondoc.Get(variable.Value, tokens)
+	} else {
+		variable, err = this.db.GetVariableAtPointer(ctx, userid, key, tokens)
+		if err == nil && variable.Revision == 0 {
+			var shares map[string][]model.Share
+			var shared model.VariableWithUser
+			shares, err = this.getShares(ctx, userid)
+			if err == nil {
+				shared, _, err = this.getShared(ctx, key, shares)
+			}
+			if err == nil && shared.Revision > 0 {
+				variable, err = this.db.GetVariableAtPointer(ctx, shared.UserId, key, tokens)
+			}
+		}
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	this.metrics.LogReadSize(userid, variable.Variable)
+	return variable.Value, variable.Revision, nil
+}
+
+// Set writes the variable of the user or the variable shared with the user, see getWriteOwner;
+// returns model.ErrValueTooLarge or model.ErrQuotaExceeded if the write would exceed the quota of the owner (see checkQuota)
+func (this *Controller) Set(ctx context.Context, userid string, variable model.Variable) error {
+	if variable.ExpiresAt < 0 {
+		return model.ErrInvalidExpiry
+	}
+	err := variable.ValidateIds()
+	if err != nil {
+		return err
+	}
+	err = variable.ValidateBinary()
+	if err != nil {
+		return err
+	}
+	owner, err := this.getWriteOwner(ctx, userid, variable.Key)
+	if err != nil {
+		return err
+	}
+	err = this.checkQuota(ctx, owner, variable)
+	if err != nil {
+		return err
+	}
+	this.metrics.LogWriteSize(userid, variable)
+	if !this.config.HistoryEnabled {
+		return this.db.SetVariable(ctx, this.withUser(owner, variable))
+	}
+	//the history needs the revision of the written version, which is only returned by UpdateVariable
+	_, err = this.update(ctx, userid, owner, variable.Key, func(current model.VariableWithUser) (model.VariableWithUser, error) {
+		return this.withUser(owner, variable), nil
+	})
+	return err
+}
+
+// maxCompareAndSetAttempts limits the retries of CompareAndSet if the variable is changed between check and write
+const maxCompareAndSetAttempts = 10
+
+// CompareAndSet sets the variable, if the stored variable matches the precondition;
+// returns model.ErrPreconditionFailed otherwise
+func (this *Controller) CompareAndSet(ctx context.Context, userid string, variable model.Variable, precondition model.Precondition) error {
+	if precondition.IsEmpty() {
+		return this.Set(ctx, userid, variable)
+	}
+	if variable.ExpiresAt < 0 {
+		return model.ErrInvalidExpiry
+	}
+	err := variable.ValidateIds()
+	if err != nil {
+		return err
+	}
+	err = variable.ValidateBinary()
+	if err != nil {
+		return err
+	}
+	owner, err := this.getWriteOwner(ctx, userid, variable.Key)
+	if err != nil {
+		return err
+	}
+	err = this.checkQuota(ctx, owner, variable)
+	if err != nil {
+		return err
+	}
 	this.metrics.LogWriteSize(userid, variable)
-	return this.db.SetVariable(model.VariableWithUser{
+	return this.historyTransaction(ctx, func(ctx context.Context) error {
+		for i := 0; i < maxCompareAndSetAttempts; i++ {
+			current, err := this.db.GetVariable(ctx, owner, variable.Key)
+			if err != nil {
+				return err
+			}
+			ok, err := precondition.Check(current.Revision)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return model.ErrPreconditionFailed
+			}
+			written := this.withUser(owner, variable)
+			revision, ok, err := this.db.SetVariableIfRevision(ctx, written, current.Revision)
+			if err != nil {
+				return err
+			}
+			if ok {
+				written.Revision = revision
+				return this.addVersion(ctx, userid, written)
+			}
+		}
+		return model.ErrPreconditionFailed
+	})
+}
+
+// Increment atomically adds delta to the numeric value of the variable and returns the new value and revision;
+// missing variables are created with delta as value; the expiry of existing variables is kept
+func (this *Controller) Increment(ctx context.Context, userid string, key string, delta float64) (value interface{}, revision int64, err error) {
+	err = model.ValidateId(key)
+	if err != nil {
+		return nil, 0, err
+	}
+	owner, err := this.getWriteOwner(ctx, userid, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	current, err := this.db.GetVariable(ctx, owner, key)
+	if err != nil {
+		return nil, 0, err
+	}
+	incremented := current.Variable
+	incremented.Value = delta
+	if number, ok := current.Value.(float64); ok && current.Revision > 0 {
+		incremented.Value = number + delta
+	}
+	err = this.checkQuota(ctx, owner, incremented)
+	if err != nil {
+		return nil, 0, err
+	}
+	var variable model.VariableWithUser
+	err = this.historyTransaction(ctx, func(ctx context.Context) (err error) {
+		variable, err = this.db.IncrementVariable(ctx, this.withUser(owner, model.Variable{Key: key}), delta)
+		if err != nil {
+			return err
+		}
+		return this.addVersion(ctx, userid, variable)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	this.metrics.LogWriteSize(userid, variable.Variable)
+	return variable.Value, variable.Revision, nil
+}
+
+// Patch applies the patch to the stored value, if the stored variable matches the precondition;
+// the patch is applied to the current state of the variable in the database, to not overwrite concurrent changes
+func (this *Controller) Patch(ctx context.Context, userid string, key string, patch model.Patch, precondition model.Precondition) (res model.VariableWithUnixTimestamp, revision int64, err error) {
+	err = model.ValidateId(key)
+	if err != nil {
+		return res, revision, err
+	}
+	apply, err := jsondoc.NewPatch(patch)
+	if err != nil {
+		return res, revision, err
+	}
+	owner, err := this.getWriteOwner(ctx, userid, key)
+	if err != nil {
+		return res, revision, err
+	}
+	variable, err := this.update(ctx, userid, owner, key, func(current model.VariableWithUser) (model.VariableWithUser, error) {
+		ok, err := precondition.Check(current.Revision)
+		if err != nil {
+			return current, err
+		}
+		if !ok {
+			return current, model.ErrPreconditionFailed
+		}
+		if current.IsBinary() {
+			return current, fmt.Errorf("%w: binary values can not be patched", model.ErrPatchFailed)
+		}
+		value, err := jsondoc.Normalize(current.Value)
+		if err != nil {
+			return current, err
+		}
+		current.Value, err = apply(value)
+		if err != nil {
+			return current, err
+		}
+		current.UnixTimestampInS = configuration.TimeNow().Unix()
+		return current, nil
+	})
+	if err != nil {
+		return res, revision, err
+	}
+	this.metrics.LogWriteSize(userid, variable.Variable)
+	if owner != userid {
+		variable.Owner = owner
+	}
+	return variable.VariableWithUnixTimestamp, variable.Revision, nil
+}
+
+// update calls db.UpdateVariable for the variable of the owner, checks the result against the quota of the owner
+// and adds the version written by the writer to the history
+func (this *Controller) update(ctx context.Context, writer string, owner string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (variable model.VariableWithUser, err error) {
+	quota, err := this.getQuotaCheck(ctx, owner)
+	if err != nil {
+		return variable, err
+	}
+	err = this.historyTransaction(ctx, func(ctx context.Context) (err error) {
+		variable, err = this.db.UpdateVariable(ctx, owner, key, func(current model.VariableWithUser) (model.VariableWithUser, error) {
+			result, err := update(current)
+			if err != nil {
+				return result, err
+			}
+			return result, quota.check(current, result.Variable)
+		})
+		if err != nil {
+			return err
+		}
+		return this.addVersion(ctx, writer, variable)
+	})
+	return variable, err
+}
+
+func (this *Controller) withUser(userid string, variable model.Variable) model.VariableWithUser {
+	return model.VariableWithUser{
 		VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
 			Variable:         variable,
 			UnixTimestampInS: configuration.TimeNow().Unix(),
 		},
 		UserId: userid,
-	})
+	}
+}
+
+func (this *Controller) Delete(ctx context.Context, userid string, key string) error {
+	return this.db.DeleteVariable(ctx, userid, key)
+}
+
+// DeleteVariables deletes the variables of the user matching the filters of the query;
+// limit, offset, sort and cursor of the query are ignored.
+// returns model.ErrMissingDeleteFilter if the query has no filters and options.All is not set
+func (this *Controller) DeleteVariables(ctx context.Context, userid string, query model.VariablesQueryOptions, options model.DeleteOptions) (result model.DeleteResult, err error) {
+	query.Limit, query.Offset, query.Sort, query.Cursor = 0, 0, "", ""
+	if !options.DryRun && !options.All && !query.HasFilters() {
+		return result, model.ErrMissingDeleteFilter
+	}
+	if !options.DryRun {
+		result.Count, err = this.db.DeleteVariables(ctx, userid, query)
+		return result, err
+	}
+	result.DryRun = true
+	if !options.IncludeKeys {
+		count, err := this.db.CountVariables(ctx, userid, query)
+		result.Count = count.Count
+		return result, err
+	}
+	list, err := this.db.ListVariables(ctx, userid, query)
+	if err != nil {
+		return result, err
+	}
+	for _, variable := range list {
+		result.Keys = append(result.Keys, variable.Key)
+	}
+	result.Count = int64(len(result.Keys))
+	return result, nil
 }
 
-func (this *Controller) Delete(userid string, key string) error {
-	return this.db.DeleteVariable(userid, key)
+func (this *Controller) Bulk(ctx context.Context, userid string, bulk model.BulkRequest) (result model.BulkResponse, err error) {
+	if !bulk.Atomic {
+		return this.bulk(ctx, userid, bulk)
+	}
+	err = this.db.Transaction(ctx, func(ctx context.Context) error {
+		result, err = this.bulk(ctx, userid, bulk)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-func (this *Controller) Bulk(userid string, bulk model.BulkRequest) (result model.BulkResponse, err error) {
-	for _, variable := range bulk.Set {
-		err = this.Set(userid, variable)
+func (this *Controller) bulk(ctx context.Context, userid string, bulk model.BulkRequest) (result model.BulkResponse, err error) {
+	for _, item := range bulk.Set {
+		if item.TtlInS != 0 {
+			item.ExpiresAt, err = model.ExpiresAtForTtl(configuration.TimeNow().Unix(), item.TtlInS)
+			if err != nil {
+				return result, err
+			}
+		}
+		err = this.CompareAndSet(ctx, userid, item.Variable, item.Precondition)
 		if err != nil {
 			return result, err
 		}
 	}
 	for _, key := range bulk.Get {
 		var variable model.VariableWithUnixTimestamp
-		variable, err = this.Get(userid, key)
+		variable, err = this.Get(ctx, userid, key)
 		if err != nil {
 			return result, err
 		}
@@ -124,10 +624,20 @@ func (this *Controller) Bulk(userid string, bulk model.BulkRequest) (result mode
 	return result, nil
 }
 
-func (this *Controller) DeleteProcessDefinition(userid string, definitionId string) error {
-	return this.db.DeleteVariablesOfProcessDefinition(definitionId)
+func (this *Controller) DeleteProcessDefinition(ctx context.Context, userid string, definitionId string) (result model.DeleteResult, err error) {
+	err = model.ValidateId(definitionId)
+	if err != nil {
+		return result, err
+	}
+	result.Count, err = this.db.DeleteVariablesOfProcessDefinition(ctx, definitionId)
+	return result, err
 }
 
-func (this *Controller) DeleteProcessInstance(userid string, instanceId string) error {
-	return this.db.DeleteVariablesOfProcessInstance(instanceId)
+func (this *Controller) DeleteProcessInstance(ctx context.Context, userid string, instanceId string) (result model.DeleteResult, err error) {
+	err = model.ValidateId(instanceId)
+	if err != nil {
+		return result, err
+	}
+	result.Count, err = this.db.DeleteVariablesOfProcessInstance(ctx, instanceId)
+	return result, err
 }
@@ -0,0 +1,164 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// exportBatchSize limits the number of variables held in memory during an export
+const exportBatchSize = 1000
+
+// Export calls f for every variable of the user, ordered by key;
+// the variables are read in pages by cursor, to neither skip nor repeat variables if keys are added or removed during the export
+func (this *Controller) Export(ctx context.Context, userid string, f func(variable model.VariableWithUnixTimestamp) error) error {
+	query := model.VariablesQueryOptions{
+		Limit: exportBatchSize,
+		Sort:  "key.asc",
+	}
+	for {
+		list, err := this.db.ListVariables(ctx, userid, query)
+		if err != nil {
+			return err
+		}
+		for _, variable := range list {
+			this.metrics.LogReadSize(userid, variable.Variable)
+			err = f(variable)
+			if err != nil {
+				return err
+			}
+		}
+		query.Cursor = model.NextVariablesCursor(query, list)
+		if query.Cursor == "" {
+			return nil
+		}
+	}
+}
+
+var errImportSkip = errors.New("skip imported variable")
+
+// Import writes the variables for the user and handles existing keys according to the conflict policy;
+// timestamps of the imported variables are kept, variables without timestamp get the time of the import.
+// with model.ImportConflictFail, all keys are checked before the first write and no variable is imported if any key exists;
+// if the database supports transactions, the import runs in a transaction and a failed write rolls back the already imported variables.
+// without transaction support (mongodb without replica set), a variable created concurrently or a failed write
+// still fails the import with already imported variables kept.
+func (this *Controller) Import(ctx context.Context, userid string, variables []model.VariableWithUnixTimestamp, conflict string) (result model.ImportSummary, err error) {
+	result = model.ImportSummary{Created: []string{}, Updated: []string{}, Skipped: []string{}}
+	switch conflict {
+	case model.ImportConflictOverwrite, model.ImportConflictSkip, model.ImportConflictFail:
+	default:
+		return result, fmt.Errorf("%w: unknown conflict policy %v", model.ErrInvalidImport, conflict)
+	}
+	keys := map[string]bool{}
+	for _, variable := range variables {
+		if variable.Key == "" {
+			return result, fmt.Errorf("%w: missing key", model.ErrInvalidImport)
+		}
+		if keys[variable.Key] {
+			return result, fmt.Errorf("%w: duplicate key %v", model.ErrInvalidImport, variable.Key)
+		}
+		if variable.ExpiresAt < 0 {
+			return result, model.ErrInvalidExpiry
+		}
+		if err := variable.ValidateIds(); err != nil {
+			return result, fmt.Errorf("%w: %v: %w", model.ErrInvalidImport, variable.Key, err)
+		}
+		if err := variable.ValidateBinary(); err != nil {
+			return result, fmt.Errorf("%w: %v: %w", model.ErrInvalidImport, variable.Key, err)
+		}
+		keys[variable.Key] = true
+	}
+	var written []model.Variable
+	if conflict == model.ImportConflictFail {
+		err = this.db.Transaction(ctx, func(ctx context.Context) (err error) {
+			result, written, err = this.importVariables(ctx, userid, variables, conflict)
+			return err
+		})
+		switch {
+		case errors.Is(err, model.ErrTransactionsUnsupported):
+			result, written, err = this.importVariables(ctx, userid, variables, conflict)
+		case err != nil:
+			//the transaction is rolled back
+			return model.ImportSummary{Created: []string{}, Updated: []string{}, Skipped: []string{}}, err
+		}
+	} else {
+		result, written, err = this.importVariables(ctx, userid, variables, conflict)
+	}
+	for _, variable := range written {
+		this.metrics.LogWriteSize(userid, variable)
+	}
+	return result, err
+}
+
+// importVariables writes the validated variables of Import and returns the written variables
+func (this *Controller) importVariables(ctx context.Context, userid string, variables []model.VariableWithUnixTimestamp, conflict string) (result model.ImportSummary, written []model.Variable, err error) {
+	result = model.ImportSummary{Created: []string{}, Updated: []string{}, Skipped: []string{}}
+	if conflict == model.ImportConflictFail {
+		conflicts := []string{}
+		for _, variable := range variables {
+			current, err := this.db.GetVariable(ctx, userid, variable.Key)
+			if err != nil {
+				return result, nil, err
+			}
+			if current.Revision > 0 {
+				conflicts = append(conflicts, variable.Key)
+			}
+		}
+		if len(conflicts) > 0 {
+			return result, nil, fmt.Errorf("%w: %v", model.ErrImportConflict, strings.Join(conflicts, ", "))
+		}
+	}
+	now := configuration.TimeNow().Unix()
+	for _, variable := range variables {
+		if variable.UnixTimestampInS <= 0 {
+			variable.UnixTimestampInS = now
+		}
+		variable.Owner = ""
+		existed := false
+		_, err = this.update(ctx, userid, userid, variable.Key, func(current model.VariableWithUser) (model.VariableWithUser, error) {
+			existed = current.Revision > 0
+			switch {
+			case existed && conflict == model.ImportConflictSkip:
+				return current, errImportSkip
+			case existed && conflict == model.ImportConflictFail:
+				return current, fmt.Errorf("%w: %v", model.ErrImportConflict, variable.Key)
+			}
+			return model.VariableWithUser{VariableWithUnixTimestamp: variable, UserId: userid}, nil
+		})
+		switch {
+		case errors.Is(err, errImportSkip):
+			result.Skipped = append(result.Skipped, variable.Key)
+		case err != nil:
+			return result, written, err
+		case existed:
+			result.Updated = append(result.Updated, variable.Key)
+		default:
+			result.Created = append(result.Created, variable.Key)
+		}
+		if err == nil {
+			written = append(written, variable.Variable)
+		}
+	}
+	return result, written, nil
+}
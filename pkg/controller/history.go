@@ -0,0 +1,134 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller/calculate"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// GetAt returns the variable as it was stored at the given time and its revision at that time;
+// returns an unknown variable (revision 0) if the variable did not exist at that time or the version is no longer retained
+func (this *Controller) GetAt(ctx context.Context, userid string, key string, unixTimestampInS int64) (res model.VariableWithUnixTimestamp, revision int64, err error) {
+	if !this.config.HistoryEnabled {
+		return res, revision, model.ErrHistoryDisabled
+	}
+	if strings.HasPrefix(key, calculate.Prefix) {
+		return this.GetWithRevision(ctx, userid, key)
+	}
+	current, err := this.db.GetVariable(ctx, userid, key)
+	if err != nil {
+		return res, revision, err
+	}
+	//variables written before the history was enabled have no versions
+	if current.Revision > 0 && current.UnixTimestampInS <= unixTimestampInS {
+		this.metrics.LogReadSize(userid, current.Variable)
+		return current.VariableWithUnixTimestamp, current.Revision, nil
+	}
+	version, err := this.db.GetVariableVersionAt(ctx, userid, key, unixTimestampInS)
+	if errors.Is(err, model.ErrVersionNotFound) || (err == nil && version.IsExpired(unixTimestampInS)) {
+		return model.VariableWithUnixTimestamp{Variable: model.Variable{Key: key}}, 0, nil
+	}
+	if err != nil {
+		return res, revision, err
+	}
+	this.metrics.LogReadSize(userid, version.Variable)
+	return version.VariableWithUnixTimestamp, version.Revision, nil
+}
+
+// History returns the retained versions of the variable, newest first
+func (this *Controller) History(ctx context.Context, userid string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	if !this.config.HistoryEnabled {
+		return []model.VariableVersion{}, model.ErrHistoryDisabled
+	}
+	result, err = this.db.ListVariableVersions(ctx, userid, key, query)
+	if err != nil {
+		return []model.VariableVersion{}, err
+	}
+	if result == nil {
+		result = []model.VariableVersion{}
+	}
+	return result, nil
+}
+
+// Restore writes the version with the given revision from the history as new version of the variable,
+// if the stored variable matches the precondition; returns the new variable and revision.
+// the restored variable does not expire, because the expiry of the version may already be reached
+func (this *Controller) Restore(ctx context.Context, userid string, key string, revision int64, precondition model.Precondition) (res model.VariableWithUnixTimestamp, newRevision int64, err error) {
+	if !this.config.HistoryEnabled {
+		return res, newRevision, model.ErrHistoryDisabled
+	}
+	version, err := this.db.GetVariableVersion(ctx, userid, key, revision)
+	if err != nil {
+		return res, newRevision, err
+	}
+	variable, err := this.update(ctx, userid, userid, key, func(current model.VariableWithUser) (model.VariableWithUser, error) {
+		ok, err := precondition.Check(current.Revision)
+		if err != nil {
+			return current, err
+		}
+		if !ok {
+			return current, model.ErrPreconditionFailed
+		}
+		restored := version.Variable
+		restored.ExpiresAt = 0
+		return this.withUser(userid, restored), nil
+	})
+	if err != nil {
+		return res, newRevision, err
+	}
+	this.metrics.LogWriteSize(userid, variable.Variable)
+	return variable.VariableWithUnixTimestamp, variable.Revision, nil
+}
+
+// historyTransaction runs f in a transaction if the history is enabled,
+// to store the variable and its version in the history atomically
+func (this *Controller) historyTransaction(ctx context.Context, f func(ctx context.Context) error) error {
+	if !this.config.HistoryEnabled {
+		return f(ctx)
+	}
+	return this.db.Transaction(ctx, f)
+}
+
+// addVersion stores the written variable in the history, if the history is enabled,
+// and removes the versions no longer retained by the configured history_max_versions and history_max_age
+func (this *Controller) addVersion(ctx context.Context, writer string, variable model.VariableWithUser) error {
+	if !this.config.HistoryEnabled {
+		return nil
+	}
+	err := this.db.AddVariableVersion(ctx, variable.UserId, model.VariableVersion{
+		VariableWithUnixTimestamp: variable.VariableWithUnixTimestamp,
+		Revision:                  variable.Revision,
+		Writer:                    writer,
+	})
+	if err != nil {
+		return err
+	}
+	retention := model.HistoryRetention{MaxVersions: this.config.HistoryMaxVersions}
+	if this.historyMaxAge > 0 {
+		retention.MinUnixTimestampInS = configuration.TimeNow().Add(-this.historyMaxAge).Unix()
+	}
+	if retention.MaxVersions <= 0 && retention.MinUnixTimestampInS <= 0 {
+		return nil
+	}
+	return this.db.PruneVariableHistory(ctx, variable.UserId, variable.Key, variable.Revision, retention)
+}
@@ -44,6 +44,14 @@ func New() *Metrics {
 			Name: "process_io_api_read_size_sum",
 			Help: "read size sum in bytes",
 		}, []string{"user_id"}),
+		cacheHits: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "process_io_api_cache_hits",
+			Help: "variable reads served by the cache",
+		}),
+		cacheMisses: promauto.With(reg).NewCounter(prometheus.CounterOpts{
+			Name: "process_io_api_cache_misses",
+			Help: "variable reads not found in the cache and read from the database",
+		}),
 	}
 
 	return result
@@ -54,6 +62,9 @@ type Metrics struct {
 
 	writeSizes *prometheus.CounterVec
 	readSizes  *prometheus.CounterVec
+
+	cacheHits   prometheus.Counter
+	cacheMisses prometheus.Counter
 }
 
 func (this *Metrics) LogWriteSize(userId string, writtenElement interface{}) {
@@ -79,3 +90,17 @@ func (this *Metrics) LogReadSize(userId string, readElement interface{}) {
 		this.readSizes.WithLabelValues(userId).Add(float64(len(buf)))
 	}
 }
+
+func (this *Metrics) LogCacheHit() {
+	if this == nil {
+		return
+	}
+	this.cacheHits.Inc()
+}
+
+func (this *Metrics) LogCacheMiss() {
+	if this == nil {
+		return
+	}
+	this.cacheMisses.Inc()
+}
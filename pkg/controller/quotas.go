@@ -0,0 +1,136 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// GetQuota returns the quota of the user and its current usage
+func (this *Controller) GetQuota(ctx context.Context, userid string) (result model.QuotaStatus, err error) {
+	result.Quota, result.Override, err = this.getQuota(ctx, userid)
+	if err != nil {
+		return result, err
+	}
+	result.Usage, err = this.db.GetUsage(ctx, userid)
+	return result, err
+}
+
+// SetQuota overrides the configured limits for the user quotaUserId; the api only allows admins to call it
+func (this *Controller) SetQuota(ctx context.Context, userid string, quotaUserId string, quota model.Quota) error {
+	if quotaUserId == "" {
+		return errors.Join(model.ErrInvalidQuota, errors.New("missing user id"))
+	}
+	quota.UserId = quotaUserId
+	err := quota.Validate()
+	if err != nil {
+		return err
+	}
+	return this.db.SetQuota(ctx, quota)
+}
+
+// DeleteQuota removes the override of the user quotaUserId, so that the configured limits apply again
+func (this *Controller) DeleteQuota(ctx context.Context, userid string, quotaUserId string) error {
+	return this.db.DeleteQuota(ctx, quotaUserId)
+}
+
+// getQuota returns the override of the user or the configured limits
+func (this *Controller) getQuota(ctx context.Context, userid string) (quota model.Quota, override bool, err error) {
+	quota, override, err = this.db.GetQuota(ctx, userid)
+	if err != nil || override {
+		return quota, override, err
+	}
+	return model.Quota{
+		MaxVariables:  this.config.QuotaMaxVariables,
+		MaxValueBytes: this.config.QuotaMaxValueBytes,
+		MaxTotalBytes: this.config.QuotaMaxTotalBytes,
+	}, false, nil
+}
+
+// checkQuota returns model.ErrValueTooLarge or model.ErrQuotaExceeded if writing the variable would exceed the quota of the owner;
+// concurrent writes are not serialized, so the limits may be exceeded slightly
+func (this *Controller) checkQuota(ctx context.Context, owner string, variable model.Variable) error {
+	check, err := this.getQuotaCheck(ctx, owner)
+	if err != nil {
+		return err
+	}
+	current := model.VariableWithUser{}
+	if check.needsUsage() {
+		current, err = this.db.GetVariable(ctx, owner, variable.Key)
+		if err != nil {
+			return err
+		}
+	}
+	return check.check(current, variable)
+}
+
+// quotaCheck holds the quota and usage of an owner, to check writes without further database reads;
+// used in db.UpdateVariable callbacks, where backends may hold a lock over read and write
+type quotaCheck struct {
+	quota model.Quota
+	usage model.Usage
+}
+
+// getQuotaCheck loads the quota of the owner and, if needed by the quota, the current usage
+func (this *Controller) getQuotaCheck(ctx context.Context, owner string) (result quotaCheck, err error) {
+	result.quota, _, err = this.getQuota(ctx, owner)
+	if err != nil {
+		return result, err
+	}
+	if result.needsUsage() {
+		result.usage, err = this.db.GetUsage(ctx, owner)
+	}
+	return result, err
+}
+
+func (this quotaCheck) needsUsage() bool {
+	return this.quota.MaxVariables > 0 || this.quota.MaxTotalBytes > 0
+}
+
+// check returns an error if replacing the current variable with the new variable exceeds the quota
+func (this quotaCheck) check(current model.VariableWithUser, variable model.Variable) error {
+	if this.quota.MaxVariables == 0 && this.quota.MaxValueBytes == 0 && this.quota.MaxTotalBytes == 0 {
+		return nil
+	}
+	size, err := model.ValueSize(variable.Value)
+	if err != nil {
+		return err
+	}
+	if this.quota.MaxValueBytes > 0 && size > this.quota.MaxValueBytes {
+		return fmt.Errorf("%w: %v bytes > %v bytes", model.ErrValueTooLarge, size, this.quota.MaxValueBytes)
+	}
+	if this.quota.MaxVariables > 0 && current.Revision == 0 && this.usage.Variables >= this.quota.MaxVariables {
+		return fmt.Errorf("%w: max_variables %v reached", model.ErrQuotaExceeded, this.quota.MaxVariables)
+	}
+	if this.quota.MaxTotalBytes > 0 {
+		var currentSize int64
+		if current.Revision > 0 {
+			currentSize, err = model.ValueSize(current.Value)
+			if err != nil {
+				return err
+			}
+		}
+		if total := this.usage.TotalBytes - currentSize + size; total > this.quota.MaxTotalBytes {
+			return fmt.Errorf("%w: %v bytes > max_total_bytes %v", model.ErrQuotaExceeded, total, this.quota.MaxTotalBytes)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,252 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+)
+
+// ListShares lists the shares created by the user
+func (this *Controller) ListShares(ctx context.Context, userid string) ([]model.Share, error) {
+	return this.db.ListShares(ctx, userid)
+}
+
+// ListReceivedShares lists the shares granted to the user or to one of the groups of the user (see model.WithGroups)
+func (this *Controller) ListReceivedShares(ctx context.Context, userid string) ([]model.Share, error) {
+	return this.db.ListSharesFor(ctx, userid, model.GetGroups(ctx))
+}
+
+// CreateShare shares variables of the user; the id and owner of the share are set by the controller
+func (this *Controller) CreateShare(ctx context.Context, userid string, share model.Share) (model.Share, error) {
+	share.OwnerId = userid
+	err := share.Validate()
+	if err != nil {
+		return share, err
+	}
+	id := make([]byte, 16)
+	_, err = rand.Read(id)
+	if err != nil {
+		return share, err
+	}
+	share.Id = hex.EncodeToString(id)
+	return share, this.db.SetShare(ctx, share)
+}
+
+// DeleteShare deletes a share created by the user; returns model.ErrShareNotFound if the user has no share with the id
+func (this *Controller) DeleteShare(ctx context.Context, userid string, id string) error {
+	deleted, err := this.db.DeleteShare(ctx, userid, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return model.ErrShareNotFound
+	}
+	return nil
+}
+
+// getShares returns the shares granted to the user, grouped by owner; shares of the user itself are ignored
+func (this *Controller) getShares(ctx context.Context, userid string) (map[string][]model.Share, error) {
+	shares, err := this.db.ListSharesFor(ctx, userid, model.GetGroups(ctx))
+	if err != nil {
+		return nil, err
+	}
+	result := map[string][]model.Share{}
+	for _, share := range shares {
+		if share.OwnerId != userid {
+			result[share.OwnerId] = append(result[share.OwnerId], share)
+		}
+	}
+	return result, nil
+}
+
+// getShared returns the variable with the key shared by the shares (see getShares) and whether the user may write it;
+// if multiple owners share a variable with the key, owners granting write access are preferred, then the owner with the lowest id wins,
+// so reads and writes of the user resolve to the same variable.
+// the returned variable has revision 0 if no existing variable is shared with the user
+func (this *Controller) getShared(ctx context.Context, key string, shares map[string][]model.Share) (variable model.VariableWithUser, write bool, err error) {
+	grants := map[string]bool{}
+	for owner, ownerShares := range shares {
+		for _, share := range ownerShares {
+			if share.Matches(key) {
+				grants[owner] = grants[owner] || share.Write
+			}
+		}
+	}
+	owners := slices.Sorted(maps.Keys(grants))
+	slices.SortStableFunc(owners, func(a, b string) int {
+		switch {
+		case grants[a] == grants[b]:
+			return 0
+		case grants[a]:
+			return -1
+		default:
+			return 1
+		}
+	})
+	for _, owner := range owners {
+		variable, err = this.db.GetVariable(ctx, owner, key)
+		if err != nil || variable.Revision > 0 {
+			return variable, grants[owner], err
+		}
+	}
+	return model.VariableWithUser{}, false, nil
+}
+
+// getWriteOwner returns the user whose variable is written if the user writes the key:
+// the own variable if it exists, otherwise a variable shared with the user for writing, otherwise a new own variable.
+// a variable shared only for reading does not prevent the user from creating an own variable with the key
+func (this *Controller) getWriteOwner(ctx context.Context, userid string, key string) (string, error) {
+	own, err := this.db.GetVariable(ctx, userid, key)
+	if err != nil || own.Revision > 0 {
+		return userid, err
+	}
+	shares, err := this.getShares(ctx, userid)
+	if err != nil || len(shares) == 0 {
+		return userid, err
+	}
+	shared, write, err := this.getShared(ctx, key, shares)
+	if err != nil || shared.Revision == 0 || !write {
+		return userid, err
+	}
+	return shared.UserId, nil
+}
+
+// listWithShared lists the variables of the user and the variables shared with the user;
+// every source is listed up to offset+limit, the merged list is sorted and paginated in memory
+func (this *Controller) listWithShared(ctx context.Context, userid string, query model.VariablesQueryOptions, shares map[string][]model.Share) ([]model.VariableWithUnixTimestamp, error) {
+	sourceQuery := query
+	sourceQuery.Offset = 0
+	if query.Limit > 0 {
+		sourceQuery.Limit = query.Limit + query.Offset
+	}
+	result, err := this.listAll(ctx, userid, sourceQuery, shares)
+	if err != nil {
+		return nil, err
+	}
+	ties, err := this.listCursorTies(ctx, userid, query, shares)
+	if err != nil {
+		return nil, err
+	}
+	result = append(result, ties...)
+	for i := range result {
+		//the sort compares values like the in-memory databases, which expect normalized json values
+		result[i].Value, err = jsondoc.Normalize(result[i].Value)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = util.SortVariables(result, query)
+	if err != nil {
+		return nil, err
+	}
+	return util.Paginate(result, query), nil
+}
+
+// listAll lists the variables of the user and the variables shared with the user without merging
+func (this *Controller) listAll(ctx context.Context, userid string, query model.VariablesQueryOptions, shares map[string][]model.Share) ([]model.VariableWithUnixTimestamp, error) {
+	result, err := this.db.ListVariables(ctx, userid, query)
+	if err != nil {
+		return nil, err
+	}
+	shared, err := this.listShared(ctx, query, shares)
+	if err != nil {
+		return nil, err
+	}
+	return append(result, shared...), nil
+}
+
+// listCursorTies lists the variables with the key of the cursor position of the query, which are sorted after the cursor only by their owner;
+// the databases do not know the owners and skip these variables as equal to the cursor position
+func (this *Controller) listCursorTies(ctx context.Context, userid string, query model.VariablesQueryOptions, shares map[string][]model.Share) (result []model.VariableWithUnixTimestamp, err error) {
+	cursor, err := util.ParseCursor(query)
+	if err != nil || cursor == nil {
+		return nil, err
+	}
+	if len(query.Keys) > 0 && !slices.Contains(query.Keys, cursor.Key()) {
+		return nil, nil
+	}
+	query.Keys = []string{cursor.Key()}
+	query.Limit, query.Offset, query.Cursor = 0, 0, ""
+	candidates, err := this.listAll(ctx, userid, query, shares)
+	if err != nil {
+		return nil, err
+	}
+	for _, variable := range candidates {
+		if cursor.Ties(variable) && cursor.After(variable) {
+			result = append(result, variable)
+		}
+	}
+	return result, nil
+}
+
+// listShared lists the variables matching the query that are shared by the owners of the shares
+func (this *Controller) listShared(ctx context.Context, query model.VariablesQueryOptions, shares map[string][]model.Share) (result []model.VariableWithUnixTimestamp, err error) {
+	result = []model.VariableWithUnixTimestamp{}
+	for _, owner := range slices.Sorted(maps.Keys(shares)) {
+		seen := map[string]bool{}
+		for _, share := range shares[owner] {
+			shareQuery, ok := restrictToShare(query, share)
+			if !ok {
+				continue
+			}
+			variables, err := this.db.ListVariables(ctx, owner, shareQuery)
+			if err != nil {
+				return nil, err
+			}
+			for _, variable := range variables {
+				if !seen[variable.Key] {
+					seen[variable.Key] = true
+					variable.Owner = owner
+					result = append(result, variable)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+// restrictToShare narrows the key filters of the query to the keys of the share; returns false if no key matches both
+func restrictToShare(query model.VariablesQueryOptions, share model.Share) (model.VariablesQueryOptions, bool) {
+	if share.Key != "" {
+		if len(query.Keys) > 0 && !slices.Contains(query.Keys, share.Key) {
+			return query, false
+		}
+		if !strings.HasPrefix(share.Key, query.KeyPrefix) {
+			return query, false
+		}
+		query.Keys = []string{share.Key}
+		return query, true
+	}
+	switch {
+	case strings.HasPrefix(query.KeyPrefix, share.KeyPrefix):
+		//the prefix of the query is already narrower than the prefix of the share
+	case strings.HasPrefix(share.KeyPrefix, query.KeyPrefix):
+		query.KeyPrefix = share.KeyPrefix
+	default:
+		return query, false
+	}
+	return query, true
+}
@@ -0,0 +1,105 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	bolt "go.etcd.io/bbolt"
+)
+
+var CreateBuckets = []func(db *Bolt) error{}
+
+// New opens (or creates) the single file database located at config.BoltFile
+func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (*Bolt, error) {
+	if config.BoltFile == "" {
+		return nil, errors.New("missing bolt_file config")
+	}
+	db, err := bolt.Open(config.BoltFile, 0600, &bolt.Options{Timeout: 10 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Bolt{config: config, db: db}
+	for _, creators := range CreateBuckets {
+		err = creators(result)
+		if err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	result.startExpirySweeper(ctx, wg, config.GetExpirySweepInterval())
+
+	wg.Add(1)
+	go func() {
+		<-ctx.Done()
+		db.Close()
+		wg.Done()
+	}()
+
+	return result, nil
+}
+
+type Bolt struct {
+	config configuration.Config
+	db     *bolt.DB
+}
+
+// Ping checks if the database file is still open
+func (this *Bolt) Ping(ctx context.Context) error {
+	return this.db.View(func(tx *bolt.Tx) error {
+		return nil
+	})
+}
+
+const separator = "\x00"
+
+// joinKey builds bucket keys from multiple parts; parts are separated by a null byte,
+// so that a prefix scan for the first n parts never matches longer values of the n-th part
+func joinKey(parts ...string) []byte {
+	buf := bytes.Buffer{}
+	for _, part := range parts {
+		buf.WriteString(part)
+		buf.WriteString(separator)
+	}
+	return buf.Bytes()
+}
+
+func splitKey(key []byte) (result []string) {
+	for _, part := range bytes.Split(bytes.TrimSuffix(key, []byte(separator)), []byte(separator)) {
+		result = append(result, string(part))
+	}
+	return result
+}
+
+// scanPrefix calls f for every key in the bucket starting with prefix
+func scanPrefix(bucket *bolt.Bucket, prefix []byte, f func(key []byte, value []byte) error) error {
+	cursor := bucket.Cursor()
+	for k, v := cursor.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = cursor.Next() {
+		err := f(k, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
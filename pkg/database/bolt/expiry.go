@@ -0,0 +1,84 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// startExpirySweeper periodically deletes expired variables and their history until ctx is done;
+// reads already ignore expired variables, the sweeper only frees the storage
+func (this *Bolt) startExpirySweeper(ctx context.Context, wg *sync.WaitGroup, interval time.Duration) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := this.deleteExpiredVariables(ctx)
+				if err != nil && ctx.Err() == nil {
+					this.config.GetLogger().Error("unable to delete expired variables", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// deleteExpiredVariables deletes all expired variables and their history
+func (this *Bolt) deleteExpiredVariables(ctx context.Context) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		now := configuration.TimeNow().Unix()
+		expired := []model.VariableWithUser{}
+		err := tx.Bucket(variablesBucket).ForEach(func(_ []byte, value []byte) error {
+			variable := model.VariableWithUser{}
+			err := json.Unmarshal(value, &variable)
+			if err != nil {
+				return err
+			}
+			if variable.IsExpired(now) {
+				expired = append(expired, variable)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		//the bucket must not be changed while iterating over it
+		for _, variable := range expired {
+			err = deleteVariable(tx, variable.UserId, variable.Key)
+			if err != nil {
+				return err
+			}
+			err = deleteHistory(tx, variable.UserId, variable.Key)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
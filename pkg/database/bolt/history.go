@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// historyBucket stores the versions of the variables with user-id, key and revision as bucket key;
+// the revision is zero padded to iterate the versions of a variable in revision order
+var historyBucket = []byte("variable_history")
+
+// history index buckets map <index-value, user-id, key, revision> to an empty value
+var historyProcessInstanceIndexBucket = []byte("variable_history_p_instance_index")
+var historyProcessDefinitionIndexBucket = []byte("variable_history_p_definition_index")
+
+func init() {
+	CreateBuckets = append(CreateBuckets, func(db *Bolt) error {
+		return db.db.Update(func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{historyBucket, historyProcessInstanceIndexBucket, historyProcessDefinitionIndexBucket} {
+				_, err := tx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func formatRevision(revision int64) string {
+	return fmt.Sprintf("%020d", revision)
+}
+
+func (this *Bolt) AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error {
+	value, err := json.Marshal(version)
+	if err != nil {
+		return err
+	}
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		revision := formatRevision(version.Revision)
+		err = deleteVersion(tx, userId, version.Key, revision)
+		if err != nil {
+			return err
+		}
+		err = tx.Bucket(historyBucket).Put(joinKey(userId, version.Key, revision), value)
+		if err != nil {
+			return err
+		}
+		if version.ProcessInstanceId != "" {
+			err = tx.Bucket(historyProcessInstanceIndexBucket).Put(joinKey(version.ProcessInstanceId, userId, version.Key, revision), []byte{})
+			if err != nil {
+				return err
+			}
+		}
+		if version.ProcessDefinitionId != "" {
+			err = tx.Bucket(historyProcessDefinitionIndexBucket).Put(joinKey(version.ProcessDefinitionId, userId, version.Key, revision), []byte{})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (this *Bolt) ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return scanPrefix(tx.Bucket(historyBucket), joinKey(userId, key), func(_ []byte, value []byte) error {
+			version := model.VariableVersion{}
+			err := json.Unmarshal(value, &version)
+			if err != nil {
+				return err
+			}
+			result = append(result, version)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(result) //newest version first
+	return util.PaginateVersions(result, query), nil
+}
+
+func (this *Bolt) GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (result model.VariableVersion, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		value := tx.Bucket(historyBucket).Get(joinKey(userId, key, formatRevision(revision)))
+		if value == nil {
+			return model.ErrVersionNotFound
+		}
+		return json.Unmarshal(value, &result)
+	})
+	return result, err
+}
+
+func (this *Bolt) GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (result model.VariableVersion, err error) {
+	found := false
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return scanPrefix(tx.Bucket(historyBucket), joinKey(userId, key), func(_ []byte, value []byte) error {
+			version := model.VariableVersion{}
+			err := json.Unmarshal(value, &version)
+			if err != nil {
+				return err
+			}
+			if version.UnixTimestampInS <= unixTimestampInS {
+				result = version
+				found = true
+			}
+			return nil
+		})
+	})
+	if err == nil && !found {
+		err = model.ErrVersionNotFound
+	}
+	return result, err
+}
+
+func (this *Bolt) PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		pruned := []string{}
+		err := scanPrefix(tx.Bucket(historyBucket), joinKey(userId, key), func(_ []byte, value []byte) error {
+			version := model.VariableVersion{}
+			err := json.Unmarshal(value, &version)
+			if err != nil {
+				return err
+			}
+			if util.IsPruned(version, currentRevision, retention) {
+				pruned = append(pruned, formatRevision(version.Revision))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, revision := range pruned {
+			err = deleteVersion(tx, userId, key, revision)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteHistory removes all versions of the variable and their index entries
+func deleteHistory(tx *bolt.Tx, userId string, key string) error {
+	revisions := []string{}
+	err := scanPrefix(tx.Bucket(historyBucket), joinKey(userId, key), func(k []byte, _ []byte) error {
+		ref := splitKey(k)
+		if len(ref) == 3 {
+			revisions = append(revisions, ref[2])
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, revision := range revisions {
+		err = deleteVersion(tx, userId, key, revision)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteVersion removes the version and its index entries
+func deleteVersion(tx *bolt.Tx, userId string, key string, revision string) error {
+	value := tx.Bucket(historyBucket).Get(joinKey(userId, key, revision))
+	if value == nil {
+		return nil
+	}
+	old := model.VariableVersion{}
+	err := json.Unmarshal(value, &old)
+	if err != nil {
+		return err
+	}
+	if old.ProcessInstanceId != "" {
+		err = tx.Bucket(historyProcessInstanceIndexBucket).Delete(joinKey(old.ProcessInstanceId, userId, key, revision))
+		if err != nil {
+			return err
+		}
+	}
+	if old.ProcessDefinitionId != "" {
+		err = tx.Bucket(historyProcessDefinitionIndexBucket).Delete(joinKey(old.ProcessDefinitionId, userId, key, revision))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(historyBucket).Delete(joinKey(userId, key, revision))
+}
+
+// deleteIndexedVersions removes all versions referenced by the history index entries with the given index value
+func deleteIndexedVersions(tx *bolt.Tx, indexBucket []byte, indexValue string) error {
+	refs := [][]string{}
+	err := scanPrefix(tx.Bucket(indexBucket), joinKey(indexValue), func(key []byte, _ []byte) error {
+		refs = append(refs, splitKey(key))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if len(ref) != 4 {
+			continue
+		}
+		err = deleteVersion(tx, ref[1], ref[2], ref[3])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// quotasBucket stores the quota overrides as json with the user id as bucket key
+var quotasBucket = []byte("quotas")
+
+func init() {
+	CreateBuckets = append(CreateBuckets, func(db *Bolt) error {
+		return db.db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(quotasBucket)
+			return err
+		})
+	})
+}
+
+func (this *Bolt) GetUsage(ctx context.Context, userId string) (result model.Usage, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return forEachVariable(ctx, tx, userId, model.VariablesQueryOptions{}, func(variable model.VariableWithUser) error {
+			size, err := model.ValueSize(variable.Value)
+			if err != nil {
+				return err
+			}
+			result.Variables++
+			result.TotalBytes += size
+			return nil
+		})
+	})
+	return result, err
+}
+
+func (this *Bolt) GetQuota(ctx context.Context, userId string) (quota model.Quota, found bool, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		value := tx.Bucket(quotasBucket).Get([]byte(userId))
+		if value == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(value, &quota)
+	})
+	return quota, found, err
+}
+
+func (this *Bolt) SetQuota(ctx context.Context, quota model.Quota) error {
+	value, err := json.Marshal(quota)
+	if err != nil {
+		return err
+	}
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket(quotasBucket).Put([]byte(quota.UserId), value)
+	})
+}
+
+func (this *Bolt) DeleteQuota(ctx context.Context, userId string) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket(quotasBucket).Delete([]byte(userId))
+	})
+}
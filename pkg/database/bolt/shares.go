@@ -0,0 +1,101 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// sharesBucket stores the shares as json with the share id as bucket key;
+// shares are expected to be few, so listings scan the whole bucket
+var sharesBucket = []byte("shares")
+
+func init() {
+	CreateBuckets = append(CreateBuckets, func(db *Bolt) error {
+		return db.db.Update(func(tx *bolt.Tx) error {
+			_, err := tx.CreateBucketIfNotExists(sharesBucket)
+			return err
+		})
+	})
+}
+
+func (this *Bolt) SetShare(ctx context.Context, share model.Share) error {
+	value, err := json.Marshal(share)
+	if err != nil {
+		return err
+	}
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket(sharesBucket).Put([]byte(share.Id), value)
+	})
+}
+
+func (this *Bolt) DeleteShare(ctx context.Context, ownerId string, id string) (deleted bool, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(sharesBucket)
+		value := bucket.Get([]byte(id))
+		if value == nil {
+			return nil
+		}
+		share := model.Share{}
+		err := json.Unmarshal(value, &share)
+		if err != nil {
+			return err
+		}
+		if share.OwnerId != ownerId {
+			return nil
+		}
+		deleted = true
+		return bucket.Delete([]byte(id))
+	})
+	return deleted, err
+}
+
+func (this *Bolt) ListShares(ctx context.Context, ownerId string) ([]model.Share, error) {
+	return this.listShares(ctx, func(share model.Share) bool {
+		return share.OwnerId == ownerId
+	})
+}
+
+func (this *Bolt) ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) {
+	return this.listShares(ctx, func(share model.Share) bool {
+		return (share.UserId != "" && share.UserId == userId) || (share.Group != "" && slices.Contains(groups, share.Group))
+	})
+}
+
+// listShares returns the shares matching the filter in id order
+func (this *Bolt) listShares(ctx context.Context, filter func(share model.Share) bool) (result []model.Share, err error) {
+	result = []model.Share{}
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return tx.Bucket(sharesBucket).ForEach(func(key []byte, value []byte) error {
+			share := model.Share{}
+			err := json.Unmarshal(value, &share)
+			if err != nil {
+				return err
+			}
+			if filter(share) {
+				result = append(result, share)
+			}
+			return nil
+		})
+	})
+	return result, err
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+type txContextKey struct{}
+
+// Transaction runs f in a read-write transaction; all methods called with the context passed to f use this transaction.
+// the transaction is committed if f returns nil and rolled back otherwise.
+// nested calls reuse the outer transaction.
+func (this *Bolt) Transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		return f(context.WithValue(ctx, txContextKey{}, tx))
+	})
+}
+
+// view runs f in the transaction of the context or in a new read-only transaction
+func (this *Bolt) view(ctx context.Context, f func(tx *bolt.Tx) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*bolt.Tx); ok {
+		return f(tx)
+	}
+	return this.db.View(f)
+}
+
+// update runs f in the transaction of the context or in a new read-write transaction
+func (this *Bolt) update(ctx context.Context, f func(tx *bolt.Tx) error) error {
+	if tx, ok := ctx.Value(txContextKey{}).(*bolt.Tx); ok {
+		return f(tx)
+	}
+	return this.db.Update(f)
+}
@@ -0,0 +1,502 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package bolt
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	bolt "go.etcd.io/bbolt"
+)
+
+// variablesBucket stores the variables with user-id and key as bucket key (equivalent to the user/key index in mongo)
+var variablesBucket = []byte("variables")
+
+// index buckets map <index-value, user-id, key> to an empty value
+var processInstanceIndexBucket = []byte("variables_p_instance_index")
+var processDefinitionIndexBucket = []byte("variables_p_definition_index")
+
+// revisionsBucket stores the last revision of removed variables with user-id and key as bucket key, see nextRevision
+var revisionsBucket = []byte("variable_revisions")
+
+func init() {
+	CreateBuckets = append(CreateBuckets, func(db *Bolt) error {
+		return db.db.Update(func(tx *bolt.Tx) error {
+			for _, name := range [][]byte{variablesBucket, processInstanceIndexBucket, processDefinitionIndexBucket, revisionsBucket} {
+				_, err := tx.CreateBucketIfNotExists(name)
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+func (this *Bolt) GetVariable(ctx context.Context, userId string, key string) (result model.VariableWithUser, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		var found bool
+		result, found, err = getLiveVariable(tx, userId, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			result = model.VariableWithUser{
+				VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
+					Variable: model.Variable{
+						Key:                 key,
+						Value:               nil,
+						ProcessDefinitionId: "",
+						ProcessInstanceId:   "",
+					},
+					UnixTimestampInS: 0,
+				},
+				UserId: userId,
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (this *Bolt) GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (model.VariableWithUser, error) {
+	variable, err := this.GetVariable(ctx, userId, key)
+	if err != nil {
+		return variable, err
+	}
+	return util.SelectPointer(variable, pointer)
+}
+
+func (this *Bolt) SetVariable(ctx context.Context, variable model.VariableWithUser) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		old, _, err := getWritableVariable(tx, variable.UserId, variable.Key)
+		if err != nil {
+			return err
+		}
+		variable.Revision, err = nextRevision(tx, variable.UserId, variable.Key, old.Revision)
+		if err != nil {
+			return err
+		}
+		return putVariable(tx, variable)
+	})
+}
+
+func (this *Bolt) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (newRevision int64, ok bool, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		old, _, err := getWritableVariable(tx, variable.UserId, variable.Key)
+		if err != nil {
+			return err
+		}
+		if old.Revision != revision {
+			return nil
+		}
+		variable.Revision, err = nextRevision(tx, variable.UserId, variable.Key, old.Revision)
+		if err != nil {
+			return err
+		}
+		newRevision, ok = variable.Revision, true
+		return putVariable(tx, variable)
+	})
+	return newRevision, ok, err
+}
+
+func (this *Bolt) IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (result model.VariableWithUser, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		old, exists, err := getWritableVariable(tx, variable.UserId, variable.Key)
+		if err != nil {
+			return err
+		}
+		if exists {
+			number, ok := old.Value.(float64)
+			if !ok {
+				return model.ErrNotNumeric
+			}
+			variable.ProcessDefinitionId = old.ProcessDefinitionId
+			variable.ProcessInstanceId = old.ProcessInstanceId
+			variable.ExpiresAt = old.ExpiresAt
+			delta = number + delta
+		}
+		variable.Value = delta
+		variable.Revision, err = nextRevision(tx, variable.UserId, variable.Key, old.Revision)
+		if err != nil {
+			return err
+		}
+		result = variable
+		return putVariable(tx, variable)
+	})
+	return result, err
+}
+
+func (this *Bolt) UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (result model.VariableWithUser, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		current, found, err := getWritableVariable(tx, userId, key)
+		if err != nil {
+			return err
+		}
+		if !found {
+			current = model.VariableWithUser{VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: key}}, UserId: userId}
+		}
+		result, err = update(current)
+		if err != nil {
+			return err
+		}
+		result.Revision, err = nextRevision(tx, userId, key, current.Revision)
+		if err != nil {
+			return err
+		}
+		return putVariable(tx, result)
+	})
+	return result, err
+}
+
+func (this *Bolt) DeleteVariable(ctx context.Context, userId string, key string) error {
+	return this.update(ctx, func(tx *bolt.Tx) error {
+		err := deleteVariable(tx, userId, key)
+		if err != nil {
+			return err
+		}
+		return deleteHistory(tx, userId, key)
+	})
+}
+
+func (this *Bolt) ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	match, err := util.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return forEachVariable(ctx, tx, userId, query, func(variable model.VariableWithUser) error {
+			if match(variable.VariableWithUnixTimestamp) {
+				result = append(result, variable.VariableWithUnixTimestamp)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = util.SortVariables(result, query)
+	if err != nil {
+		return nil, err
+	}
+	return util.Paginate(result, query), nil
+}
+
+func (this *Bolt) CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
+	match, err := util.NewMatcher(query)
+	if err != nil {
+		return result, err
+	}
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		return forEachVariable(ctx, tx, userId, query, func(variable model.VariableWithUser) error {
+			if match(variable.VariableWithUnixTimestamp) {
+				result.Count++
+			}
+			return nil
+		})
+	})
+	return result, err
+}
+
+func (this *Bolt) ListUserIds(ctx context.Context) (result []string, err error) {
+	err = this.view(ctx, func(tx *bolt.Tx) error {
+		//keys are sorted by user-id, so that the variables of a user are adjacent
+		return tx.Bucket(variablesBucket).ForEach(func(key []byte, _ []byte) error {
+			parts := splitKey(key)
+			if len(parts) != 2 {
+				return nil
+			}
+			if len(result) == 0 || result[len(result)-1] != parts[0] {
+				result = append(result, parts[0])
+			}
+			return nil
+		})
+	})
+	return result, err
+}
+
+// DeleteVariables removes the not expired variables of the user matching the filters of the query and their history
+func (this *Bolt) DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (count int64, err error) {
+	match, err := util.NewMatcher(query)
+	if err != nil {
+		return 0, err
+	}
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		//keys are collected first, because buckets may not be changed while iterating over them
+		keys := []string{}
+		err := forEachVariable(ctx, tx, userId, query, func(variable model.VariableWithUser) error {
+			if match(variable.VariableWithUnixTimestamp) {
+				keys = append(keys, variable.Key)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, key := range keys {
+			err = deleteVariable(tx, userId, key)
+			if err != nil {
+				return err
+			}
+			err = deleteHistory(tx, userId, key)
+			if err != nil {
+				return err
+			}
+		}
+		count = int64(len(keys))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (this *Bolt) DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (count int64, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		count, err = deleteIndexed(tx, processDefinitionIndexBucket, definitionId)
+		if err != nil {
+			return err
+		}
+		return deleteIndexedVersions(tx, historyProcessDefinitionIndexBucket, definitionId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (this *Bolt) DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (count int64, err error) {
+	err = this.update(ctx, func(tx *bolt.Tx) error {
+		count, err = deleteIndexed(tx, processInstanceIndexBucket, instanceId)
+		if err != nil {
+			return err
+		}
+		return deleteIndexedVersions(tx, historyProcessInstanceIndexBucket, instanceId)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func getVariable(tx *bolt.Tx, userId string, key string) (result model.VariableWithUser, found bool, err error) {
+	value := tx.Bucket(variablesBucket).Get(joinKey(userId, key))
+	if value == nil {
+		return result, false, nil
+	}
+	err = json.Unmarshal(value, &result)
+	return result, true, err
+}
+
+// getLiveVariable returns the variable like getVariable, but handles expired variables as not found
+func getLiveVariable(tx *bolt.Tx, userId string, key string) (result model.VariableWithUser, found bool, err error) {
+	result, found, err = getVariable(tx, userId, key)
+	if err != nil || !found || !result.IsExpired(configuration.TimeNow().Unix()) {
+		return result, found, err
+	}
+	return model.VariableWithUser{}, false, nil
+}
+
+// getWritableVariable returns the variable like getLiveVariable, but deletes expired variables and their history,
+// so that following writes start with a new history
+func getWritableVariable(tx *bolt.Tx, userId string, key string) (result model.VariableWithUser, found bool, err error) {
+	result, found, err = getVariable(tx, userId, key)
+	if err != nil || !found || !result.IsExpired(configuration.TimeNow().Unix()) {
+		return result, found, err
+	}
+	err = deleteVariable(tx, userId, key)
+	if err != nil {
+		return result, false, err
+	}
+	return model.VariableWithUser{}, false, deleteHistory(tx, userId, key)
+}
+
+// putVariable replaces the stored variable and updates the index entries
+func putVariable(tx *bolt.Tx, variable model.VariableWithUser) error {
+	value, err := json.Marshal(variable)
+	if err != nil {
+		return err
+	}
+	old, found, err := getVariable(tx, variable.UserId, variable.Key)
+	if err != nil {
+		return err
+	}
+	if found {
+		err = deleteIndexEntries(tx, old)
+		if err != nil {
+			return err
+		}
+	}
+	err = tx.Bucket(variablesBucket).Put(joinKey(variable.UserId, variable.Key), value)
+	if err != nil {
+		return err
+	}
+	if variable.ProcessInstanceId != "" {
+		err = tx.Bucket(processInstanceIndexBucket).Put(joinKey(variable.ProcessInstanceId, variable.UserId, variable.Key), []byte{})
+		if err != nil {
+			return err
+		}
+	}
+	if variable.ProcessDefinitionId != "" {
+		err = tx.Bucket(processDefinitionIndexBucket).Put(joinKey(variable.ProcessDefinitionId, variable.UserId, variable.Key), []byte{})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteVariable removes the variable and its index entries and remembers its revision (see nextRevision)
+func deleteVariable(tx *bolt.Tx, userId string, key string) error {
+	old, found, err := getVariable(tx, userId, key)
+	if err != nil || !found {
+		return err
+	}
+	err = deleteIndexEntries(tx, old)
+	if err != nil {
+		return err
+	}
+	err = tx.Bucket(variablesBucket).Delete(joinKey(userId, key))
+	if err != nil {
+		return err
+	}
+	removed, err := getRemovedRevision(tx, userId, key)
+	if err != nil || removed >= old.Revision {
+		return err
+	}
+	value, err := json.Marshal(old.Revision)
+	if err != nil {
+		return err
+	}
+	return tx.Bucket(revisionsBucket).Put(joinKey(userId, key), value)
+}
+
+func deleteIndexEntries(tx *bolt.Tx, variable model.VariableWithUser) error {
+	if variable.ProcessInstanceId != "" {
+		err := tx.Bucket(processInstanceIndexBucket).Delete(joinKey(variable.ProcessInstanceId, variable.UserId, variable.Key))
+		if err != nil {
+			return err
+		}
+	}
+	if variable.ProcessDefinitionId != "" {
+		err := tx.Bucket(processDefinitionIndexBucket).Delete(joinKey(variable.ProcessDefinitionId, variable.UserId, variable.Key))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getRemovedRevision returns the last revision of the removed variable; 0 if no variable with the key was removed
+func getRemovedRevision(tx *bolt.Tx, userId string, key string) (revision int64, err error) {
+	value := tx.Bucket(revisionsBucket).Get(joinKey(userId, key))
+	if value == nil {
+		return 0, nil
+	}
+	err = json.Unmarshal(value, &revision)
+	return revision, err
+}
+
+// nextRevision returns the revision of the next write of the variable; revisions of removed variables are remembered,
+// so that recreated variables continue with higher revisions and outdated ETags do not match
+func nextRevision(tx *bolt.Tx, userId string, key string, currentRevision int64) (int64, error) {
+	removed, err := getRemovedRevision(tx, userId, key)
+	if err != nil {
+		return 0, err
+	}
+	return max(currentRevision, removed) + 1, nil
+}
+
+// deleteIndexed removes all variables referenced by the index entries with the given index value;
+// returns the number of removed not expired variables
+func deleteIndexed(tx *bolt.Tx, indexBucket []byte, indexValue string) (count int64, err error) {
+	refs := [][]string{}
+	err = scanPrefix(tx.Bucket(indexBucket), joinKey(indexValue), func(key []byte, _ []byte) error {
+		refs = append(refs, splitKey(key))
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, ref := range refs {
+		if len(ref) != 3 {
+			continue
+		}
+		_, live, err := getLiveVariable(tx, ref[1], ref[2])
+		if err != nil {
+			return count, err
+		}
+		err = deleteVariable(tx, ref[1], ref[2])
+		if err != nil {
+			return count, err
+		}
+		if live {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// forEachVariable iterates over the not expired variables of the user, using the most selective index available for the query;
+// stops with ctx.Err() if the context is canceled
+func forEachVariable(ctx context.Context, tx *bolt.Tx, userId string, query model.VariablesQueryOptions, f func(variable model.VariableWithUser) error) error {
+	now := configuration.TimeNow().Unix()
+	live := func(variable model.VariableWithUser) error {
+		if variable.IsExpired(now) {
+			return nil
+		}
+		return f(variable)
+	}
+	var indexBucket []byte
+	var indexValue string
+	switch {
+	case query.ProcessInstanceId != "":
+		indexBucket, indexValue = processInstanceIndexBucket, query.ProcessInstanceId
+	case query.ProcessDefinitionId != "":
+		indexBucket, indexValue = processDefinitionIndexBucket, query.ProcessDefinitionId
+	default:
+		return scanPrefix(tx.Bucket(variablesBucket), joinKey(userId), func(_ []byte, value []byte) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			variable := model.VariableWithUser{}
+			err := json.Unmarshal(value, &variable)
+			if err != nil {
+				return err
+			}
+			return live(variable)
+		})
+	}
+	return scanPrefix(tx.Bucket(indexBucket), joinKey(indexValue, userId), func(key []byte, _ []byte) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		ref := splitKey(key)
+		if len(ref) != 3 {
+			return nil
+		}
+		variable, found, err := getVariable(tx, userId, ref[2])
+		if err != nil || !found {
+			return err
+		}
+		return live(variable)
+	})
+}
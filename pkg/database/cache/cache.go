@@ -0,0 +1,266 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller/metrics"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// New wraps db with a read-through cache for GetVariable and ListSharesFor, holding up to config.CacheSize entries for config.GetCacheTtl();
+// all other reads are passed through, writes invalidate the affected entries
+func New(config configuration.Config, db controller.Database, m *metrics.Metrics) *Cache {
+	return &Cache{
+		Database: db,
+		metrics:  m,
+		size:     int(config.CacheSize),
+		ttl:      config.GetCacheTtl(),
+		entries:  map[entryKey]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+type Cache struct {
+	controller.Database //methods without effect on the cached variables are passed through
+	metrics             *metrics.Metrics
+	size                int
+	ttl                 time.Duration
+
+	mux     sync.Mutex
+	entries map[entryKey]*list.Element
+	lru     *list.List //elements are *entry, most recently used first
+	epoch   uint64     //incremented on every invalidation; reads only fill the cache if no invalidation happened in the meantime
+}
+
+// entryKey identifies a variable or, if shares is set, the shares granted to the user and the groups in key
+type entryKey struct {
+	userId string
+	key    string
+	shares bool
+}
+
+// entry stores the value as json to decouple cached values from the callers references
+type entry struct {
+	key       entryKey
+	variable  model.VariableWithUser
+	jsonValue []byte
+	shares    []model.Share
+	storedAt  time.Time
+}
+
+func (this *Cache) GetVariable(ctx context.Context, userId string, key string) (result model.VariableWithUser, err error) {
+	if getTransaction(ctx) != nil {
+		//reads in transactions may see uncommitted writes
+		return this.Database.GetVariable(ctx, userId, key)
+	}
+	result, ok, err := this.get(entryKey{userId: userId, key: key})
+	if err != nil || ok {
+		return result, err
+	}
+	epoch := this.getEpoch()
+	result, err = this.Database.GetVariable(ctx, userId, key)
+	if err != nil {
+		return result, err
+	}
+	err = this.put(epoch, result)
+	return result, err
+}
+
+// ListSharesFor is called for most requests to resolve shared variables, so the result is cached like variables
+func (this *Cache) ListSharesFor(ctx context.Context, userId string, groups []string) (result []model.Share, err error) {
+	if getTransaction(ctx) != nil {
+		return this.Database.ListSharesFor(ctx, userId, groups)
+	}
+	key := entryKey{userId: userId, key: strings.Join(slices.Sorted(slices.Values(groups)), "\x00"), shares: true}
+	e, ok := this.lookup(key)
+	if ok {
+		return slices.Clone(e.shares), nil
+	}
+	epoch := this.getEpoch()
+	result, err = this.Database.ListSharesFor(ctx, userId, groups)
+	if err != nil {
+		return result, err
+	}
+	this.store(epoch, &entry{key: key, shares: slices.Clone(result)})
+	return result, nil
+}
+
+func (this *Cache) SetShare(ctx context.Context, share model.Share) error {
+	defer this.invalidateAll(ctx)
+	return this.Database.SetShare(ctx, share)
+}
+
+func (this *Cache) DeleteShare(ctx context.Context, ownerId string, id string) (bool, error) {
+	defer this.invalidateAll(ctx)
+	return this.Database.DeleteShare(ctx, ownerId, id)
+}
+
+func (this *Cache) SetVariable(ctx context.Context, variable model.VariableWithUser) error {
+	defer this.invalidate(ctx, variable.UserId, variable.Key)
+	return this.Database.SetVariable(ctx, variable)
+}
+
+func (this *Cache) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (int64, bool, error) {
+	defer this.invalidate(ctx, variable.UserId, variable.Key)
+	return this.Database.SetVariableIfRevision(ctx, variable, revision)
+}
+
+func (this *Cache) IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (model.VariableWithUser, error) {
+	defer this.invalidate(ctx, variable.UserId, variable.Key)
+	return this.Database.IncrementVariable(ctx, variable, delta)
+}
+
+func (this *Cache) UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (model.VariableWithUser, error) {
+	defer this.invalidate(ctx, userId, key)
+	return this.Database.UpdateVariable(ctx, userId, key, update)
+}
+
+func (this *Cache) DeleteVariable(ctx context.Context, userId string, key string) error {
+	defer this.invalidate(ctx, userId, key)
+	return this.Database.DeleteVariable(ctx, userId, key)
+}
+
+func (this *Cache) DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (int64, error) {
+	defer this.invalidateAll(ctx)
+	return this.Database.DeleteVariables(ctx, userId, query)
+}
+
+func (this *Cache) DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (int64, error) {
+	defer this.invalidateAll(ctx)
+	return this.Database.DeleteVariablesOfProcessDefinition(ctx, definitionId)
+}
+
+func (this *Cache) DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (int64, error) {
+	defer this.invalidateAll(ctx)
+	return this.Database.DeleteVariablesOfProcessInstance(ctx, instanceId)
+}
+
+// Ping forwards to the wrapped database, if it implements database.Pinger
+func (this *Cache) Ping(ctx context.Context) error {
+	if pinger, ok := this.Database.(database.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+// get returns the cached variable
+func (this *Cache) get(key entryKey) (result model.VariableWithUser, ok bool, err error) {
+	e, ok := this.lookup(key)
+	if !ok {
+		return result, false, nil
+	}
+	result = e.variable
+	err = json.Unmarshal(e.jsonValue, &result.Value)
+	return result, true, err
+}
+
+// lookup returns the cached entry; expired cache entries and expired variables are removed
+func (this *Cache) lookup(key entryKey) (e *entry, ok bool) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	element, ok := this.entries[key]
+	if !ok {
+		this.metrics.LogCacheMiss()
+		return nil, false
+	}
+	e = element.Value.(*entry)
+	now := configuration.TimeNow()
+	if now.Sub(e.storedAt) > this.ttl || e.variable.IsExpired(now.Unix()) {
+		this.remove(element)
+		this.metrics.LogCacheMiss()
+		return nil, false
+	}
+	this.lru.MoveToFront(element)
+	this.metrics.LogCacheHit()
+	return e, true
+}
+
+// put caches the variable, see store
+func (this *Cache) put(epoch uint64, variable model.VariableWithUser) error {
+	jsonValue, err := json.Marshal(variable.Value)
+	if err != nil {
+		return err
+	}
+	variable.Value = nil
+	this.store(epoch, &entry{key: entryKey{userId: variable.UserId, key: variable.Key}, variable: variable, jsonValue: jsonValue})
+	return nil
+}
+
+// store caches the entry, if no invalidation happened since epoch was read; evicts the least recently used entry if the cache is full
+func (this *Cache) store(epoch uint64, e *entry) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	if epoch != this.epoch {
+		return
+	}
+	if element, ok := this.entries[e.key]; ok {
+		this.remove(element)
+	}
+	e.storedAt = configuration.TimeNow()
+	this.entries[e.key] = this.lru.PushFront(e)
+	for this.lru.Len() > this.size {
+		this.remove(this.lru.Back())
+	}
+}
+
+// remove deletes the element from the cache; expects the caller to hold the lock
+func (this *Cache) remove(element *list.Element) {
+	this.lru.Remove(element)
+	delete(this.entries, element.Value.(*entry).key)
+}
+
+func (this *Cache) getEpoch() uint64 {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	return this.epoch
+}
+
+// invalidate removes the variable from the cache; keys changed in transactions are removed again when the transaction ends
+func (this *Cache) invalidate(ctx context.Context, userId string, key string) {
+	if tx := getTransaction(ctx); tx != nil {
+		tx.add(entryKey{userId: userId, key: key})
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.epoch++
+	if element, ok := this.entries[entryKey{userId: userId, key: key}]; ok {
+		this.remove(element)
+	}
+}
+
+// invalidateAll clears the cache, for writes with unknown affected variables
+func (this *Cache) invalidateAll(ctx context.Context) {
+	if tx := getTransaction(ctx); tx != nil {
+		tx.addAll()
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.epoch++
+	this.entries = map[entryKey]*list.Element{}
+	this.lru.Init()
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cache
+
+import (
+	"context"
+	"sync"
+)
+
+type txContextKey struct{}
+
+// transaction records the variables changed while the transaction is running;
+// they are invalidated again when the transaction ends, because concurrent reads may cache the previous state until the commit
+type transaction struct {
+	mux  sync.Mutex
+	keys []entryKey
+	all  bool
+}
+
+func (this *transaction) add(key entryKey) {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.keys = append(this.keys, key)
+}
+
+func (this *transaction) addAll() {
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	this.all = true
+}
+
+func getTransaction(ctx context.Context) *transaction {
+	tx, _ := ctx.Value(txContextKey{}).(*transaction)
+	return tx
+}
+
+// Transaction runs f in a transaction of the wrapped database; reads in f bypass the cache.
+// nested calls reuse the outer transaction.
+func (this *Cache) Transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	if getTransaction(ctx) != nil {
+		return this.Database.Transaction(ctx, f)
+	}
+	tx := &transaction{}
+	defer func() {
+		background := context.Background()
+		if tx.all {
+			this.invalidateAll(background)
+			return
+		}
+		for _, key := range tx.keys {
+			this.invalidate(background, key.userId, key.key)
+		}
+	}()
+	return this.Database.Transaction(context.WithValue(ctx, txContextKey{}, tx), f)
+}
@@ -20,6 +20,8 @@ import (
 	"context"
 	"errors"
 	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/bolt"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/memory"
 	"github.com/SENERGY-Platform/process-io-api/pkg/database/mongo"
 	"github.com/SENERGY-Platform/process-io-api/pkg/database/postgres"
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
@@ -27,13 +29,41 @@ import (
 )
 
 type Database interface {
-	GetVariable(userId string, key string) (model.VariableWithUser, error)
-	SetVariable(variable model.VariableWithUser) error
-	DeleteVariable(userId string, key string) error
-	ListVariables(userId string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
-	DeleteVariablesOfProcessDefinition(definitionId string) error
-	DeleteVariablesOfProcessInstance(instanceId string) error
-	CountVariables(userId string, query model.VariablesQueryOptions) (model.Count, error)
+	GetVariable(ctx context.Context, userId string, key string) (model.VariableWithUser, error)
+	GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (model.VariableWithUser, error)
+	SetVariable(ctx context.Context, variable model.VariableWithUser) error
+	SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (newRevision int64, ok bool, err error) //ok is false if the stored revision is not revision
+	IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (model.VariableWithUser, error)
+	UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (model.VariableWithUser, error)
+	DeleteVariable(ctx context.Context, userId string, key string) error
+	ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) ([]model.VariableWithUnixTimestamp, error)
+	ListUserIds(ctx context.Context) ([]string, error) //ids of all users with stored variables, sorted
+	//DeleteVariables deletes the not expired variables of the user matching the filters of the query and their history;
+	//returns the number of deleted variables
+	DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (int64, error)
+	DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (int64, error)
+	DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (int64, error)
+	CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (model.Count, error)
+	AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error
+	ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) ([]model.VariableVersion, error)
+	GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (model.VariableVersion, error)
+	GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (model.VariableVersion, error)
+	PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error
+	Transaction(ctx context.Context, f func(ctx context.Context) error) error
+	SetShare(ctx context.Context, share model.Share) error                                    //creates or replaces the share with the id of share
+	DeleteShare(ctx context.Context, ownerId string, id string) (bool, error)                 //false if the owner has no share with the id
+	ListShares(ctx context.Context, ownerId string) ([]model.Share, error)                    //shares of the owner, sorted by id
+	ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) //shares granted to the user or one of the groups, sorted by id
+	GetUsage(ctx context.Context, userId string) (model.Usage, error)                         //not expired variables of the user and the sum of their value sizes
+	GetQuota(ctx context.Context, userId string) (quota model.Quota, found bool, err error)   //found is false if the user has no quota override
+	SetQuota(ctx context.Context, quota model.Quota) error                                    //creates or replaces the quota of quota.UserId
+	DeleteQuota(ctx context.Context, userId string) error                                     //no error if the user has no quota override
+}
+
+// Pinger is optionally implemented by databases to check if they are reachable;
+// databases without Pinger implementation are treated as always reachable
+type Pinger interface {
+	Ping(ctx context.Context) error
 }
 
 func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (Database, error) {
@@ -42,6 +72,10 @@ func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (
 		return mongo.New(ctx, wg, config)
 	case "postgres":
 		return postgres.New(ctx, wg, config)
+	case "memory":
+		return memory.New(ctx, wg, config)
+	case "bolt":
+		return bolt.New(ctx, wg, config)
 	default:
 		return nil, errors.New("unknown database: " + config.DatabaseSelection)
 	}
@@ -0,0 +1,52 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// startExpirySweeper periodically deletes expired variables and their history until ctx is done;
+// reads already ignore expired variables, the sweeper only frees the memory
+func (this *Memory) startExpirySweeper(ctx context.Context, wg *sync.WaitGroup, interval time.Duration) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				this.deleteExpiredVariables(ctx)
+			}
+		}
+	}()
+}
+
+// deleteExpiredVariables deletes all expired variables and their history
+func (this *Memory) deleteExpiredVariables(ctx context.Context) {
+	defer this.lock(ctx)()
+	for userId, variables := range this.variables {
+		for key := range variables {
+			this.removeExpired(ctx, userId, key)
+		}
+	}
+}
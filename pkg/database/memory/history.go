@@ -0,0 +1,139 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// versionEntry stores the value as json, like entry
+type versionEntry struct {
+	version   model.VariableVersion
+	jsonValue []byte
+}
+
+func (this versionEntry) get() (result model.VariableVersion, err error) {
+	result = this.version
+	err = json.Unmarshal(this.jsonValue, &result.Value)
+	return result, err
+}
+
+func (this *Memory) AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error {
+	e := versionEntry{version: version}
+	var err error
+	e.jsonValue, err = json.Marshal(version.Value)
+	if err != nil {
+		return err
+	}
+	e.version.Value = nil
+	defer this.lock(ctx)()
+	versions := []versionEntry{}
+	for _, existing := range this.history[userId][version.Key] {
+		if existing.version.Revision != version.Revision {
+			versions = append(versions, existing)
+		}
+	}
+	versions = append(versions, e)
+	sort.SliceStable(versions, func(i, j int) bool {
+		return versions[i].version.Revision < versions[j].version.Revision
+	})
+	this.putHistory(ctx, userId, version.Key, versions)
+	return nil
+}
+
+func (this *Memory) ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	defer this.rlock(ctx)()
+	versions := this.history[userId][key]
+	for i := len(versions) - 1; i >= 0; i-- {
+		version, err := versions[i].get()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	return util.PaginateVersions(result, query), nil
+}
+
+func (this *Memory) GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (result model.VariableVersion, err error) {
+	defer this.rlock(ctx)()
+	for _, e := range this.history[userId][key] {
+		if e.version.Revision == revision {
+			return e.get()
+		}
+	}
+	return result, model.ErrVersionNotFound
+}
+
+func (this *Memory) GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (result model.VariableVersion, err error) {
+	defer this.rlock(ctx)()
+	versions := this.history[userId][key]
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].version.UnixTimestampInS <= unixTimestampInS {
+			return versions[i].get()
+		}
+	}
+	return result, model.ErrVersionNotFound
+}
+
+func (this *Memory) PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error {
+	defer this.lock(ctx)()
+	this.deleteVersionsWhere(ctx, userId, key, func(version model.VariableVersion) bool {
+		return util.IsPruned(version, currentRevision, retention)
+	})
+	return nil
+}
+
+// deleteVersionsWhere removes the matching versions of the variable; expects the caller to hold the write lock
+func (this *Memory) deleteVersionsWhere(ctx context.Context, userId string, key string, condition func(version model.VariableVersion) bool) {
+	versions := []versionEntry{}
+	for _, e := range this.history[userId][key] {
+		if !condition(e.version) {
+			versions = append(versions, e)
+		}
+	}
+	if len(versions) != len(this.history[userId][key]) {
+		this.putHistory(ctx, userId, key, versions)
+	}
+}
+
+// putHistory replaces (or removes if versions is empty) the history of the variable; expects the caller to hold the write lock
+func (this *Memory) putHistory(ctx context.Context, userId string, key string, versions []versionEntry) {
+	if tx := getTransaction(ctx); tx != nil {
+		old := this.history[userId][key]
+		tx.undo = append(tx.undo, func() {
+			this.putHistory(context.Background(), userId, key, old)
+		})
+	}
+	if len(versions) == 0 {
+		delete(this.history[userId], key)
+		if len(this.history[userId]) == 0 {
+			delete(this.history, userId)
+		}
+		return
+	}
+	userHistory, ok := this.history[userId]
+	if !ok {
+		userHistory = map[string][]versionEntry{}
+		this.history[userId] = userHistory
+	}
+	userHistory[key] = versions
+}
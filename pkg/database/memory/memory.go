@@ -0,0 +1,50 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"sync"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// New creates a Database implementation which keeps all variables in process memory.
+// nothing is persisted; intended for tests and local development
+func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (*Memory, error) {
+	result := &Memory{
+		config:    config,
+		variables: map[string]map[string]entry{},
+		revisions: map[string]map[string]int64{},
+		history:   map[string]map[string][]versionEntry{},
+		shares:    map[string]model.Share{},
+		quotas:    map[string]model.Quota{},
+	}
+	result.startExpirySweeper(ctx, wg, config.GetExpirySweepInterval())
+	return result, nil
+}
+
+type Memory struct {
+	config    configuration.Config
+	mux       sync.RWMutex
+	variables map[string]map[string]entry          //user-id -> key -> entry
+	revisions map[string]map[string]int64          //user-id -> key -> last revision of removed variables, see nextRevision
+	history   map[string]map[string][]versionEntry //user-id -> key -> versions sorted by revision
+	shares    map[string]model.Share               //share-id -> share
+	quotas    map[string]model.Quota               //user-id -> quota override
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// quotas are not part of transactions; changes are not reverted if a transaction fails
+
+func (this *Memory) GetUsage(ctx context.Context, userId string) (result model.Usage, err error) {
+	list, err := this.find(ctx, userId, model.VariablesQueryOptions{})
+	if err != nil {
+		return result, err
+	}
+	for _, variable := range list {
+		size, err := model.ValueSize(variable.Value)
+		if err != nil {
+			return result, err
+		}
+		result.Variables++
+		result.TotalBytes += size
+	}
+	return result, nil
+}
+
+func (this *Memory) GetQuota(ctx context.Context, userId string) (model.Quota, bool, error) {
+	defer this.rlock(ctx)()
+	quota, ok := this.quotas[userId]
+	return quota, ok, nil
+}
+
+func (this *Memory) SetQuota(ctx context.Context, quota model.Quota) error {
+	defer this.lock(ctx)()
+	this.quotas[quota.UserId] = quota
+	return nil
+}
+
+func (this *Memory) DeleteQuota(ctx context.Context, userId string) error {
+	defer this.lock(ctx)()
+	delete(this.quotas, userId)
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// shares are not part of transactions; changes are not reverted if a transaction fails
+
+func (this *Memory) SetShare(ctx context.Context, share model.Share) error {
+	defer this.lock(ctx)()
+	this.shares[share.Id] = share
+	return nil
+}
+
+func (this *Memory) DeleteShare(ctx context.Context, ownerId string, id string) (bool, error) {
+	defer this.lock(ctx)()
+	share, ok := this.shares[id]
+	if !ok || share.OwnerId != ownerId {
+		return false, nil
+	}
+	delete(this.shares, id)
+	return true, nil
+}
+
+func (this *Memory) ListShares(ctx context.Context, ownerId string) ([]model.Share, error) {
+	return this.listShares(ctx, func(share model.Share) bool {
+		return share.OwnerId == ownerId
+	}), nil
+}
+
+func (this *Memory) ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) {
+	return this.listShares(ctx, func(share model.Share) bool {
+		return (share.UserId != "" && share.UserId == userId) || (share.Group != "" && slices.Contains(groups, share.Group))
+	}), nil
+}
+
+func (this *Memory) listShares(ctx context.Context, filter func(share model.Share) bool) []model.Share {
+	defer this.rlock(ctx)()
+	result := []model.Share{}
+	for _, share := range this.shares {
+		if filter(share) {
+			result = append(result, share)
+		}
+	}
+	slices.SortFunc(result, func(a, b model.Share) int {
+		return strings.Compare(a.Id, b.Id)
+	})
+	return result
+}
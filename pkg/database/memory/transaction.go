@@ -0,0 +1,118 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+)
+
+type txContextKey struct{}
+
+// transaction records how to revert each change made while the transaction is running
+type transaction struct {
+	undo []func()
+}
+
+// Transaction runs f while holding the write lock; all methods called with the context passed to f skip locking
+// and record their changes, which are reverted if f returns an error.
+// nested calls reuse the outer transaction.
+func (this *Memory) Transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	if getTransaction(ctx) != nil {
+		return f(ctx)
+	}
+	this.mux.Lock()
+	defer this.mux.Unlock()
+	tx := &transaction{}
+	err := f(context.WithValue(ctx, txContextKey{}, tx))
+	if err != nil {
+		for i := len(tx.undo) - 1; i >= 0; i-- {
+			tx.undo[i]()
+		}
+	}
+	return err
+}
+
+func getTransaction(ctx context.Context) *transaction {
+	tx, _ := ctx.Value(txContextKey{}).(*transaction)
+	return tx
+}
+
+// lock acquires the write lock, if the context is not part of a transaction (which already holds the lock)
+func (this *Memory) lock(ctx context.Context) (unlock func()) {
+	if getTransaction(ctx) != nil {
+		return func() {}
+	}
+	this.mux.Lock()
+	return this.mux.Unlock
+}
+
+// rlock acquires the read lock, if the context is not part of a transaction (which already holds the lock)
+func (this *Memory) rlock(ctx context.Context) (unlock func()) {
+	if getTransaction(ctx) != nil {
+		return func() {}
+	}
+	this.mux.RLock()
+	return this.mux.RUnlock
+}
+
+// put stores (or removes if e is nil) the entry; expects the caller to hold the write lock
+func (this *Memory) put(ctx context.Context, userId string, key string, e *entry) {
+	if tx := getTransaction(ctx); tx != nil {
+		old, existed := this.variables[userId][key]
+		removedRevision := this.revisions[userId][key]
+		tx.undo = append(tx.undo, func() {
+			if existed {
+				this.put(context.Background(), userId, key, &old)
+			} else {
+				this.put(context.Background(), userId, key, nil)
+			}
+			//reverted writes must not count as removed revisions
+			this.setRemovedRevision(userId, key, removedRevision)
+		})
+	}
+	if e == nil {
+		if old, ok := this.variables[userId][key]; ok {
+			this.setRemovedRevision(userId, key, max(this.revisions[userId][key], old.variable.Revision))
+		}
+		delete(this.variables[userId], key)
+		if len(this.variables[userId]) == 0 {
+			delete(this.variables, userId)
+		}
+		return
+	}
+	userVariables, ok := this.variables[userId]
+	if !ok {
+		userVariables = map[string]entry{}
+		this.variables[userId] = userVariables
+	}
+	userVariables[key] = *e
+}
+
+// setRemovedRevision remembers the last revision of a removed variable (see nextRevision); expects the caller to hold the write lock
+func (this *Memory) setRemovedRevision(userId string, key string, revision int64) {
+	if revision == 0 {
+		delete(this.revisions[userId], key)
+		if len(this.revisions[userId]) == 0 {
+			delete(this.revisions, userId)
+		}
+		return
+	}
+	if this.revisions[userId] == nil {
+		this.revisions[userId] = map[string]int64{}
+	}
+	this.revisions[userId][key] = revision
+}
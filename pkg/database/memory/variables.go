@@ -0,0 +1,301 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// entry stores the value as json to decouple stored values from the callers references
+// and to return the same value types as the other database implementations
+type entry struct {
+	variable  model.VariableWithUser
+	jsonValue []byte
+}
+
+func newEntry(variable model.VariableWithUser) (result entry, err error) {
+	result.jsonValue, err = json.Marshal(variable.Value)
+	if err != nil {
+		return result, err
+	}
+	variable.Value = nil
+	result.variable = variable
+	return result, nil
+}
+
+func (this entry) get() (result model.VariableWithUser, err error) {
+	result = this.variable
+	err = json.Unmarshal(this.jsonValue, &result.Value)
+	return result, err
+}
+
+// live returns the entry of the variable, if it exists and is not expired; expects the caller to hold the lock
+func (this *Memory) live(userId string, key string) (entry, bool) {
+	e, ok := this.variables[userId][key]
+	if !ok || e.variable.IsExpired(configuration.TimeNow().Unix()) {
+		return entry{}, false
+	}
+	return e, true
+}
+
+// removeExpired deletes the variable and its history, if the variable is expired,
+// so that following writes start with a new history; expects the caller to hold the write lock
+func (this *Memory) removeExpired(ctx context.Context, userId string, key string) {
+	e, ok := this.variables[userId][key]
+	if ok && e.variable.IsExpired(configuration.TimeNow().Unix()) {
+		this.put(ctx, userId, key, nil)
+		this.putHistory(ctx, userId, key, nil)
+	}
+}
+
+// nextRevision returns the revision of the next write of the variable; revisions of removed variables are remembered,
+// so that recreated variables continue with higher revisions and outdated ETags do not match; expects the caller to hold the lock
+func (this *Memory) nextRevision(userId string, key string) int64 {
+	return max(this.variables[userId][key].variable.Revision, this.revisions[userId][key]) + 1
+}
+
+func (this *Memory) GetVariable(ctx context.Context, userId string, key string) (result model.VariableWithUser, err error) {
+	defer this.rlock(ctx)()
+	e, ok := this.live(userId, key)
+	if !ok {
+		return model.VariableWithUser{
+			VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
+				Variable: model.Variable{
+					Key:                 key,
+					Value:               nil,
+					ProcessDefinitionId: "",
+					ProcessInstanceId:   "",
+				},
+				UnixTimestampInS: 0,
+			},
+			UserId: userId,
+		}, nil
+	}
+	return e.get()
+}
+
+func (this *Memory) GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (model.VariableWithUser, error) {
+	variable, err := this.GetVariable(ctx, userId, key)
+	if err != nil {
+		return variable, err
+	}
+	return util.SelectPointer(variable, pointer)
+}
+
+func (this *Memory) SetVariable(ctx context.Context, variable model.VariableWithUser) error {
+	e, err := newEntry(variable)
+	if err != nil {
+		return err
+	}
+	defer this.lock(ctx)()
+	this.removeExpired(ctx, variable.UserId, variable.Key)
+	e.variable.Revision = this.nextRevision(variable.UserId, variable.Key)
+	this.put(ctx, variable.UserId, variable.Key, &e)
+	return nil
+}
+
+func (this *Memory) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (int64, bool, error) {
+	e, err := newEntry(variable)
+	if err != nil {
+		return 0, false, err
+	}
+	defer this.lock(ctx)()
+	this.removeExpired(ctx, variable.UserId, variable.Key)
+	if this.variables[variable.UserId][variable.Key].variable.Revision != revision {
+		return 0, false, nil
+	}
+	e.variable.Revision = this.nextRevision(variable.UserId, variable.Key)
+	this.put(ctx, variable.UserId, variable.Key, &e)
+	return e.variable.Revision, true, nil
+}
+
+func (this *Memory) IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (result model.VariableWithUser, err error) {
+	defer this.lock(ctx)()
+	this.removeExpired(ctx, variable.UserId, variable.Key)
+	old, exists := this.variables[variable.UserId][variable.Key]
+	if exists {
+		current, err := old.get()
+		if err != nil {
+			return result, err
+		}
+		number, ok := current.Value.(float64)
+		if !ok {
+			return result, model.ErrNotNumeric
+		}
+		variable.ProcessDefinitionId = current.ProcessDefinitionId
+		variable.ProcessInstanceId = current.ProcessInstanceId
+		variable.ExpiresAt = current.ExpiresAt
+		delta = number + delta
+	}
+	variable.Value = delta
+	variable.Revision = this.nextRevision(variable.UserId, variable.Key)
+	e, err := newEntry(variable)
+	if err != nil {
+		return result, err
+	}
+	this.put(ctx, variable.UserId, variable.Key, &e)
+	return e.get()
+}
+
+func (this *Memory) UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (result model.VariableWithUser, err error) {
+	defer this.lock(ctx)()
+	this.removeExpired(ctx, userId, key)
+	current := model.VariableWithUser{VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: key}}, UserId: userId}
+	if old, exists := this.variables[userId][key]; exists {
+		current, err = old.get()
+		if err != nil {
+			return result, err
+		}
+	}
+	result, err = update(current)
+	if err != nil {
+		return result, err
+	}
+	result.Revision = this.nextRevision(userId, key)
+	e, err := newEntry(result)
+	if err != nil {
+		return result, err
+	}
+	this.put(ctx, userId, key, &e)
+	return result, nil
+}
+
+func (this *Memory) DeleteVariable(ctx context.Context, userId string, key string) error {
+	defer this.lock(ctx)()
+	this.put(ctx, userId, key, nil)
+	this.putHistory(ctx, userId, key, nil)
+	return nil
+}
+
+func (this *Memory) ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	result, err = this.find(ctx, userId, query)
+	if err != nil {
+		return nil, err
+	}
+	err = util.SortVariables(result, query)
+	if err != nil {
+		return nil, err
+	}
+	return util.Paginate(result, query), nil
+}
+
+func (this *Memory) CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
+	list, err := this.find(ctx, userId, query)
+	if err != nil {
+		return result, err
+	}
+	result.Count = int64(len(list))
+	return result, nil
+}
+
+func (this *Memory) find(ctx context.Context, userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	match, err := util.NewMatcher(query)
+	if err != nil {
+		return nil, err
+	}
+	defer this.rlock(ctx)()
+	now := configuration.TimeNow().Unix()
+	for _, e := range this.variables[userId] {
+		if e.variable.IsExpired(now) {
+			continue
+		}
+		variable, err := e.get()
+		if err != nil {
+			return nil, err
+		}
+		if match(variable.VariableWithUnixTimestamp) {
+			result = append(result, variable.VariableWithUnixTimestamp)
+		}
+	}
+	return result, nil
+}
+
+func (this *Memory) ListUserIds(ctx context.Context) (result []string, err error) {
+	defer this.rlock(ctx)()
+	for userId := range this.variables {
+		result = append(result, userId)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// DeleteVariables removes the not expired variables of the user matching the filters of the query and their history
+func (this *Memory) DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (count int64, err error) {
+	match, err := util.NewMatcher(query)
+	if err != nil {
+		return 0, err
+	}
+	defer this.lock(ctx)()
+	now := configuration.TimeNow().Unix()
+	for key, e := range this.variables[userId] {
+		if e.variable.IsExpired(now) {
+			continue
+		}
+		variable, err := e.get()
+		if err != nil {
+			return count, err
+		}
+		if match(variable.VariableWithUnixTimestamp) {
+			this.put(ctx, userId, key, nil)
+			this.putHistory(ctx, userId, key, nil)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (this *Memory) DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (int64, error) {
+	return this.deleteWhere(ctx, func(variable model.VariableWithUser) bool {
+		return variable.ProcessDefinitionId == definitionId
+	})
+}
+
+func (this *Memory) DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (int64, error) {
+	return this.deleteWhere(ctx, func(variable model.VariableWithUser) bool {
+		return variable.ProcessInstanceId == instanceId
+	})
+}
+
+// deleteWhere removes the matching variables and the matching versions of the history; returns the number of removed not expired variables
+func (this *Memory) deleteWhere(ctx context.Context, condition func(variable model.VariableWithUser) bool) (count int64, err error) {
+	defer this.lock(ctx)()
+	now := configuration.TimeNow().Unix()
+	for userId, userVariables := range this.variables {
+		for key, e := range userVariables {
+			if condition(e.variable) {
+				this.put(ctx, userId, key, nil)
+				if !e.variable.IsExpired(now) {
+					count++
+				}
+			}
+		}
+	}
+	for userId, userHistory := range this.history {
+		for key := range userHistory {
+			this.deleteVersionsWhere(ctx, userId, key, func(version model.VariableVersion) bool {
+				return condition(model.VariableWithUser{VariableWithUnixTimestamp: version.VariableWithUnixTimestamp, UserId: userId})
+			})
+		}
+	}
+	return count, nil
+}
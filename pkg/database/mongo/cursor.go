@@ -0,0 +1,68 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// variableSortPaths maps the sortable fields of variables to document paths
+var variableSortPaths = map[string]string{
+	model.SortFieldKey:                 VariableBson.Key,
+	model.SortFieldTimestamp:           VariableUnixTimestampBson,
+	model.SortFieldProcessDefinitionId: VariableBson.ProcessDefinitionId,
+	model.SortFieldProcessInstanceId:   VariableBson.ProcessInstanceId,
+}
+
+// getSortPath returns the document path of the sort key
+func getSortPath(key model.SortKey) (string, error) {
+	if key.Field != model.SortFieldValue {
+		return variableSortPaths[key.Field], nil
+	}
+	pointer, err := util.ParseSortPointer(key)
+	if err != nil {
+		return "", err
+	}
+	return getValuePath(pointer)
+}
+
+// addCursor adds the condition selecting the variables sorted after the cursor of the query:
+// for every sort key, the variables with equal preceding sort fields and a following field
+func addCursor(filter bson.M, query model.VariablesQueryOptions) error {
+	cursor, err := util.ParseCursor(query)
+	if err != nil || cursor == nil {
+		return err
+	}
+	alternatives := bson.A{}
+	for i, key := range cursor.Sort {
+		condition := bson.M{}
+		for j := 0; j < i; j++ {
+			condition[variableSortPaths[cursor.Sort[j].Field]] = cursor.Values[j]
+		}
+		operator := "$gt"
+		if key.Desc {
+			operator = "$lt"
+		}
+		condition[variableSortPaths[key.Field]] = bson.M{operator: cursor.Values[i]}
+		alternatives = append(alternatives, condition)
+	}
+	conditions, _ := filter["$and"].(bson.A)
+	filter["$and"] = append(conditions, bson.M{"$or": alternatives})
+	return nil
+}
@@ -18,34 +18,22 @@ package mongo
 
 import (
 	"context"
-	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
-	"strings"
 )
 
 type QueryOptions interface {
 	GetLimit() int64
 	GetOffset() int64
-	GetSort() string
 }
 
+// createFindOptions sets limit and offset; sorts are set by the caller
 func createFindOptions(query QueryOptions) *options.FindOptions {
 	opt := options.Find()
 	if query.GetLimit() > 0 {
 		opt.SetLimit(query.GetLimit())
 	}
 	opt.SetSkip(query.GetOffset())
-
-	sortby := query.GetSort()
-	sortby = strings.TrimSuffix(sortby, ".asc")
-	sortby = strings.TrimSuffix(sortby, ".desc")
-
-	direction := int32(1)
-	if strings.HasSuffix(query.GetSort(), ".desc") {
-		direction = int32(-1)
-	}
-	opt.SetSort(bson.D{{sortby, direction}})
 	return opt
 }
 
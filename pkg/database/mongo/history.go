@@ -0,0 +1,160 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type VersionDocument struct {
+	Version model.VariableVersion
+	UserId  string
+}
+
+var VersionBson = getBsonFieldObject[VersionDocument]()
+var VersionRevisionBson = mustGetBsonFieldPath(VersionDocument{}, "Version.Revision")
+var VersionUnixTimestampBson = mustGetBsonFieldPath(VersionDocument{}, "Version.VariableWithUnixTimestamp.UnixTimestampInS")
+
+func init() {
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		var err error
+		collection := db.historyCollection()
+		err = db.ensureCompoundIndex(collection, "history_user_key_revision_index", true, true, VersionBson.UserId, VersionBson.Version.Key, VersionRevisionBson)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureIndex(collection, "history_p_instance_index", VersionBson.Version.ProcessInstanceId, true, false)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureIndex(collection, "history_p_definition_index", VersionBson.Version.ProcessDefinitionId, true, false)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		return nil
+	})
+}
+
+func (this *Mongo) historyCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoHistoryCollection)
+}
+
+func (this *Mongo) AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	if version.Revision == 1 {
+		// the first revision starts a new history; remaining versions belong to a variable removed by the ttl index
+		_, err := this.historyCollection().DeleteMany(ctx, bson.M{
+			VersionBson.UserId:      userId,
+			VersionBson.Version.Key: version.Key,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	_, err := this.historyCollection().ReplaceOne(
+		ctx,
+		bson.M{
+			VersionBson.UserId:      userId,
+			VersionBson.Version.Key: version.Key,
+			VersionRevisionBson:     version.Revision,
+		},
+		VersionDocument{Version: version, UserId: userId},
+		options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *Mongo) ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	opt := options.Find().SetSort(bson.D{{Key: VersionRevisionBson, Value: -1}}).SetSkip(int64(query.Offset))
+	if query.Limit > 0 {
+		opt.SetLimit(int64(query.Limit))
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	cursor, err := this.historyCollection().Find(ctx, bson.M{VersionBson.UserId: userId, VersionBson.Version.Key: key}, opt)
+	if err != nil {
+		return result, err
+	}
+	defer cursor.Close(context.Background())
+	temp, err := readCursorResult[VersionDocument](ctx, cursor)
+	if err != nil {
+		return result, err
+	}
+	for _, e := range temp {
+		result = append(result, e.Version)
+	}
+	return result, nil
+}
+
+func (this *Mongo) GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (model.VariableVersion, error) {
+	return this.findVersion(ctx, bson.M{VersionBson.UserId: userId, VersionBson.Version.Key: key, VersionRevisionBson: revision})
+}
+
+func (this *Mongo) GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (model.VariableVersion, error) {
+	return this.findVersion(ctx, bson.M{VersionBson.UserId: userId, VersionBson.Version.Key: key, VersionUnixTimestampBson: bson.M{"$lte": unixTimestampInS}})
+}
+
+// findVersion returns the newest version matching the filter
+func (this *Mongo) findVersion(ctx context.Context, filter bson.M) (result model.VariableVersion, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	document := VersionDocument{}
+	err = this.historyCollection().FindOne(ctx, filter, options.FindOne().SetSort(bson.D{{Key: VersionRevisionBson, Value: -1}})).Decode(&document)
+	if err == mongo.ErrNoDocuments {
+		return result, model.ErrVersionNotFound
+	}
+	return document.Version, err
+}
+
+// PruneVariableHistory mirrors util.IsPruned
+func (this *Mongo) PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error {
+	conditions := bson.A{}
+	if retention.MaxVersions > 0 {
+		conditions = append(conditions, bson.M{VersionRevisionBson: bson.M{"$lte": currentRevision - retention.MaxVersions}})
+	}
+	if retention.MinUnixTimestampInS > 0 {
+		conditions = append(conditions, bson.M{VersionUnixTimestampBson: bson.M{"$lt": retention.MinUnixTimestampInS}})
+	}
+	if len(conditions) == 0 {
+		return nil
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.historyCollection().DeleteMany(ctx, bson.M{
+		VersionBson.UserId:      userId,
+		VersionBson.Version.Key: key,
+		VersionRevisionBson:     bson.M{"$lt": currentRevision},
+		"$or":                   conditions,
+	})
+	return err
+}
+
+func (this *Mongo) deleteVersions(ctx context.Context, filter bson.M) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.historyCollection().DeleteMany(ctx, filter)
+	return err
+}
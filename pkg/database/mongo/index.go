@@ -17,6 +17,7 @@
 package mongo
 
 import (
+	"context"
 	"errors"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsoncodec"
@@ -35,6 +36,15 @@ func getBsonFieldObject[T any]() T {
 	return *v
 }
 
+// mustGetBsonFieldPath is getBsonFieldPath for fields that are not strings and therefore not filled by getBsonFieldObject
+func mustGetBsonFieldPath(obj interface{}, path string) string {
+	result, err := getBsonFieldPath(obj, path)
+	if err != nil {
+		panic(err)
+	}
+	return result
+}
+
 func fillObjectWithItsBsonFieldNames(ptr interface{}, prefix []string) error {
 	ptrval := reflect.ValueOf(ptr)
 	objval := reflect.Indirect(ptrval)
@@ -96,7 +106,8 @@ func getBsonFieldName(obj interface{}, fieldName string) (bsonName string, err e
 }
 
 func (this *Mongo) ensureCompoundIndex(collection *mongo.Collection, indexname string, asc bool, unique bool, indexKeys ...string) error {
-	ctx, _ := getTimeoutContext()
+	ctx, cancel := this.getTimeoutContext(context.Background())
+	defer cancel()
 	var direction int32 = -1
 	if asc {
 		direction = 1
@@ -113,18 +124,30 @@ func (this *Mongo) ensureCompoundIndex(collection *mongo.Collection, indexname s
 }
 
 func (this *Mongo) ensureIndex(collection *mongo.Collection, indexname string, indexKey string, asc bool, unique bool) error {
-	ctx, _ := getTimeoutContext()
+	ctx, cancel := this.getTimeoutContext(context.Background())
+	defer cancel()
 	var direction int32 = -1
 	if asc {
 		direction = 1
 	}
 	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
-		Keys:    bson.D{{indexKey, direction}},
+		Keys:    bson.D{{Key: indexKey, Value: direction}},
 		Options: options.Index().SetName(indexname).SetUnique(unique),
 	})
 	return err
 }
 
+// ensureTtlIndex creates an index which lets mongodb delete documents once the date in indexKey is reached
+func (this *Mongo) ensureTtlIndex(collection *mongo.Collection, indexname string, indexKey string) error {
+	ctx, cancel := this.getTimeoutContext(context.Background())
+	defer cancel()
+	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: indexKey, Value: 1}},
+		Options: options.Index().SetName(indexname).SetExpireAfterSeconds(0),
+	})
+	return err
+}
+
 func (this *Mongo) ensureTextIndex(collection *mongo.Collection, indexname string, indexKeys ...string) error {
 	if len(indexKeys) == 0 {
 		return errors.New("expect at least one key")
@@ -133,7 +156,8 @@ func (this *Mongo) ensureTextIndex(collection *mongo.Collection, indexname strin
 	for _, key := range indexKeys {
 		keys = append(keys, bson.E{Key: key, Value: "text"})
 	}
-	ctx, _ := getTimeoutContext()
+	ctx, cancel := this.getTimeoutContext(context.Background())
+	defer cancel()
 	_, err := collection.Indexes().CreateOne(ctx, mongo.IndexModel{
 		Keys:    keys,
 		Options: options.Index().SetName(indexname),
@@ -18,11 +18,13 @@ package mongo
 
 import (
 	"context"
+	"errors"
 	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/bsontype"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 	"reflect"
 	"sync"
 	"time"
@@ -31,14 +33,24 @@ import (
 var CreateCollections = []func(db *Mongo) error{}
 
 func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (*Mongo, error) {
-	connectCtx, _ := context.WithTimeout(ctx, 10*time.Second)
+	connectCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 	reg := bson.NewRegistryBuilder().RegisterTypeMapEntry(bsontype.EmbeddedDocument, reflect.TypeOf(bson.M{})).Build() //ensure map marshalling to interface
 	client, err := mongo.Connect(connectCtx, options.Client().ApplyURI(config.MongoUrl), options.Client().SetRegistry(reg))
 	if err != nil {
 		return nil, err
 	}
 
-	db := &Mongo{config: config, client: client}
+	db := &Mongo{config: config, client: client, timeout: config.GetDatabaseTimeout()}
+	db.transactions, err = db.supportsTransactions(connectCtx)
+	if err != nil {
+		client.Disconnect(context.Background())
+		return nil, err
+	}
+	if config.HistoryEnabled && !db.transactions {
+		client.Disconnect(context.Background())
+		return nil, errors.New("history_enabled requires mongodb to run as replica set or sharded cluster, to support transactions")
+	}
 	for _, creators := range CreateCollections {
 		err = creators(db)
 		if err != nil {
@@ -57,11 +69,19 @@ func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (
 	return db, nil
 }
 
-func getTimeoutContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+// getTimeoutContext limits the given (request) context to the configured database timeout
+func (this *Mongo) getTimeoutContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, this.timeout)
 }
 
 type Mongo struct {
-	config configuration.Config
-	client *mongo.Client
+	config       configuration.Config
+	client       *mongo.Client
+	timeout      time.Duration
+	transactions bool //mongodb runs as replica set or sharded cluster, see supportsTransactions
+}
+
+// Ping checks if the primary of the mongodb is reachable
+func (this *Mongo) Ping(ctx context.Context) error {
+	return this.client.Ping(ctx, readpref.Primary())
 }
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var QuotaBson = getBsonFieldObject[model.Quota]()
+
+func init() {
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		err := db.ensureIndex(db.quotasCollection(), "quota_user_index", QuotaBson.UserId, true, true)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		return nil
+	})
+}
+
+func (this *Mongo) quotasCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoQuotaCollection)
+}
+
+// GetUsage measures the value sizes with model.ValueSize, like the quota checks of the controller
+func (this *Mongo) GetUsage(ctx context.Context, userId string) (result model.Usage, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	cursor, err := this.variablesCollection().Find(ctx, notExpired(bson.M{VariableBson.UserId: userId}), options.Find().SetProjection(bson.M{VariableValueBson: 1}))
+	if err != nil {
+		return result, err
+	}
+	defer cursor.Close(context.Background())
+	for cursor.Next(ctx) {
+		variable := model.VariableWithUser{}
+		err = cursor.Decode(&variable)
+		if err != nil {
+			return result, err
+		}
+		size, err := model.ValueSize(variable.Value)
+		if err != nil {
+			return result, err
+		}
+		result.Variables++
+		result.TotalBytes += size
+	}
+	return result, cursor.Err()
+}
+
+func (this *Mongo) GetQuota(ctx context.Context, userId string) (quota model.Quota, found bool, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.quotasCollection().FindOne(ctx, bson.M{QuotaBson.UserId: userId}).Decode(&quota)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return quota, false, nil
+	}
+	return quota, err == nil, err
+}
+
+func (this *Mongo) SetQuota(ctx context.Context, quota model.Quota) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.quotasCollection().ReplaceOne(ctx, bson.M{QuotaBson.UserId: quota.UserId}, quota, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *Mongo) DeleteQuota(ctx context.Context, userId string) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.quotasCollection().DeleteOne(ctx, bson.M{QuotaBson.UserId: userId})
+	return err
+}
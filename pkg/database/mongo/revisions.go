@@ -0,0 +1,171 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"errors"
+	"runtime/debug"
+	"slices"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// the revisions collection stores the last revision of removed variables with the user_id, key and revision fields of variables,
+// so that recreated variables continue with higher revisions and outdated ETags do not match.
+// variables removed by the ttl index are not seen by the service, so the revisions of expiring variables are stored on write
+
+// maxWriteAttempts limits the retries of writes, which find a variable concurrently created or removed between update and insert
+const maxWriteAttempts = 10
+
+// deleteBatchSize limits the number of variables removed by one delete command of deleteVariables
+const deleteBatchSize = 1000
+
+func init() {
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		err := db.ensureCompoundIndex(db.revisionsCollection(), "revisions_user_key_index", true, true, VariableBson.UserId, VariableBson.Key)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		return nil
+	})
+}
+
+func (this *Mongo) revisionsCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoRevisionCollection)
+}
+
+// getRemovedRevision returns the last revision of the removed variable; 0 if no variable with the key was removed
+func (this *Mongo) getRemovedRevision(ctx context.Context, userId string, key string) (int64, error) {
+	result := model.VariableWithUser{}
+	err := this.revisionsCollection().FindOne(ctx, bson.M{VariableBson.UserId: userId, VariableBson.Key: key}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	return result.Revision, err
+}
+
+func newRemovedRevisionModel(variable model.VariableWithUser) mongo.WriteModel {
+	return mongo.NewUpdateOneModel().
+		SetFilter(bson.M{VariableBson.UserId: variable.UserId, VariableBson.Key: variable.Key}).
+		SetUpdate(bson.M{"$max": bson.M{VariableRevisionBson: variable.Revision}}).
+		SetUpsert(true)
+}
+
+// keepExpiringRevision stores the revision of the written variable, if the variable may be removed by the ttl index
+func (this *Mongo) keepExpiringRevision(ctx context.Context, variable model.VariableWithUser) error {
+	if variable.ExpiresAt <= 0 {
+		return nil
+	}
+	_, err := this.revisionsCollection().BulkWrite(ctx, []mongo.WriteModel{newRemovedRevisionModel(variable)})
+	return err
+}
+
+// insertVariable creates the variable with the revision following the revision of the removed variable;
+// returns 0 if the variable exists
+func (this *Mongo) insertVariable(ctx context.Context, variable model.VariableWithUser) (revision int64, err error) {
+	removed, err := this.getRemovedRevision(ctx, variable.UserId, variable.Key)
+	if err != nil {
+		return 0, err
+	}
+	variable.Revision = removed + 1
+	fields := getVariableFields(variable)
+	fields[VariableRevisionBson] = variable.Revision
+	result, err := this.variablesCollection().UpdateOne(
+		ctx,
+		bson.M{
+			VariableBson.UserId: variable.UserId,
+			VariableBson.Key:    variable.Key,
+		},
+		bson.M{"$setOnInsert": fields},
+		options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return 0, nil
+	}
+	if err != nil || result.UpsertedCount == 0 {
+		return 0, err
+	}
+	return variable.Revision, this.keepExpiringRevision(ctx, variable)
+}
+
+// deleteVariables deletes the variables matching the filter, stores their revisions and returns the deleted variables without values;
+// variables changed between read and delete are kept, as if the change was written after the delete
+func (this *Mongo) deleteVariables(ctx context.Context, filter bson.M) (deleted []model.VariableWithUser, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	projection := bson.M{VariableBson.UserId: 1, VariableBson.Key: 1, VariableRevisionBson: 1, VariableExpiresAtBson: 1}
+	cursor, err := this.variablesCollection().Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+	found, err := readCursorResult[model.VariableWithUser](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+	for batch := range slices.Chunk(found, deleteBatchSize) {
+		revisions := []mongo.WriteModel{}
+		conditions := bson.A{}
+		for _, variable := range batch {
+			revisions = append(revisions, newRemovedRevisionModel(variable))
+			conditions = append(conditions, bson.M{VariableBson.UserId: variable.UserId, VariableBson.Key: variable.Key, VariableRevisionBson: variable.Revision})
+		}
+		_, err = this.revisionsCollection().BulkWrite(ctx, revisions, options.BulkWrite().SetOrdered(false))
+		if err != nil {
+			return nil, err
+		}
+		result, err := this.variablesCollection().DeleteMany(ctx, bson.M{"$or": conditions})
+		if err != nil {
+			return nil, err
+		}
+		if result.DeletedCount < int64(len(batch)) {
+			//some variables are changed or removed concurrently
+			batch, err = this.withoutExisting(ctx, batch)
+			if err != nil {
+				return nil, err
+			}
+		}
+		deleted = append(deleted, batch...)
+	}
+	return deleted, nil
+}
+
+// withoutExisting removes the variables from the list, which still exist
+func (this *Mongo) withoutExisting(ctx context.Context, variables []model.VariableWithUser) ([]model.VariableWithUser, error) {
+	conditions := bson.A{}
+	for _, variable := range variables {
+		conditions = append(conditions, bson.M{VariableBson.UserId: variable.UserId, VariableBson.Key: variable.Key})
+	}
+	cursor, err := this.variablesCollection().Find(ctx, bson.M{"$or": conditions}, options.Find().SetProjection(bson.M{VariableBson.UserId: 1, VariableBson.Key: 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+	existing, err := readCursorResult[model.VariableWithUser](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+	return slices.DeleteFunc(slices.Clone(variables), func(variable model.VariableWithUser) bool {
+		return slices.ContainsFunc(existing, func(e model.VariableWithUser) bool {
+			return e.UserId == variable.UserId && e.Key == variable.Key
+		})
+	}), nil
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ShareBson = getBsonFieldObject[model.Share]()
+
+func init() {
+	CreateCollections = append(CreateCollections, func(db *Mongo) error {
+		var err error
+		collection := db.sharesCollection()
+		err = db.ensureIndex(collection, "share_id_index", ShareBson.Id, true, true)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureIndex(collection, "share_owner_index", ShareBson.OwnerId, true, false)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureIndex(collection, "share_user_index", ShareBson.UserId, true, false)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureIndex(collection, "share_group_index", ShareBson.Group, true, false)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		return nil
+	})
+}
+
+func (this *Mongo) sharesCollection() *mongo.Collection {
+	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoShareCollection)
+}
+
+func (this *Mongo) SetShare(ctx context.Context, share model.Share) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.sharesCollection().ReplaceOne(ctx, bson.M{ShareBson.Id: share.Id}, share, options.Replace().SetUpsert(true))
+	return err
+}
+
+func (this *Mongo) DeleteShare(ctx context.Context, ownerId string, id string) (bool, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	result, err := this.sharesCollection().DeleteOne(ctx, bson.M{ShareBson.Id: id, ShareBson.OwnerId: ownerId})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (this *Mongo) ListShares(ctx context.Context, ownerId string) ([]model.Share, error) {
+	return this.listShares(ctx, bson.M{ShareBson.OwnerId: ownerId})
+}
+
+func (this *Mongo) ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) {
+	grantees := bson.A{bson.M{ShareBson.UserId: userId}}
+	if len(groups) > 0 {
+		grantees = append(grantees, bson.M{ShareBson.Group: bson.M{"$in": groups}})
+	}
+	return this.listShares(ctx, bson.M{"$or": grantees})
+}
+
+// listShares returns the shares matching the filter in id order
+func (this *Mongo) listShares(ctx context.Context, filter bson.M) ([]model.Share, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	cursor, err := this.sharesCollection().Find(ctx, filter, options.Find().SetSort(bson.D{{Key: ShareBson.Id, Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(context.Background())
+	result, err := readCursorResult[model.Share](ctx, cursor)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result = []model.Share{}
+	}
+	return result, nil
+}
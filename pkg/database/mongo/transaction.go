@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Transaction runs f in a multi-document transaction; all methods called with the context passed to f use this transaction.
+// requires mongodb to run as replica set or sharded cluster, returns model.ErrTransactionsUnsupported otherwise.
+// f may be called multiple times if the transaction has to be retried. nested calls reuse the outer transaction.
+func (this *Mongo) Transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return f(ctx)
+	}
+	if !this.transactions {
+		return model.ErrTransactionsUnsupported
+	}
+	session, err := this.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.Background())
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, f(sessionCtx)
+	})
+	return err
+}
+
+// supportsTransactions checks if mongodb runs as replica set or sharded cluster, which is required for transactions
+func (this *Mongo) supportsTransactions(ctx context.Context) (bool, error) {
+	result := struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}{}
+	err := this.client.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&result)
+	if err != nil {
+		return false, err
+	}
+	return result.SetName != "" || result.Msg == "isdbgrid", nil
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mongo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// getValuePath translates json pointer tokens into the dot-notation path of the referenced element of the stored value
+func getValuePath(pointer []string) (string, error) {
+	for _, token := range pointer {
+		if token == "" || strings.Contains(token, ".") || strings.HasPrefix(token, "$") {
+			return "", fmt.Errorf("%w: path token %q can not be used in mongodb queries", model.ErrInvalidValueFilter, token)
+		}
+	}
+	return strings.Join(append([]string{VariableValueBson}, pointer...), "."), nil
+}
+
+// addValueFilters adds the conditions of the value filters to the filter; mirrors util.ValueFilter.Match.
+// mongodb matches arrays by their elements, which is prevented by an additional type condition
+func addValueFilters(filter bson.M, filters []model.ValueFilter) error {
+	parsed, err := util.ParseValueFilters(filters)
+	if err != nil {
+		return err
+	}
+	conditions, _ := filter["$and"].(bson.A)
+	for _, valueFilter := range parsed {
+		path, err := getValuePath(valueFilter.Pointer)
+		if err != nil {
+			return err
+		}
+		notArray := bson.M{path: bson.M{"$not": bson.M{"$type": "array"}}}
+		equals := func(value interface{}) bson.M {
+			if value == nil {
+				//{path: null} would also match missing elements
+				return bson.M{"$and": bson.A{bson.M{path: bson.M{"$type": "null"}}, notArray}}
+			}
+			return bson.M{"$and": bson.A{bson.M{path: value}, notArray}}
+		}
+		switch valueFilter.Operator {
+		case model.ValueFilterExists:
+			conditions = append(conditions, bson.M{path: bson.M{"$exists": true}})
+		case model.ValueFilterEq:
+			conditions = append(conditions, equals(valueFilter.Values[0]))
+		case model.ValueFilterNe:
+			conditions = append(conditions, bson.M{"$nor": bson.A{equals(valueFilter.Values[0])}})
+		case model.ValueFilterIn:
+			alternatives := bson.A{}
+			for _, value := range valueFilter.Values {
+				alternatives = append(alternatives, equals(value))
+			}
+			conditions = append(conditions, bson.M{"$or": alternatives})
+		default:
+			//comparisons only match elements of the same type (type bracketing)
+			operator := "$" + valueFilter.Operator
+			conditions = append(conditions, bson.M{"$and": bson.A{bson.M{path: bson.M{operator: valueFilter.Values[0]}}, notArray}})
+		}
+	}
+	if len(conditions) > 0 {
+		filter["$and"] = conditions
+	}
+	return nil
+}
+
+// createVariablesFindOptions is createFindOptions with the unique sort of the query (see model.Sort.Unique)
+func createVariablesFindOptions(query model.VariablesQueryOptions) (*options.FindOptions, error) {
+	opt := createFindOptions(query)
+	sort, err := query.GetSortKeys()
+	if err != nil {
+		return nil, err
+	}
+	sortDocument := bson.D{}
+	for _, key := range sort.Unique() {
+		path, err := getSortPath(key)
+		if err != nil {
+			return nil, err
+		}
+		direction := int32(1)
+		if key.Desc {
+			direction = int32(-1)
+		}
+		sortDocument = append(sortDocument, bson.E{Key: path, Value: direction})
+	}
+	opt.SetSort(sortDocument)
+	return opt, nil
+}
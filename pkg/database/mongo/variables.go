@@ -17,14 +17,30 @@
 package mongo
 
 import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"regexp"
 	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
 )
 
 var VariableBson = getBsonFieldObject[model.VariableWithUser]()
+var VariableValueBson = mustGetBsonFieldPath(model.VariableWithUser{}, "VariableWithUnixTimestamp.Variable.Value")
+var VariableUnixTimestampBson = mustGetBsonFieldPath(model.VariableWithUser{}, "VariableWithUnixTimestamp.UnixTimestampInS")
+var VariableRevisionBson = mustGetBsonFieldPath(model.VariableWithUser{}, "Revision")
+var VariableExpiresAtBson = mustGetBsonFieldPath(model.VariableWithUser{}, "VariableWithUnixTimestamp.Variable.ExpiresAt")
+
+// VariableExpirationBson is a date field, derived from the expires_at field, which is used by the ttl index;
+// it is not part of model.VariableWithUser and ignored on decode
+const VariableExpirationBson = "expiration"
 
 func init() {
 	CreateCollections = append(CreateCollections, func(db *Mongo) error {
@@ -40,6 +56,11 @@ func init() {
 			debug.PrintStack()
 			return err
 		}
+		err = db.ensureCompoundIndex(collection, "variables_user_timestamp_index", true, false, VariableBson.UserId, VariableUnixTimestampBson)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
 		err = db.ensureIndex(collection, "variables_p_instance_index", VariableBson.ProcessInstanceId, true, false)
 		if err != nil {
 			debug.PrintStack()
@@ -50,6 +71,16 @@ func init() {
 			debug.PrintStack()
 			return err
 		}
+		err = db.ensureTtlIndex(collection, "variables_expiration_index", VariableExpirationBson)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
+		err = db.ensureRevisions(collection)
+		if err != nil {
+			debug.PrintStack()
+			return err
+		}
 		return nil
 	})
 }
@@ -58,9 +89,10 @@ func (this *Mongo) variablesCollection() *mongo.Collection {
 	return this.client.Database(this.config.MongoTable).Collection(this.config.MongoVariablesCollection)
 }
 
-func (this *Mongo) GetVariable(userId string, key string) (result model.VariableWithUser, err error) {
-	ctx, _ := getTimeoutContext()
-	filter := bson.M{VariableBson.UserId: userId, VariableBson.Key: key}
+func (this *Mongo) GetVariable(ctx context.Context, userId string, key string) (result model.VariableWithUser, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	filter := notExpired(bson.M{VariableBson.UserId: userId, VariableBson.Key: key})
 	temp := this.variablesCollection().FindOne(ctx, filter)
 	err = temp.Err()
 	if err == mongo.ErrNoDocuments {
@@ -98,48 +130,322 @@ func (this *Mongo) GetVariable(userId string, key string) (result model.Variable
 	return result, nil
 }
 
-func (this *Mongo) SetVariable(variable model.VariableWithUser) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.variablesCollection().ReplaceOne(
+func (this *Mongo) GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (result model.VariableWithUser, err error) {
+	valuePath := VariableValueBson
+	if isProjectablePointer(pointer) {
+		valuePath = strings.Join(append([]string{VariableValueBson}, pointer...), ".")
+	}
+	projection := bson.M{
+		VariableBson.UserId:              1,
+		VariableBson.Key:                 1,
+		VariableBson.ProcessDefinitionId: 1,
+		VariableBson.ProcessInstanceId:   1,
+		VariableUnixTimestampBson:        1,
+		VariableRevisionBson:             1,
+		VariableExpiresAtBson:            1,
+		VariableBson.ContentType:         1,
+		valuePath:                        1,
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.variablesCollection().FindOne(ctx, notExpired(bson.M{VariableBson.UserId: userId, VariableBson.Key: key}), options.FindOne().SetProjection(projection)).Decode(&result)
+	if err == mongo.ErrNoDocuments {
+		return model.VariableWithUser{
+			VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
+				Variable: model.Variable{
+					Key:                 key,
+					Value:               nil,
+					ProcessDefinitionId: "",
+					ProcessInstanceId:   "",
+				},
+				UnixTimestampInS: 0,
+			},
+			UserId: userId,
+		}, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	// the projection keeps the structure of the document; the referenced element is selected in process
+	return util.SelectPointer(result, pointer)
+}
+
+// isProjectablePointer checks if the pointer can be used as projection path;
+// numeric tokens may reference array elements, which are not supported by projections
+func isProjectablePointer(pointer []string) bool {
+	if len(pointer) == 0 {
+		return false
+	}
+	for _, token := range pointer {
+		if token == "" || strings.Contains(token, ".") || strings.HasPrefix(token, "$") || strings.Trim(token, "0123456789") == "" {
+			return false
+		}
+	}
+	return true
+}
+
+// addKeyFilters adds the key filters of the query to the filter; mirrors util.KeyMatcher.Match.
+// prefixes are matched by case-sensitive anchored regular expressions without special characters, which may use the key index
+func addKeyFilters(filter bson.M, query model.VariablesQueryOptions) error {
+	keys, err := util.ParseKeyFilters(query)
+	if err != nil || keys == nil {
+		return err
+	}
+	conditions, _ := filter["$and"].(bson.A)
+	if len(keys.Keys) > 0 {
+		conditions = append(conditions, bson.M{VariableBson.Key: bson.M{"$in": keys.Keys}})
+	}
+	if keys.Prefix != "" {
+		conditions = append(conditions, bson.M{VariableBson.Key: bson.M{"$regex": "^" + regexp.QuoteMeta(keys.Prefix)}})
+	}
+	if keys.Glob != "" {
+		conditions = append(conditions, bson.M{VariableBson.Key: bson.M{"$regex": keys.Glob, "$options": "s"}})
+	}
+	if keys.Regex != "" {
+		options := ""
+		if keys.RegexCaseInsensitive {
+			options = "i"
+		}
+		conditions = append(conditions, bson.M{VariableBson.Key: bson.M{"$regex": keys.Regex, "$options": options}})
+	}
+	filter["$and"] = conditions
+	return nil
+}
+
+// addTimestampFilters adds the updated_after and updated_before conditions of the query to the filter
+func addTimestampFilters(filter bson.M, query model.VariablesQueryOptions) {
+	condition := bson.M{}
+	if query.UpdatedAfter != 0 {
+		condition["$gt"] = query.UpdatedAfter
+	}
+	if query.UpdatedBefore != 0 {
+		condition["$lt"] = query.UpdatedBefore
+	}
+	if len(condition) > 0 {
+		filter[VariableUnixTimestampBson] = condition
+	}
+}
+
+// notExpired adds the condition to skip expired variables to the filter;
+// the ttl index removes expired variables only periodically
+func notExpired(filter bson.M) bson.M {
+	filter[VariableExpiresAtBson] = bson.M{"$not": bson.M{"$gt": 0, "$lte": configuration.TimeNow().Unix()}}
+	return filter
+}
+
+// removeExpired deletes the variable and its history, if the variable is expired,
+// so that following writes start with a new history
+func (this *Mongo) removeExpired(ctx context.Context, userId string, key string) error {
+	deleted, err := this.deleteVariables(ctx, bson.M{
+		VariableBson.UserId:   userId,
+		VariableBson.Key:      key,
+		VariableExpiresAtBson: bson.M{"$gt": 0, "$lte": configuration.TimeNow().Unix()},
+	})
+	if err != nil || len(deleted) == 0 {
+		return err
+	}
+	return this.deleteVersions(ctx, bson.M{
+		VersionBson.UserId:      userId,
+		VersionBson.Version.Key: key,
+	})
+}
+
+// ensureRevisions sets the first revision on variables stored before revisions were introduced
+func (this *Mongo) ensureRevisions(collection *mongo.Collection) error {
+	ctx, cancel := this.getTimeoutContext(context.Background())
+	defer cancel()
+	_, err := collection.UpdateMany(ctx, bson.M{VariableRevisionBson: bson.M{"$exists": false}}, bson.M{"$set": bson.M{VariableRevisionBson: 1}})
+	return err
+}
+
+func (this *Mongo) SetVariable(ctx context.Context, variable model.VariableWithUser) error {
+	err := this.removeExpired(ctx, variable.UserId, variable.Key)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	for i := 0; i < maxWriteAttempts; i++ {
+		current := model.VariableWithUser{}
+		err = this.variablesCollection().FindOneAndUpdate(
+			ctx,
+			bson.M{
+				VariableBson.UserId: variable.UserId,
+				VariableBson.Key:    variable.Key,
+			},
+			bson.M{
+				"$set": getVariableFields(variable),
+				"$inc": bson.M{VariableRevisionBson: 1},
+			},
+			options.FindOneAndUpdate().SetProjection(bson.M{VariableRevisionBson: 1}).SetReturnDocument(options.After)).Decode(&current)
+		if err == nil {
+			variable.Revision = current.Revision
+			return this.keepExpiringRevision(ctx, variable)
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return err
+		}
+		revision, err := this.insertVariable(ctx, variable)
+		if err != nil || revision > 0 {
+			return err
+		}
+	}
+	return model.ErrUpdateConflict
+}
+
+func (this *Mongo) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (int64, bool, error) {
+	err := this.removeExpired(ctx, variable.UserId, variable.Key)
+	if err != nil {
+		return 0, false, err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	if revision == 0 {
+		revision, err = this.insertVariable(ctx, variable)
+		return revision, revision > 0, err
+	}
+	variable.Revision = revision + 1
+	fields := getVariableFields(variable)
+	fields[VariableRevisionBson] = variable.Revision
+	result, err := this.variablesCollection().UpdateOne(
 		ctx,
 		bson.M{
-			VariableBson.UserId: variable.UserId,
-			VariableBson.Key:    variable.Key,
+			VariableBson.UserId:  variable.UserId,
+			VariableBson.Key:     variable.Key,
+			VariableRevisionBson: revision,
 		},
-		variable,
-		options.Replace().SetUpsert(true))
+		bson.M{"$set": fields})
+	if err != nil || result.MatchedCount == 0 {
+		return 0, false, err
+	}
+	return variable.Revision, true, this.keepExpiringRevision(ctx, variable)
+}
+
+// mongoTypeMismatch is the error code returned by $inc on a non-numeric value
+const mongoTypeMismatch = 14
+
+func (this *Mongo) IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (result model.VariableWithUser, err error) {
+	err = this.removeExpired(ctx, variable.UserId, variable.Key)
 	if err != nil {
-		return err
+		return result, err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	for i := 0; i < maxWriteAttempts; i++ {
+		err = this.variablesCollection().FindOneAndUpdate(
+			ctx,
+			bson.M{
+				VariableBson.UserId: variable.UserId,
+				VariableBson.Key:    variable.Key,
+			},
+			bson.M{
+				"$inc": bson.M{VariableValueBson: delta, VariableRevisionBson: 1},
+				"$set": bson.M{VariableUnixTimestampBson: variable.UnixTimestampInS},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&result)
+		var serverErr mongo.ServerError
+		if errors.As(err, &serverErr) && serverErr.HasErrorCode(mongoTypeMismatch) {
+			return result, model.ErrNotNumeric
+		}
+		if err == nil {
+			return result, this.keepExpiringRevision(ctx, result)
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return result, err
+		}
+		result = variable
+		result.Value = delta
+		result.Revision, err = this.insertVariable(ctx, result)
+		if err != nil || result.Revision > 0 {
+			return result, err
+		}
+	}
+	return result, model.ErrUpdateConflict
+}
+
+// getVariableFields returns the fields of the variable, except the revision, for use in $set
+func getVariableFields(variable model.VariableWithUser) bson.M {
+	return bson.M{
+		VariableBson.UserId:              variable.UserId,
+		VariableBson.Key:                 variable.Key,
+		VariableValueBson:                variable.Value,
+		VariableBson.ProcessDefinitionId: variable.ProcessDefinitionId,
+		VariableBson.ProcessInstanceId:   variable.ProcessInstanceId,
+		VariableUnixTimestampBson:        variable.UnixTimestampInS,
+		VariableExpiresAtBson:            variable.ExpiresAt,
+		VariableExpirationBson:           getExpiration(variable.ExpiresAt),
+		VariableBson.ContentType:         variable.ContentType,
 	}
-	return nil
 }
 
-func (this *Mongo) DeleteVariable(userId string, key string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.variablesCollection().DeleteMany(ctx, bson.M{
+// getExpiration returns the value of the VariableExpirationBson field; documents without date are ignored by the ttl index
+func getExpiration(expiresAt int64) interface{} {
+	if expiresAt <= 0 {
+		return nil
+	}
+	return time.Unix(expiresAt, 0)
+}
+
+func (this *Mongo) UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (model.VariableWithUser, error) {
+	return util.UpdateVariable(ctx, this, userId, key, update)
+}
+
+func (this *Mongo) DeleteVariable(ctx context.Context, userId string, key string) error {
+	_, err := this.deleteVariables(ctx, bson.M{
 		VariableBson.UserId: userId,
 		VariableBson.Key:    key,
 	})
-	return err
+	if err != nil {
+		return err
+	}
+	return this.deleteVersions(ctx, bson.M{
+		VersionBson.UserId:      userId,
+		VersionBson.Version.Key: key,
+	})
 }
 
-func (this *Mongo) ListVariables(userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
-	opt := createFindOptions(query)
-	filter := bson.M{VariableBson.UserId: userId}
+// getVariablesFilter returns the filter selecting the not expired variables of the user
+// matching the filters and the cursor of the query
+func getVariablesFilter(userId string, query model.VariablesQueryOptions) (filter bson.M, err error) {
+	filter = notExpired(bson.M{VariableBson.UserId: userId})
 	if query.ProcessDefinitionId != "" {
 		filter[VariableBson.ProcessDefinitionId] = query.ProcessDefinitionId
 	}
 	if query.ProcessInstanceId != "" {
 		filter[VariableBson.ProcessInstanceId] = query.ProcessInstanceId
 	}
-	if query.KeyRegex != "" {
-		filter[VariableBson.Key] = bson.M{"$regex": query.KeyRegex, "$options": "i"}
+	err = addKeyFilters(filter, query)
+	if err != nil {
+		return nil, err
+	}
+	addTimestampFilters(filter, query)
+	err = addValueFilters(filter, query.ValueFilters)
+	if err != nil {
+		return nil, err
+	}
+	err = addCursor(filter, query)
+	if err != nil {
+		return nil, err
+	}
+	return filter, nil
+}
+
+func (this *Mongo) ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	opt, err := createVariablesFindOptions(query)
+	if err != nil {
+		return result, err
 	}
-	ctx, _ := getTimeoutContext()
+	filter, err := getVariablesFilter(userId, query)
+	if err != nil {
+		return result, err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
 	cursor, err := this.variablesCollection().Find(ctx, filter, opt)
 	if err != nil {
 		return result, err
 	}
+	defer cursor.Close(context.Background())
 	temp, err := readCursorResult[model.VariableWithUser](ctx, cursor)
 	if err != nil {
 		return result, err
@@ -150,34 +456,106 @@ func (this *Mongo) ListVariables(userId string, query model.VariablesQueryOption
 	return result, err
 }
 
-func (this *Mongo) CountVariables(userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
-	filter := bson.M{VariableBson.UserId: userId}
-	if query.ProcessDefinitionId != "" {
-		filter[VariableBson.ProcessDefinitionId] = query.ProcessDefinitionId
-	}
-	if query.ProcessInstanceId != "" {
-		filter[VariableBson.ProcessInstanceId] = query.ProcessInstanceId
-	}
-	if query.KeyRegex != "" {
-		filter[VariableBson.Key] = bson.M{"$regex": query.KeyRegex, "$options": "i"}
+func (this *Mongo) CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
+	filter, err := getVariablesFilter(userId, query)
+	if err != nil {
+		return result, err
 	}
-	ctx, _ := getTimeoutContext()
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
 	result.Count, err = this.variablesCollection().CountDocuments(ctx, filter)
 	return
 }
 
-func (this *Mongo) DeleteVariablesOfProcessDefinition(definitionId string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.variablesCollection().DeleteMany(ctx, bson.M{
+func (this *Mongo) ListUserIds(ctx context.Context) (result []string, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	temp, err := this.variablesCollection().Distinct(ctx, VariableBson.UserId, bson.M{})
+	if err != nil {
+		return nil, err
+	}
+	for _, userId := range temp {
+		if str, ok := userId.(string); ok {
+			result = append(result, str)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// DeleteVariables removes the not expired variables of the user matching the filters of the query and their history
+func (this *Mongo) DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (count int64, err error) {
+	filter, err := getVariablesFilter(userId, query)
+	if err != nil {
+		return 0, err
+	}
+	deleted, err := this.deleteVariables(ctx, filter)
+	if err != nil || len(deleted) == 0 {
+		return 0, err
+	}
+	//the keys are needed to remove the history of the deleted variables
+	keys := []string{}
+	for _, variable := range deleted {
+		keys = append(keys, variable.Key)
+	}
+	err = this.deleteVersions(ctx, bson.M{
+		VersionBson.UserId:      userId,
+		VersionBson.Version.Key: bson.M{"$in": keys},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(deleted)), nil
+}
+
+func (this *Mongo) DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (int64, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	count, err := this.deleteVariablesWhere(ctx, bson.M{
 		VariableBson.ProcessDefinitionId: definitionId,
 	})
-	return err
+	if err != nil {
+		return 0, err
+	}
+	err = this.deleteVersions(ctx, bson.M{
+		VersionBson.Version.ProcessDefinitionId: definitionId,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
 }
 
-func (this *Mongo) DeleteVariablesOfProcessInstance(instanceId string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.variablesCollection().DeleteMany(ctx, bson.M{
+func (this *Mongo) DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (int64, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	count, err := this.deleteVariablesWhere(ctx, bson.M{
 		VariableBson.ProcessInstanceId: instanceId,
 	})
-	return err
+	if err != nil {
+		return 0, err
+	}
+	err = this.deleteVersions(ctx, bson.M{
+		VersionBson.Version.ProcessInstanceId: instanceId,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// deleteVariablesWhere deletes the variables matching the filter, including expired variables not yet removed by the ttl index;
+// returns the number of deleted not expired variables
+func (this *Mongo) deleteVariablesWhere(ctx context.Context, filter bson.M) (count int64, err error) {
+	deleted, err := this.deleteVariables(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	now := configuration.TimeNow().Unix()
+	for _, variable := range deleted {
+		if !variable.IsExpired(now) {
+			count++
+		}
+	}
+	return count, nil
 }
@@ -0,0 +1,66 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postgres
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+)
+
+const deleteExpiredVariablesSql = `
+WITH deleted AS (
+    DELETE FROM variables WHERE expires_at > 0 AND expires_at <= $1 RETURNING user_id, variable_key, revision
+),
+history AS (
+    DELETE FROM variable_history h USING deleted e WHERE h.user_id = e.user_id AND h.variable_key = e.variable_key
+),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted;
+`
+
+// startExpirySweeper periodically deletes expired variables and their history until ctx is done;
+// reads already ignore expired variables, the sweeper only frees the storage
+func (this *Pg) startExpirySweeper(ctx context.Context, wg *sync.WaitGroup, interval time.Duration) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				err := this.deleteExpiredVariables(ctx)
+				if err != nil && ctx.Err() == nil {
+					this.config.GetLogger().Error("unable to delete expired variables", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// deleteExpiredVariables deletes all expired variables and their history
+func (this *Pg) deleteExpiredVariables(ctx context.Context) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, deleteExpiredVariablesSql, configuration.TimeNow().Unix())
+	return err
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+const addVariableVersionSql = `
+INSERT INTO variable_history (user_id, variable_key, revision, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, writer, expires_at, content_type)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+ON CONFLICT (user_id, variable_key, revision) DO UPDATE
+  SET process_definition_id = excluded.process_definition_id,
+      process_instance_id = excluded.process_instance_id,
+      unix_timestamp_in_s = excluded.unix_timestamp_in_s,
+      variable_value = excluded.variable_value,
+      writer = excluded.writer,
+      expires_at = excluded.expires_at,
+      content_type = excluded.content_type;
+`
+
+func (this *Pg) AddVariableVersion(ctx context.Context, userId string, version model.VariableVersion) error {
+	jsonValue, err := json.Marshal(version.Value)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err = this.getExecutor(ctx).ExecContext(ctx, addVariableVersionSql,
+		userId,
+		version.Key,
+		version.Revision,
+		version.ProcessDefinitionId,
+		version.ProcessInstanceId,
+		version.UnixTimestampInS,
+		jsonValue,
+		version.Writer,
+		version.ExpiresAt,
+		version.ContentType,
+	)
+	return err
+}
+
+const versionFieldsSql = `variable_key, revision, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, writer, expires_at, content_type`
+
+const listVariableVersionsSql = `SELECT ` + versionFieldsSql + ` FROM variable_history WHERE user_id = $1 AND variable_key = $2 ORDER BY revision DESC LIMIT $3 OFFSET $4`
+
+func (this *Pg) ListVariableVersions(ctx context.Context, userId string, key string, query model.HistoryQueryOptions) (result []model.VariableVersion, err error) {
+	var limit interface{} //NULL = no limit
+	if query.Limit > 0 {
+		limit = query.Limit
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	rows, err := this.getExecutor(ctx).QueryContext(ctx, listVariableVersionsSql, userId, key, limit, query.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		version, err := scanVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, version)
+	}
+	return result, rows.Err()
+}
+
+const getVariableVersionSql = `SELECT ` + versionFieldsSql + ` FROM variable_history WHERE user_id = $1 AND variable_key = $2 AND revision = $3`
+
+func (this *Pg) GetVariableVersion(ctx context.Context, userId string, key string, revision int64) (model.VariableVersion, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	return scanVersion(this.getExecutor(ctx).QueryRowContext(ctx, getVariableVersionSql, userId, key, revision))
+}
+
+const getVariableVersionAtSql = `SELECT ` + versionFieldsSql + ` FROM variable_history WHERE user_id = $1 AND variable_key = $2 AND unix_timestamp_in_s <= $3 ORDER BY revision DESC LIMIT 1`
+
+func (this *Pg) GetVariableVersionAt(ctx context.Context, userId string, key string, unixTimestampInS int64) (model.VariableVersion, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	return scanVersion(this.getExecutor(ctx).QueryRowContext(ctx, getVariableVersionAtSql, userId, key, unixTimestampInS))
+}
+
+// pruneVariableHistorySql mirrors util.IsPruned; $4 and $5 are 0 if unlimited
+const pruneVariableHistorySql = `
+DELETE FROM variable_history
+  WHERE user_id = $1 AND variable_key = $2 AND revision < $3
+    AND (($4 > 0 AND revision <= $3 - $4) OR ($5 > 0 AND unix_timestamp_in_s < $5));
+`
+
+func (this *Pg) PruneVariableHistory(ctx context.Context, userId string, key string, currentRevision int64, retention model.HistoryRetention) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, pruneVariableHistorySql, userId, key, currentRevision, retention.MaxVersions, retention.MinUnixTimestampInS)
+	return err
+}
+
+// scanner is implemented by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanVersion(row scanner) (result model.VariableVersion, err error) {
+	var jsonValue []byte
+	var processDefinitionId, processInstanceId, writer sql.NullString
+	err = row.Scan(
+		&result.Key,
+		&result.Revision,
+		&processDefinitionId,
+		&processInstanceId,
+		&result.UnixTimestampInS,
+		&jsonValue,
+		&writer,
+		&result.ExpiresAt,
+		&result.ContentType,
+	)
+	if err == sql.ErrNoRows {
+		return result, model.ErrVersionNotFound
+	}
+	if err != nil {
+		return result, err
+	}
+	result.ProcessDefinitionId = processDefinitionId.String
+	result.ProcessInstanceId = processInstanceId.String
+	result.Writer = writer.String
+	err = json.Unmarshal(jsonValue, &result.Value)
+	return result, err
+}
@@ -0,0 +1,234 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// Migration changes the schema from the previous version to Version.
+// migrations are applied in order of Migrations; applied versions are recorded in the schema_migrations table
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+}
+
+// Migrations lists all schema migrations in ascending version order; never change an applied migration, append a new one instead
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "initial schema",
+		// idempotent to adopt databases created before schema migrations were introduced;
+		// existing rows count as first revision and never expire
+		Up: `
+CREATE TABLE IF NOT EXISTS variables (
+    user_id VARCHAR ( 50 ) NOT NULL,
+    variable_key VARCHAR ( 255 ) NOT NULL,
+    process_definition_id VARCHAR ( 64 ),
+    process_instance_id VARCHAR ( 64 ),
+    unix_timestamp_in_s INT,
+    variable_value json,
+    PRIMARY KEY (user_id, variable_key)
+);
+ALTER TABLE variables ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE variables ADD COLUMN IF NOT EXISTS expires_at BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS variable_process_definition ON variables (process_definition_id);
+CREATE INDEX IF NOT EXISTS variable_process_instance ON variables (process_instance_id);
+CREATE INDEX IF NOT EXISTS variable_expires_at ON variables (expires_at) WHERE expires_at > 0;
+
+CREATE TABLE IF NOT EXISTS variable_history (
+    user_id VARCHAR ( 50 ) NOT NULL,
+    variable_key VARCHAR ( 255 ) NOT NULL,
+    revision BIGINT NOT NULL,
+    process_definition_id VARCHAR ( 64 ),
+    process_instance_id VARCHAR ( 64 ),
+    unix_timestamp_in_s INT,
+    variable_value json,
+    writer VARCHAR ( 50 ),
+    PRIMARY KEY (user_id, variable_key, revision)
+);
+ALTER TABLE variable_history ADD COLUMN IF NOT EXISTS expires_at BIGINT NOT NULL DEFAULT 0;
+CREATE INDEX IF NOT EXISTS variable_history_process_definition ON variable_history (process_definition_id);
+CREATE INDEX IF NOT EXISTS variable_history_process_instance ON variable_history (process_instance_id);
+`,
+	},
+	{
+		Version: 2,
+		Name:    "bigint timestamps, wide ids and jsonb values",
+		Up: `
+ALTER TABLE variables
+    ALTER COLUMN user_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN process_definition_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN process_instance_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN unix_timestamp_in_s TYPE BIGINT,
+    ALTER COLUMN variable_value TYPE jsonb USING variable_value::jsonb;
+
+ALTER TABLE variable_history
+    ALTER COLUMN user_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN process_definition_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN process_instance_id TYPE VARCHAR ( 255 ),
+    ALTER COLUMN writer TYPE VARCHAR ( 255 ),
+    ALTER COLUMN unix_timestamp_in_s TYPE BIGINT,
+    ALTER COLUMN variable_value TYPE jsonb USING variable_value::jsonb;
+`,
+	},
+	{
+		Version: 3,
+		Name:    "timestamp index",
+		Up:      `CREATE INDEX IF NOT EXISTS variables_user_timestamp_index ON variables (user_id, unix_timestamp_in_s);`,
+	},
+	{
+		Version: 4,
+		Name:    "key pattern index",
+		//the primary key index can not be used for LIKE prefix matches in databases with non C collation
+		Up: `CREATE INDEX IF NOT EXISTS variables_user_key_pattern_index ON variables (user_id, variable_key varchar_pattern_ops);`,
+	},
+	{
+		Version: 5,
+		Name:    "variable shares",
+		Up: `
+CREATE TABLE IF NOT EXISTS variable_shares (
+    id VARCHAR ( 64 ) PRIMARY KEY,
+    owner_id VARCHAR ( 255 ) NOT NULL,
+    variable_key VARCHAR ( 255 ) NOT NULL DEFAULT '',
+    key_prefix VARCHAR ( 255 ) NOT NULL DEFAULT '',
+    user_id VARCHAR ( 255 ) NOT NULL DEFAULT '',
+    group_id VARCHAR ( 255 ) NOT NULL DEFAULT '',
+    can_write BOOLEAN NOT NULL DEFAULT FALSE
+);
+CREATE INDEX IF NOT EXISTS variable_shares_owner ON variable_shares (owner_id);
+CREATE INDEX IF NOT EXISTS variable_shares_user ON variable_shares (user_id) WHERE user_id <> '';
+CREATE INDEX IF NOT EXISTS variable_shares_group ON variable_shares (group_id) WHERE group_id <> '';
+`,
+	},
+	{
+		Version: 6,
+		Name:    "variable quotas",
+		Up: `
+CREATE TABLE IF NOT EXISTS variable_quotas (
+    user_id VARCHAR ( 255 ) PRIMARY KEY,
+    max_variables BIGINT NOT NULL DEFAULT 0,
+    max_value_bytes BIGINT NOT NULL DEFAULT 0,
+    max_total_bytes BIGINT NOT NULL DEFAULT 0
+);
+`,
+	},
+	{
+		Version: 7,
+		Name:    "binary value content types",
+		Up: `
+ALTER TABLE variables ADD COLUMN IF NOT EXISTS content_type VARCHAR ( 255 ) NOT NULL DEFAULT '';
+ALTER TABLE variable_history ADD COLUMN IF NOT EXISTS content_type VARCHAR ( 255 ) NOT NULL DEFAULT '';
+`,
+	},
+	{
+		Version: 8,
+		Name:    "revisions of removed variables",
+		Up: `
+CREATE TABLE IF NOT EXISTS variable_revisions (
+    user_id VARCHAR ( 255 ) NOT NULL,
+    variable_key VARCHAR ( 255 ) NOT NULL,
+    revision BIGINT NOT NULL,
+    PRIMARY KEY (user_id, variable_key)
+);
+`,
+	},
+	{
+		Version: 9,
+		Name:    "sort indexes",
+		//the variables are sorted with the "C" collation (see sortColumns), which can only use indexes with the same collation;
+		//the sorts end with the key (see model.Sort.Unique), which replaces variables_user_timestamp_index
+		Up: `
+CREATE INDEX IF NOT EXISTS variables_user_key_c_index ON variables (user_id, variable_key COLLATE "C");
+CREATE INDEX IF NOT EXISTS variables_user_timestamp_key_c_index ON variables (user_id, unix_timestamp_in_s, variable_key COLLATE "C");
+CREATE INDEX IF NOT EXISTS variables_user_definition_key_c_index ON variables (user_id, process_definition_id COLLATE "C", variable_key COLLATE "C");
+CREATE INDEX IF NOT EXISTS variables_user_instance_key_c_index ON variables (user_id, process_instance_id COLLATE "C", variable_key COLLATE "C");
+DROP INDEX IF EXISTS variables_user_timestamp_index;
+`,
+	},
+}
+
+const createSchemaMigrationsTableSql = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR ( 255 ) NOT NULL,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);`
+
+// migrationLockId identifies the advisory lock, which prevents concurrent migrations by multiple instances
+const migrationLockId = 7365834129
+
+// migrate applies all migrations of Migrations, which are not yet recorded in the schema_migrations table;
+// each migration runs in its own transaction. the advisory lock is held by a dedicated connection until all migrations are done
+func (this *Pg) migrate(ctx context.Context) (err error) {
+	conn, err := this.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, err = conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, migrationLockId)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, unlockErr := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, migrationLockId)
+		if err == nil {
+			err = unlockErr
+		}
+	}()
+	_, err = conn.ExecContext(ctx, createSchemaMigrationsTableSql)
+	if err != nil {
+		return err
+	}
+	var current int64
+	err = conn.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&current)
+	if err != nil {
+		return err
+	}
+	for _, migration := range Migrations {
+		if migration.Version <= current {
+			continue
+		}
+		err = applyMigration(ctx, conn, migration)
+		if err != nil {
+			return fmt.Errorf("schema migration %v (%v) failed: %w", migration.Version, migration.Name, err)
+		}
+		this.config.GetLogger().Info("applied schema migration", "version", migration.Version, "name", migration.Name)
+		current = migration.Version
+	}
+	return nil
+}
+
+func applyMigration(ctx context.Context, conn *sql.Conn, migration Migration) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, migration.Up)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -25,23 +25,21 @@ import (
 	"time"
 )
 
-var CreateTable = []func(db *Pg) error{}
-
 func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (pg *Pg, err error) {
-	pg = &Pg{}
+	pg = &Pg{config: config, timeout: config.GetDatabaseTimeout()}
 	pg.db, err = sql.Open("postgres", config.PostgresConnString)
 	if err != nil {
 		return pg, err
 	}
 
-	for _, creators := range CreateTable {
-		err = creators(pg)
-		if err != nil {
-			pg.db.Close()
-			return nil, err
-		}
+	err = pg.migrate(ctx)
+	if err != nil {
+		pg.db.Close()
+		return nil, err
 	}
 
+	pg.startExpirySweeper(ctx, wg, config.GetExpirySweepInterval())
+
 	wg.Add(1)
 	go func() {
 		<-ctx.Done()
@@ -52,10 +50,17 @@ func New(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (
 }
 
 type Pg struct {
-	config configuration.Config
-	db     *sql.DB
+	config  configuration.Config
+	db      *sql.DB
+	timeout time.Duration
+}
+
+// getTimeoutContext limits the given (request) context to the configured database timeout
+func (this *Pg) getTimeoutContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, this.timeout)
 }
 
-func getTimeoutContext() (context.Context, context.CancelFunc) {
-	return context.WithTimeout(context.Background(), 10*time.Second)
+// Ping checks if the postgres database is reachable
+func (this *Pg) Ping(ctx context.Context) error {
+	return this.db.PingContext(ctx)
 }
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+var getUsageSql = `SELECT variable_value FROM variables WHERE user_id = $1 AND ` + fmt.Sprintf(notExpiredSql, 2)
+
+// GetUsage measures the value sizes with model.ValueSize, like the quota checks of the controller;
+// the jsonb text representation differs from the json encoding of the value
+func (this *Pg) GetUsage(ctx context.Context, userId string) (result model.Usage, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	rows, err := this.getExecutor(ctx).QueryContext(ctx, getUsageSql, userId, configuration.TimeNow().Unix())
+	if err != nil {
+		return result, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var jsonValue []byte
+		err = rows.Scan(&jsonValue)
+		if err != nil {
+			return result, err
+		}
+		var value interface{}
+		err = json.Unmarshal(jsonValue, &value)
+		if err != nil {
+			return result, err
+		}
+		size, err := model.ValueSize(value)
+		if err != nil {
+			return result, err
+		}
+		result.Variables++
+		result.TotalBytes += size
+	}
+	return result, rows.Err()
+}
+
+func (this *Pg) GetQuota(ctx context.Context, userId string) (quota model.Quota, found bool, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.getExecutor(ctx).QueryRowContext(ctx, "SELECT user_id, max_variables, max_value_bytes, max_total_bytes FROM variable_quotas WHERE user_id = $1", userId).
+		Scan(&quota.UserId, &quota.MaxVariables, &quota.MaxValueBytes, &quota.MaxTotalBytes)
+	if err == sql.ErrNoRows {
+		return quota, false, nil
+	}
+	return quota, err == nil, err
+}
+
+const setQuotaSql = `
+INSERT INTO variable_quotas (user_id, max_variables, max_value_bytes, max_total_bytes)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (user_id) DO UPDATE
+  SET max_variables = excluded.max_variables,
+      max_value_bytes = excluded.max_value_bytes,
+      max_total_bytes = excluded.max_total_bytes;
+`
+
+func (this *Pg) SetQuota(ctx context.Context, quota model.Quota) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, setQuotaSql, quota.UserId, quota.MaxVariables, quota.MaxValueBytes, quota.MaxTotalBytes)
+	return err
+}
+
+func (this *Pg) DeleteQuota(ctx context.Context, userId string) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, "DELETE FROM variable_quotas WHERE user_id = $1", userId)
+	return err
+}
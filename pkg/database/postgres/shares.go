@@ -0,0 +1,87 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package postgres
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/lib/pq"
+)
+
+const setShareSql = `
+INSERT INTO variable_shares (id, owner_id, variable_key, key_prefix, user_id, group_id, can_write)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+ON CONFLICT (id) DO UPDATE
+  SET owner_id = excluded.owner_id,
+      variable_key = excluded.variable_key,
+      key_prefix = excluded.key_prefix,
+      user_id = excluded.user_id,
+      group_id = excluded.group_id,
+      can_write = excluded.can_write;
+`
+
+func (this *Pg) SetShare(ctx context.Context, share model.Share) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, setShareSql, share.Id, share.OwnerId, share.Key, share.KeyPrefix, share.UserId, share.Group, share.Write)
+	return err
+}
+
+func (this *Pg) DeleteShare(ctx context.Context, ownerId string, id string) (bool, error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	result, err := this.getExecutor(ctx).ExecContext(ctx, "DELETE FROM variable_shares WHERE id = $1 AND owner_id = $2", id, ownerId)
+	if err != nil {
+		return false, err
+	}
+	count, err := result.RowsAffected()
+	return count > 0, err
+}
+
+const shareFieldsSql = `id, owner_id, variable_key, key_prefix, user_id, group_id, can_write`
+
+func (this *Pg) ListShares(ctx context.Context, ownerId string) ([]model.Share, error) {
+	return this.listShares(ctx, `SELECT `+shareFieldsSql+` FROM variable_shares WHERE owner_id = $1 ORDER BY id`, ownerId)
+}
+
+func (this *Pg) ListSharesFor(ctx context.Context, userId string, groups []string) ([]model.Share, error) {
+	if groups == nil {
+		groups = []string{}
+	}
+	return this.listShares(ctx, `SELECT `+shareFieldsSql+` FROM variable_shares WHERE user_id = $1 OR group_id = ANY($2) ORDER BY id`, userId, pq.Array(groups))
+}
+
+func (this *Pg) listShares(ctx context.Context, query string, args ...interface{}) (result []model.Share, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	rows, err := this.getExecutor(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	result = []model.Share{}
+	for rows.Next() {
+		share := model.Share{}
+		err = rows.Scan(&share.Id, &share.OwnerId, &share.Key, &share.KeyPrefix, &share.UserId, &share.Group, &share.Write)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, share)
+	}
+	return result, rows.Err()
+}
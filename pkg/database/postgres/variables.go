@@ -17,55 +17,79 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
 	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+	"github.com/lib/pq"
+	"sort"
 	"strconv"
 	"strings"
 )
 
-const createVariablesTableSql = `CREATE TABLE IF NOT EXISTS variables (
-    user_id VARCHAR ( 50 ) NOT NULL,
-    variable_key VARCHAR ( 255 ) NOT NULL,
-    process_definition_id VARCHAR ( 64 ),
-    process_instance_id VARCHAR ( 64 ),
-    unix_timestamp_in_s INT,
-    variable_value json,
-    PRIMARY KEY (user_id, variable_key)
-);`
-
-const createVariablesIndexesSql = `
-CREATE INDEX IF NOT EXISTS variable_process_definition ON variables (process_definition_id);
-CREATE INDEX IF NOT EXISTS variable_process_instance ON variables (process_instance_id);
-`
+// notExpiredSql filters expired variables; expects the current unix timestamp in s as parameter
+const notExpiredSql = `(expires_at = 0 OR expires_at > $%d)`
 
-func init() {
-	CreateTable = append(CreateTable, func(db *Pg) error {
-		ctx, _ := getTimeoutContext()
-		_, err := db.db.ExecContext(ctx, createVariablesTableSql)
-		if err != nil {
-			return err
-		}
-		_, err = db.db.ExecContext(ctx, createVariablesIndexesSql)
-		if err != nil {
-			return err
-		}
-		return nil
-	})
+var getVariableSql = `SELECT user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, revision, expires_at, content_type FROM variables WHERE user_id = $1 AND variable_key = $2 AND ` + fmt.Sprintf(notExpiredSql, 3)
+
+func (this *Pg) GetVariable(ctx context.Context, userId string, key string) (result model.VariableWithUser, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	var jsonValue []byte
+	err = this.getExecutor(ctx).QueryRowContext(ctx, getVariableSql, userId, key, configuration.TimeNow().Unix()).Scan(
+		&result.UserId,
+		&result.Key,
+		&result.ProcessDefinitionId,
+		&result.ProcessInstanceId,
+		&result.UnixTimestampInS,
+		&jsonValue,
+		&result.Revision,
+		&result.ExpiresAt,
+		&result.ContentType,
+	)
+	if err == sql.ErrNoRows {
+		return model.VariableWithUser{
+			VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
+				Variable: model.Variable{
+					Key:                 key,
+					Value:               nil,
+					ProcessDefinitionId: "",
+					ProcessInstanceId:   "",
+				},
+				UnixTimestampInS: 0,
+			},
+			UserId: userId,
+		}, nil
+	}
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(jsonValue, &result.Value)
+	return result, err
 }
 
-const getVariableSql = `SELECT user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value  FROM variables WHERE user_id = $1 AND variable_key = $2`
+// the #> operator returns NULL if the path does not exist and a json null if the referenced element is null
+var getVariableAtPointerSql = `SELECT user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value #> $3, revision, expires_at, content_type FROM variables WHERE user_id = $1 AND variable_key = $2 AND ` + fmt.Sprintf(notExpiredSql, 4)
 
-func (this *Pg) GetVariable(userId string, key string) (result model.VariableWithUser, err error) {
-	ctx, _ := getTimeoutContext()
+func (this *Pg) GetVariableAtPointer(ctx context.Context, userId string, key string, pointer []string) (result model.VariableWithUser, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
 	var jsonValue []byte
-	err = this.db.QueryRowContext(ctx, getVariableSql, userId, key).Scan(
+	err = this.getExecutor(ctx).QueryRowContext(ctx, getVariableAtPointerSql, userId, key, pq.Array(pointer), configuration.TimeNow().Unix()).Scan(
 		&result.UserId,
 		&result.Key,
 		&result.ProcessDefinitionId,
 		&result.ProcessInstanceId,
 		&result.UnixTimestampInS,
 		&jsonValue,
+		&result.Revision,
+		&result.ExpiresAt,
+		&result.ContentType,
 	)
 	if err == sql.ErrNoRows {
 		return model.VariableWithUser{
@@ -84,90 +108,224 @@ func (this *Pg) GetVariable(userId string, key string) (result model.VariableWit
 	if err != nil {
 		return result, err
 	}
+	if jsonValue == nil {
+		return result, fmt.Errorf("%w: %q", model.ErrPointerNotFound, jsondoc.FormatPointer(pointer))
+	}
 	err = json.Unmarshal(jsonValue, &result.Value)
 	return result, err
 }
 
+// removedRevisionsSql is the common table expression remembering the revisions of the rows returned by the "deleted" expression,
+// which has to return user_id, variable_key and revision; see nextRevisionSql
+const removedRevisionsSql = `removed AS (
+    INSERT INTO variable_revisions (user_id, variable_key, revision) SELECT user_id, variable_key, revision FROM deleted
+    ON CONFLICT (user_id, variable_key) DO UPDATE SET revision = GREATEST(variable_revisions.revision, excluded.revision)
+)`
+
+// nextRevisionSql is the revision of a created variable: revisions of removed variables are remembered,
+// so that recreated variables continue with higher revisions and outdated ETags do not match; expects user_id as $1 and variable_key as $2
+const nextRevisionSql = `COALESCE((SELECT revision FROM variable_revisions WHERE user_id = $1 AND variable_key = $2), 0) + 1`
+
+const deleteExpiredVariableSql = `
+WITH deleted AS (
+    DELETE FROM variables WHERE user_id = $1 AND variable_key = $2 AND expires_at > 0 AND expires_at <= $3 RETURNING user_id, variable_key, revision
+),
+history AS (
+    DELETE FROM variable_history WHERE user_id = $1 AND variable_key = $2 AND EXISTS (SELECT 1 FROM deleted)
+),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted;
+`
+
+// writeVariable runs write in a transaction after deleting the variable and its history if the variable is expired,
+// so that following writes start with a new history
+func (this *Pg) writeVariable(ctx context.Context, userId string, key string, write func(ctx context.Context) error) error {
+	return this.Transaction(ctx, func(ctx context.Context) error {
+		timeoutCtx, cancel := this.getTimeoutContext(ctx)
+		defer cancel()
+		_, err := this.getExecutor(ctx).ExecContext(timeoutCtx, deleteExpiredVariableSql, userId, key, configuration.TimeNow().Unix())
+		if err != nil {
+			return err
+		}
+		return write(ctx)
+	})
+}
+
 const setVariableSql = `
-INSERT INTO variables (user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value) 
-VALUES ($1, $2, $3, $4, $5, $6)
+INSERT INTO variables (user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, expires_at, content_type, revision) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ` + nextRevisionSql + `)
 ON CONFLICT (user_id, variable_key) DO UPDATE 
   SET process_definition_id = excluded.process_definition_id, 
       process_instance_id = excluded.process_instance_id,
       unix_timestamp_in_s = excluded.unix_timestamp_in_s,
-      variable_value = excluded.variable_value;
+      variable_value = excluded.variable_value,
+      expires_at = excluded.expires_at,
+      content_type = excluded.content_type,
+      revision = variables.revision + 1;
 `
 
-func (this *Pg) SetVariable(variable model.VariableWithUser) error {
+func (this *Pg) SetVariable(ctx context.Context, variable model.VariableWithUser) error {
 	jsonValue, err := json.Marshal(variable.Value)
 	if err != nil {
 		return err
 	}
-	ctx, _ := getTimeoutContext()
-	_, err = this.db.ExecContext(ctx, setVariableSql,
+	return this.writeVariable(ctx, variable.UserId, variable.Key, func(ctx context.Context) error {
+		ctx, cancel := this.getTimeoutContext(ctx)
+		defer cancel()
+		_, err = this.getExecutor(ctx).ExecContext(ctx, setVariableSql,
+			variable.UserId,
+			variable.Key,
+			variable.ProcessDefinitionId,
+			variable.ProcessInstanceId,
+			variable.UnixTimestampInS,
+			jsonValue,
+			variable.ExpiresAt,
+			variable.ContentType,
+		)
+		return err
+	})
+}
+
+const createVariableIfMissingSql = `
+INSERT INTO variables (user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, expires_at, content_type, revision) 
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, ` + nextRevisionSql + `)
+ON CONFLICT (user_id, variable_key) DO NOTHING
+RETURNING revision;
+`
+
+const updateVariableIfRevisionSql = `
+UPDATE variables
+  SET process_definition_id = $3,
+      process_instance_id = $4,
+      unix_timestamp_in_s = $5,
+      variable_value = $6,
+      expires_at = $7,
+      content_type = $8,
+      revision = revision + 1
+  WHERE user_id = $1 AND variable_key = $2 AND revision = $9
+RETURNING revision;
+`
+
+func (this *Pg) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (newRevision int64, ok bool, err error) {
+	jsonValue, err := json.Marshal(variable.Value)
+	if err != nil {
+		return 0, false, err
+	}
+	args := []interface{}{
 		variable.UserId,
 		variable.Key,
 		variable.ProcessDefinitionId,
 		variable.ProcessInstanceId,
 		variable.UnixTimestampInS,
 		jsonValue,
-	)
-	return err
+		variable.ExpiresAt,
+		variable.ContentType,
+	}
+	query := createVariableIfMissingSql
+	if revision > 0 {
+		query = updateVariableIfRevisionSql
+		args = append(args, revision)
+	}
+	err = this.writeVariable(ctx, variable.UserId, variable.Key, func(ctx context.Context) error {
+		ctx, cancel := this.getTimeoutContext(ctx)
+		defer cancel()
+		err := this.getExecutor(ctx).QueryRowContext(ctx, query, args...).Scan(&newRevision)
+		if err == sql.ErrNoRows {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		ok = true
+		return nil
+	})
+	return newRevision, ok, err
 }
 
-const deleteVariableSql = `DELETE FROM variables WHERE user_id = $1 AND variable_key = $2;`
+// incrementVariableSql adds as float8, to get the same results as the other databases, which add float64 values;
+// new variables are inserted like in setVariableSql, existing variables keep their expiry
+const incrementVariableSql = `
+INSERT INTO variables (user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, expires_at, content_type, revision) 
+VALUES ($1, $2, $3, $4, $5, to_jsonb($6::float8), $7, $8, ` + nextRevisionSql + `)
+ON CONFLICT (user_id, variable_key) DO UPDATE 
+  SET unix_timestamp_in_s = excluded.unix_timestamp_in_s,
+      variable_value = to_jsonb(variables.variable_value::text::float8 + $6::float8),
+      revision = variables.revision + 1
+RETURNING process_definition_id, process_instance_id, variable_value, revision, expires_at, content_type;
+`
+
+// pgInvalidTextRepresentation is returned if the stored json value can not be cast to float8
+const pgInvalidTextRepresentation = "22P02"
 
-func (this *Pg) DeleteVariable(userId string, key string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.db.ExecContext(ctx, deleteVariableSql,
+func (this *Pg) IncrementVariable(ctx context.Context, variable model.VariableWithUser, delta float64) (result model.VariableWithUser, err error) {
+	result = variable
+	var jsonValue []byte
+	err = this.writeVariable(ctx, variable.UserId, variable.Key, func(ctx context.Context) error {
+		ctx, cancel := this.getTimeoutContext(ctx)
+		defer cancel()
+		return this.getExecutor(ctx).QueryRowContext(ctx, incrementVariableSql,
+			variable.UserId,
+			variable.Key,
+			variable.ProcessDefinitionId,
+			variable.ProcessInstanceId,
+			variable.UnixTimestampInS,
+			delta,
+			variable.ExpiresAt,
+			variable.ContentType,
+		).Scan(
+			&result.ProcessDefinitionId,
+			&result.ProcessInstanceId,
+			&jsonValue,
+			&result.Revision,
+			&result.ExpiresAt,
+			&result.ContentType,
+		)
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgInvalidTextRepresentation {
+		return result, model.ErrNotNumeric
+	}
+	if err != nil {
+		return result, err
+	}
+	err = json.Unmarshal(jsonValue, &result.Value)
+	return result, err
+}
+
+func (this *Pg) UpdateVariable(ctx context.Context, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (model.VariableWithUser, error) {
+	return util.UpdateVariable(ctx, this, userId, key, update)
+}
+
+const deleteVariableSql = `
+WITH history AS (DELETE FROM variable_history WHERE user_id = $1 AND variable_key = $2),
+deleted AS (DELETE FROM variables WHERE user_id = $1 AND variable_key = $2 RETURNING user_id, variable_key, revision),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted;
+`
+
+func (this *Pg) DeleteVariable(ctx context.Context, userId string, key string) error {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	_, err := this.getExecutor(ctx).ExecContext(ctx, deleteVariableSql,
 		userId,
 		key,
 	)
 	return err
 }
 
-func (this *Pg) ListVariables(userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
-	sqlQueryParts := []string{"SELECT variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value FROM variables"}
-	args := []interface{}{
-		userId,
+func (this *Pg) ListVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result []model.VariableWithUnixTimestamp, err error) {
+	sqlQueryParts := []string{"SELECT variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value, expires_at, content_type FROM variables"}
+	where, args, err := getVariablesWhere(userId, query)
+	if err != nil {
+		return result, err
 	}
+	sqlQueryParts = append(sqlQueryParts, where)
 
-	whereParts := []string{"WHERE user_id = $1"}
-	if query.ProcessDefinitionId != "" {
-		whereParts = append(whereParts, "process_definition_id = $"+(strconv.Itoa(len(args)+1)))
-		args = append(args, query.ProcessDefinitionId)
-	}
-	if query.ProcessInstanceId != "" {
-		whereParts = append(whereParts, "process_instance_id = $"+(strconv.Itoa(len(args)+1)))
-		args = append(args, query.ProcessInstanceId)
-	}
-	if query.KeyRegex != "" {
-		whereParts = append(whereParts, "variable_key ~ $"+(strconv.Itoa(len(args)+1)))
-		args = append(args, query.KeyRegex)
-	}
-	sqlQueryParts = append(sqlQueryParts, strings.Join(whereParts, " AND "))
-
-	if query.Sort != "" {
-		sortField := ""
-		sortDir := ""
-		if strings.HasSuffix(query.Sort, ".asc") {
-			sortField = strings.TrimSuffix(query.Sort, ".asc")
-			sortDir = "ASC"
-		} else if strings.HasSuffix(query.Sort, ".desc") {
-			sortField = strings.TrimSuffix(query.Sort, ".desc")
-			sortDir = "DESC"
-		} else {
-			sortField = query.Sort
-		}
-		switch sortField {
-		case "key":
-			sortField = "variable_key"
-		}
-		sqlQueryParts = append(sqlQueryParts, "ORDER BY "+sortField)
-		if sortDir != "" {
-			sqlQueryParts = append(sqlQueryParts, sortDir)
-		}
+	orderBy, args, err := getOrderBy(args, query)
+	if err != nil {
+		return nil, err
 	}
+	sqlQueryParts = append(sqlQueryParts, orderBy)
 
 	if query.Limit > 0 {
 		sqlQueryParts = append(sqlQueryParts, "Limit $"+(strconv.Itoa(len(args)+1))+" OFFSET $"+(strconv.Itoa(len(args)+2)))
@@ -175,8 +333,9 @@ func (this *Pg) ListVariables(userId string, query model.VariablesQueryOptions)
 	}
 
 	sqlQuery := strings.Join(sqlQueryParts, " ")
-	ctx, _ := getTimeoutContext()
-	rows, err := this.db.QueryContext(ctx, sqlQuery, args...)
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	rows, err := this.getExecutor(ctx).QueryContext(ctx, sqlQuery, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -188,7 +347,9 @@ func (this *Pg) ListVariables(userId string, query model.VariablesQueryOptions)
 			&element.ProcessDefinitionId,
 			&element.ProcessInstanceId,
 			&element.UnixTimestampInS,
-			&jsonValue)
+			&jsonValue,
+			&element.ExpiresAt,
+			&element.ContentType)
 		if err != nil {
 			return nil, err
 		}
@@ -198,16 +359,35 @@ func (this *Pg) ListVariables(userId string, query model.VariablesQueryOptions)
 		}
 		result = append(result, element)
 	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-func (this *Pg) CountVariables(userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
+func (this *Pg) CountVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (result model.Count, err error) {
 	sqlQueryParts := []string{"SELECT COUNT(*) FROM variables"}
-	args := []interface{}{
-		userId,
+	where, args, err := getVariablesWhere(userId, query)
+	if err != nil {
+		return result, err
 	}
+	sqlQueryParts = append(sqlQueryParts, where)
 
-	whereParts := []string{"WHERE user_id = $1"}
+	sqlQuery := strings.Join(sqlQueryParts, " ")
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.getExecutor(ctx).QueryRowContext(ctx, sqlQuery, args...).Scan(&result.Count)
+	return result, err
+}
+
+// getVariablesWhere returns the WHERE clause and its arguments selecting the not expired variables of the user
+// matching the filters and the cursor of the query
+func getVariablesWhere(userId string, query model.VariablesQueryOptions) (where string, args []interface{}, err error) {
+	args = []interface{}{
+		userId,
+		configuration.TimeNow().Unix(),
+	}
+	whereParts := []string{"WHERE user_id = $1", fmt.Sprintf(notExpiredSql, 2)}
 	if query.ProcessDefinitionId != "" {
 		whereParts = append(whereParts, "process_definition_id = $"+(strconv.Itoa(len(args)+1)))
 		args = append(args, query.ProcessDefinitionId)
@@ -216,30 +396,243 @@ func (this *Pg) CountVariables(userId string, query model.VariablesQueryOptions)
 		whereParts = append(whereParts, "process_instance_id = $"+(strconv.Itoa(len(args)+1)))
 		args = append(args, query.ProcessInstanceId)
 	}
-	if query.KeyRegex != "" {
-		whereParts = append(whereParts, "variable_key ~ $"+(strconv.Itoa(len(args)+1)))
-		args = append(args, query.KeyRegex)
+	whereParts, args, err = appendKeyFilters(whereParts, args, query)
+	if err != nil {
+		return "", nil, err
+	}
+	if query.UpdatedAfter != 0 {
+		whereParts = append(whereParts, "unix_timestamp_in_s > $"+(strconv.Itoa(len(args)+1)))
+		args = append(args, query.UpdatedAfter)
+	}
+	if query.UpdatedBefore != 0 {
+		whereParts = append(whereParts, "unix_timestamp_in_s < $"+(strconv.Itoa(len(args)+1)))
+		args = append(args, query.UpdatedBefore)
+	}
+	whereParts, args, err = appendValueFilters(whereParts, args, query.ValueFilters)
+	if err != nil {
+		return "", nil, err
+	}
+	whereParts, args, err = appendCursor(whereParts, args, query)
+	if err != nil {
+		return "", nil, err
 	}
-	sqlQueryParts = append(sqlQueryParts, strings.Join(whereParts, " AND "))
+	return strings.Join(whereParts, " AND "), args, nil
+}
 
-	sqlQuery := strings.Join(sqlQueryParts, " ")
-	ctx, _ := getTimeoutContext()
-	err = this.db.QueryRowContext(ctx, sqlQuery, args...).Scan(&result.Count)
-	return result, err
+// appendKeyFilters adds the key filters of the query; prefixes and globs are matched with LIKE,
+// which may use the variables_user_key_pattern_index
+func appendKeyFilters(whereParts []string, args []interface{}, query model.VariablesQueryOptions) ([]string, []interface{}, error) {
+	keys, err := util.ParseKeyFilters(query)
+	if err != nil || keys == nil {
+		return whereParts, args, err
+	}
+	if len(keys.Keys) > 0 {
+		whereParts = append(whereParts, "variable_key = ANY($"+strconv.Itoa(len(args)+1)+")")
+		args = append(args, pq.Array(keys.Keys))
+	}
+	if keys.Prefix != "" {
+		whereParts = append(whereParts, "variable_key LIKE $"+strconv.Itoa(len(args)+1)+" ESCAPE '\\'")
+		args = append(args, util.EscapeLike(keys.Prefix)+"%")
+	}
+	if query.KeyGlob != "" {
+		like, err := util.GlobToLike(query.KeyGlob)
+		if err != nil {
+			return whereParts, args, err
+		}
+		whereParts = append(whereParts, "variable_key LIKE $"+strconv.Itoa(len(args)+1)+" ESCAPE '\\'")
+		args = append(args, like)
+	}
+	if keys.Regex != "" {
+		operator := "~"
+		if keys.RegexCaseInsensitive {
+			operator = "~*"
+		}
+		whereParts = append(whereParts, "variable_key "+operator+" $"+strconv.Itoa(len(args)+1))
+		args = append(args, keys.Regex)
+	}
+	return whereParts, args, nil
 }
 
-const deleteProcessDefinitionSql = `DELETE FROM variables WHERE process_definition_id = $1;`
+// sortColumns maps the sortable fields of variables to columns;
+// text columns are compared with the "C" collation, to order by bytes like the other databases, independent of the database collation;
+// the sort indexes (migration 9) use the same collation
+var sortColumns = map[string]string{
+	model.SortFieldKey:                 `variable_key COLLATE "C"`,
+	model.SortFieldTimestamp:           "unix_timestamp_in_s",
+	model.SortFieldProcessDefinitionId: `process_definition_id COLLATE "C"`,
+	model.SortFieldProcessInstanceId:   `process_instance_id COLLATE "C"`,
+}
 
-func (this *Pg) DeleteVariablesOfProcessDefinition(definitionId string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.db.ExecContext(ctx, deleteProcessDefinitionSql, definitionId)
-	return err
+func getSortDirection(key model.SortKey) string {
+	if key.Desc {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// getOrderBy returns the ORDER BY clause of the unique sort of the query (see model.Sort.Unique);
+// sorts by value elements append the pointer to the args
+func getOrderBy(args []interface{}, query model.VariablesQueryOptions) (string, []interface{}, error) {
+	sort, err := query.GetSortKeys()
+	if err != nil {
+		return "", args, err
+	}
+	parts := []string{}
+	for _, key := range sort.Unique() {
+		if key.Field == model.SortFieldValue {
+			pointer, err := util.ParseSortPointer(key)
+			if err != nil {
+				return "", args, err
+			}
+			args = append(args, pq.Array(pointer))
+			parts = append(parts, fmt.Sprintf(valueSortSql, len(args), getSortDirection(key)))
+		} else {
+			parts = append(parts, sortColumns[key.Field]+" "+getSortDirection(key))
+		}
+	}
+	return "ORDER BY " + strings.Join(parts, ", "), args, nil
 }
 
-const deleteProcessInstanceSql = `DELETE FROM variables WHERE process_instance_id = $1;`
+// appendCursor appends the condition selecting the variables sorted after the cursor of the query:
+// for every sort key, the variables with equal preceding sort fields and a following field
+func appendCursor(whereParts []string, args []interface{}, query model.VariablesQueryOptions) ([]string, []interface{}, error) {
+	cursor, err := util.ParseCursor(query)
+	if err != nil || cursor == nil {
+		return whereParts, args, err
+	}
+	conditions := []string{}
+	for i, key := range cursor.Sort {
+		parts := []string{}
+		for j := 0; j < i; j++ {
+			args = append(args, cursor.Values[j])
+			parts = append(parts, fmt.Sprintf("%v = $%d", sortColumns[cursor.Sort[j].Field], len(args)))
+		}
+		operator := ">"
+		if key.Desc {
+			operator = "<"
+		}
+		args = append(args, cursor.Values[i])
+		parts = append(parts, fmt.Sprintf("%v %v $%d", sortColumns[key.Field], operator, len(args)))
+		conditions = append(conditions, strings.Join(parts, " AND "))
+	}
+	whereParts = append(whereParts, "(("+strings.Join(conditions, ") OR (")+"))")
+	return whereParts, args, nil
+}
 
-func (this *Pg) DeleteVariablesOfProcessInstance(instanceId string) error {
-	ctx, _ := getTimeoutContext()
-	_, err := this.db.ExecContext(ctx, deleteProcessInstanceSql, instanceId)
-	return err
+// valueSortSql orders by the element of the value referenced by the pointer parameter like mongodb sorts:
+// missing and null < numbers < strings < objects < arrays < booleans; objects and arrays are not ordered among each other.
+// strings are compared as text with the "C" collation, because jsonb compares strings with the database collation.
+// expects the pointer parameter index and the direction
+const valueSortSql = `CASE COALESCE(jsonb_typeof(variable_value #> $%[1]d), 'null')
+    WHEN 'number' THEN 2 WHEN 'string' THEN 3 WHEN 'object' THEN 4 WHEN 'array' THEN 5 WHEN 'boolean' THEN 6 ELSE 1 END %[2]s,
+  CASE WHEN jsonb_typeof(variable_value #> $%[1]d) IN ('number', 'boolean') THEN variable_value #> $%[1]d END %[2]s,
+  CASE WHEN jsonb_typeof(variable_value #> $%[1]d) = 'string' THEN variable_value #>> $%[1]d END COLLATE "C" %[2]s`
+
+// appendValueFilters appends the conditions of the value filters to the where parts; mirrors util.ValueFilter.Match
+func appendValueFilters(whereParts []string, args []interface{}, filters []model.ValueFilter) ([]string, []interface{}, error) {
+	parsed, err := util.ParseValueFilters(filters)
+	if err != nil {
+		return whereParts, args, err
+	}
+	for _, filter := range parsed {
+		args = append(args, pq.Array(filter.Pointer))
+		element := "variable_value #> $" + strconv.Itoa(len(args))
+		values := []string{}
+		for _, value := range filter.Values {
+			jsonValue, err := json.Marshal(value)
+			if err != nil {
+				return whereParts, args, err
+			}
+			args = append(args, string(jsonValue))
+			values = append(values, "$"+strconv.Itoa(len(args))+"::jsonb")
+		}
+		switch filter.Operator {
+		case model.ValueFilterExists:
+			whereParts = append(whereParts, element+" IS NOT NULL")
+		case model.ValueFilterEq:
+			whereParts = append(whereParts, element+" = "+values[0])
+		case model.ValueFilterNe:
+			whereParts = append(whereParts, "NOT COALESCE("+element+" = "+values[0]+", false)")
+		case model.ValueFilterIn:
+			whereParts = append(whereParts, element+" IN ("+strings.Join(values, ", ")+")")
+		default:
+			operator := map[string]string{model.ValueFilterGt: ">", model.ValueFilterGte: ">=", model.ValueFilterLt: "<", model.ValueFilterLte: "<="}[filter.Operator]
+			whereParts = append(whereParts, "(jsonb_typeof("+element+") = jsonb_typeof("+values[0]+") AND "+element+" "+operator+" "+values[0]+")")
+		}
+	}
+	return whereParts, args, nil
+}
+
+// deleteProcessDefinitionSql deletes the variables of the process definition and their history
+// and returns the number of deleted not expired variables
+var deleteProcessDefinitionSql = `
+WITH history AS (DELETE FROM variable_history WHERE process_definition_id = $1),
+deleted AS (DELETE FROM variables WHERE process_definition_id = $1 RETURNING user_id, variable_key, revision, expires_at),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted WHERE ` + fmt.Sprintf(notExpiredSql, 2) + `;
+`
+
+func (this *Pg) ListUserIds(ctx context.Context) (result []string, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	rows, err := this.getExecutor(ctx).QueryContext(ctx, "SELECT DISTINCT user_id FROM variables")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var userId string
+		err = rows.Scan(&userId)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, userId)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// deleteVariablesSqlFormat deletes the variables selected by the WHERE clause (%v) and their history
+// and returns the number of deleted variables
+const deleteVariablesSqlFormat = `
+WITH deleted AS (DELETE FROM variables %v RETURNING user_id, variable_key, revision),
+history AS (DELETE FROM variable_history WHERE (user_id, variable_key) IN (SELECT user_id, variable_key FROM deleted)),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted;
+`
+
+func (this *Pg) DeleteVariables(ctx context.Context, userId string, query model.VariablesQueryOptions) (count int64, err error) {
+	where, args, err := getVariablesWhere(userId, query)
+	if err != nil {
+		return 0, err
+	}
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.getExecutor(ctx).QueryRowContext(ctx, fmt.Sprintf(deleteVariablesSqlFormat, where), args...).Scan(&count)
+	return count, err
+}
+
+func (this *Pg) DeleteVariablesOfProcessDefinition(ctx context.Context, definitionId string) (count int64, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.getExecutor(ctx).QueryRowContext(ctx, deleteProcessDefinitionSql, definitionId, configuration.TimeNow().Unix()).Scan(&count)
+	return count, err
+}
+
+// deleteProcessInstanceSql is deleteProcessDefinitionSql for process instances
+var deleteProcessInstanceSql = `
+WITH history AS (DELETE FROM variable_history WHERE process_instance_id = $1),
+deleted AS (DELETE FROM variables WHERE process_instance_id = $1 RETURNING user_id, variable_key, revision, expires_at),
+` + removedRevisionsSql + `
+SELECT COUNT(*) FROM deleted WHERE ` + fmt.Sprintf(notExpiredSql, 2) + `;
+`
+
+func (this *Pg) DeleteVariablesOfProcessInstance(ctx context.Context, instanceId string) (count int64, err error) {
+	ctx, cancel := this.getTimeoutContext(ctx)
+	defer cancel()
+	err = this.getExecutor(ctx).QueryRowContext(ctx, deleteProcessInstanceSql, instanceId, configuration.TimeNow().Unix()).Scan(&count)
+	return count, err
 }
@@ -0,0 +1,102 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// Cursor is a decoded and validated model.VariablesQueryOptions.Cursor
+type Cursor struct {
+	Sort   model.Sort    //unique sort of the query, see model.Sort.Unique
+	Values []interface{} //one per element of Sort
+	Owner  string        //see model.VariablesCursor.Owner
+}
+
+// ParseCursor decodes the cursor of the query and checks that it belongs to the sort of the query;
+// returns nil if the query has no cursor
+func ParseCursor(query model.VariablesQueryOptions) (*Cursor, error) {
+	if query.Cursor == "" {
+		return nil, nil
+	}
+	if query.Offset > 0 {
+		return nil, fmt.Errorf("%w: cursor and offset may not be combined", model.ErrInvalidCursor)
+	}
+	sort, err := query.GetSortKeys()
+	if err != nil {
+		return nil, err
+	}
+	sort = sort.Unique()
+	cursor, err := model.DecodeVariablesCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	if cursor.Sort != sort.String() {
+		return nil, fmt.Errorf("%w: cursor of sort %v used with sort %v", model.ErrInvalidCursor, cursor.Sort, sort.String())
+	}
+	example, ok := model.NewVariablesCursor(sort, model.VariableWithUnixTimestamp{})
+	if !ok {
+		return nil, fmt.Errorf("%w: sorts by value elements do not support cursors", model.ErrInvalidCursor)
+	}
+	if len(cursor.Values) != len(example.Values) {
+		return nil, fmt.Errorf("%w: unexpected number of values", model.ErrInvalidCursor)
+	}
+	for i, value := range cursor.Values {
+		if reflect.TypeOf(value) != reflect.TypeOf(example.Values[i]) {
+			return nil, fmt.Errorf("%w: unexpected value type", model.ErrInvalidCursor)
+		}
+	}
+	return &Cursor{Sort: sort, Values: cursor.Values, Owner: cursor.Owner}, nil
+}
+
+// After checks if the variable is sorted after the cursor position;
+// variables with equal sort fields are ordered by owner, see SortVariables
+func (this *Cursor) After(variable model.VariableWithUnixTimestamp) bool {
+	result := this.compare(variable)
+	if result != 0 {
+		return result > 0
+	}
+	return variable.Owner > this.Owner
+}
+
+// Ties checks if the sort fields of the variable equal the cursor position, so that only the owner orders them
+func (this *Cursor) Ties(variable model.VariableWithUnixTimestamp) bool {
+	return this.compare(variable) == 0
+}
+
+// Key returns the key of the cursor position
+func (this *Cursor) Key() string {
+	key, _ := this.Values[len(this.Values)-1].(string)
+	return key
+}
+
+func (this *Cursor) compare(variable model.VariableWithUnixTimestamp) int {
+	for i, key := range this.Sort {
+		field, _ := key.Get(variable)
+		result := compareSortFields(field, this.Values[i])
+		if key.Desc {
+			result = -result
+		}
+		if result != 0 {
+			return result
+		}
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// IsPruned returns true if the retention removes the version from the history of a variable with the current revision;
+// mirrors the PruneVariableHistory queries of the mongo and postgres implementations
+func IsPruned(version model.VariableVersion, currentRevision int64, retention model.HistoryRetention) bool {
+	if version.Revision >= currentRevision {
+		return false
+	}
+	if retention.MaxVersions > 0 && version.Revision <= currentRevision-retention.MaxVersions {
+		return true
+	}
+	if retention.MinUnixTimestampInS > 0 && version.UnixTimestampInS < retention.MinUnixTimestampInS {
+		return true
+	}
+	return false
+}
+
+// PaginateVersions applies the limit and offset of the query to the (already sorted) list
+func PaginateVersions(list []model.VariableVersion, query model.HistoryQueryOptions) []model.VariableVersion {
+	offset := query.Offset
+	if offset > len(list) {
+		offset = len(list)
+	}
+	if offset > 0 {
+		list = list[offset:]
+	}
+	if query.Limit > 0 && query.Limit < len(list) {
+		list = list[:query.Limit]
+	}
+	return list
+}
@@ -0,0 +1,232 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// KeyMatcher matches variable keys against the key filters of model.VariablesQueryOptions;
+// all set filters must match
+type KeyMatcher struct {
+	Keys                 []string
+	Prefix               string
+	Glob                 string //anchored regular expression translated from model.VariablesQueryOptions.KeyGlob
+	Regex                string //model.VariablesQueryOptions.KeyRegex
+	RegexCaseInsensitive bool
+	glob                 *regexp.Regexp
+	regex                *regexp.Regexp
+}
+
+// ParseKeyFilters validates the key filters of the query; returns nil if the query has no key filters.
+// regular expressions are restricted to the syntax shared by RE2 (memory, bolt), PCRE (mongodb) and postgres, see ValidateRegex
+func ParseKeyFilters(query model.VariablesQueryOptions) (*KeyMatcher, error) {
+	if len(query.Keys) == 0 && query.KeyPrefix == "" && query.KeyGlob == "" && query.KeyRegex == "" {
+		return nil, nil
+	}
+	result := &KeyMatcher{
+		Keys:                 query.Keys,
+		Prefix:               query.KeyPrefix,
+		Regex:                query.KeyRegex,
+		RegexCaseInsensitive: !query.KeyRegexCaseSensitive,
+	}
+	var err error
+	if query.KeyGlob != "" {
+		result.Glob, err = GlobToRegex(query.KeyGlob)
+		if err != nil {
+			return nil, err
+		}
+		result.glob, err = regexp.Compile("(?s)" + result.Glob)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidKeyFilter, err)
+		}
+	}
+	if query.KeyRegex != "" {
+		flags := ""
+		if result.RegexCaseInsensitive {
+			flags = "(?i)"
+		}
+		result.regex, err = regexp.Compile(flags + query.KeyRegex)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidKeyFilter, err)
+		}
+		err = ValidateRegex(query.KeyRegex)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// maxRegexRepeat is the largest repetition count accepted by postgres
+const maxRegexRepeat = 255
+
+// ValidateRegex returns an error wrapping model.ErrInvalidKeyFilter if the regular expression uses syntax
+// which is not interpreted the same way by RE2, PCRE and postgres:
+// flags and other (?...) groups except (?:...), escapes of letters and digits except \d, \s and \w and their negations,
+// negated class escapes in brackets and repetitions above 255
+func ValidateRegex(expr string) error {
+	inBrackets := false
+	for i := 0; i < len(expr); i++ {
+		switch expr[i] {
+		case '\\':
+			if i+1 >= len(expr) {
+				return nil //rejected by regexp.Compile
+			}
+			i++
+			char := expr[i]
+			switch {
+			case strings.IndexByte("dsw", char) >= 0:
+			case strings.IndexByte("DSW", char) >= 0 && !inBrackets:
+			case char < utf8.RuneSelf && !unicode.IsLetter(rune(char)) && !unicode.IsDigit(rune(char)):
+			default:
+				return fmt.Errorf("%w: unsupported escape \\%c", model.ErrInvalidKeyFilter, char)
+			}
+		case '[':
+			if !inBrackets {
+				inBrackets = true
+				//a leading ']' (after an optional '^') is a literal
+				if strings.HasPrefix(expr[i+1:], "^]") {
+					i += 2
+				} else if strings.HasPrefix(expr[i+1:], "]") {
+					i++
+				}
+			} else if strings.HasPrefix(expr[i+1:], ":") {
+				end := strings.Index(expr[i+1:], ":]")
+				if end >= 0 {
+					i += end + 2 //skip character class names like [:alpha:]
+				}
+			}
+		case ']':
+			inBrackets = false
+		case '(':
+			if !inBrackets && strings.HasPrefix(expr[i+1:], "?") && !strings.HasPrefix(expr[i+1:], "?:") {
+				return fmt.Errorf("%w: unsupported group or flags %v", model.ErrInvalidKeyFilter, expr[i:min(i+3, len(expr))])
+			}
+		}
+	}
+	parsed, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return errors.Join(model.ErrInvalidKeyFilter, err)
+	}
+	return validateRegexRepeats(parsed)
+}
+
+func validateRegexRepeats(expr *syntax.Regexp) error {
+	if expr.Op == syntax.OpRepeat && (expr.Min > maxRegexRepeat || expr.Max > maxRegexRepeat) {
+		return fmt.Errorf("%w: repetitions above %v are not supported", model.ErrInvalidKeyFilter, maxRegexRepeat)
+	}
+	for _, sub := range expr.Sub {
+		err := validateRegexRepeats(sub)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (this *KeyMatcher) Match(key string) bool {
+	if len(this.Keys) > 0 && !slices.Contains(this.Keys, key) {
+		return false
+	}
+	if !strings.HasPrefix(key, this.Prefix) {
+		return false
+	}
+	if this.glob != nil && !this.glob.MatchString(key) {
+		return false
+	}
+	if this.regex != nil && !this.regex.MatchString(key) {
+		return false
+	}
+	return true
+}
+
+// GlobToRegex translates a glob pattern (see model.VariablesQueryOptions.KeyGlob) to an anchored regular expression;
+// '?' and '*' must match newlines too, which requires the s flag
+func GlobToRegex(glob string) (string, error) {
+	result := strings.Builder{}
+	result.WriteString("^")
+	err := walkGlob(glob, func(literal string) {
+		result.WriteString(regexp.QuoteMeta(literal))
+	}, func(wildcard rune) {
+		if wildcard == '*' {
+			result.WriteString(".*")
+		} else {
+			result.WriteString(".")
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	result.WriteString("$")
+	return result.String(), nil
+}
+
+// GlobToLike translates a glob pattern (see model.VariablesQueryOptions.KeyGlob) to a sql LIKE pattern with '\' as escape character
+func GlobToLike(glob string) (string, error) {
+	result := strings.Builder{}
+	err := walkGlob(glob, func(literal string) {
+		result.WriteString(EscapeLike(literal))
+	}, func(wildcard rune) {
+		if wildcard == '*' {
+			result.WriteString("%")
+		} else {
+			result.WriteString("_")
+		}
+	})
+	if err != nil {
+		return "", err
+	}
+	return result.String(), nil
+}
+
+// EscapeLike escapes the wildcards of sql LIKE patterns with '\' as escape character
+func EscapeLike(literal string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(literal)
+}
+
+// walkGlob calls literal for every literal character and wildcard for every '*' and '?' of the glob pattern;
+// a trailing '\' is invalid
+func walkGlob(glob string, literal func(string), wildcard func(rune)) error {
+	escaped := false
+	for _, char := range glob {
+		switch {
+		case escaped:
+			literal(string(char))
+			escaped = false
+		case char == '\\':
+			escaped = true
+		case char == '*' || char == '?':
+			wildcard(char)
+		default:
+			literal(string(char))
+		}
+	}
+	if escaped {
+		return errors.Join(model.ErrInvalidKeyFilter, errors.New("glob pattern ends with an unescaped '\\'"))
+	}
+	return nil
+}
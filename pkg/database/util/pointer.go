@@ -0,0 +1,36 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+)
+
+// SelectPointer replaces the value of the variable with the element referenced by the json pointer tokens;
+// variables that do not exist are returned unchanged
+func SelectPointer(variable model.VariableWithUser, pointer []string) (model.VariableWithUser, error) {
+	if variable.Revision == 0 || len(pointer) == 0 {
+		return variable, nil
+	}
+	value, err := jsondoc.Normalize(variable.Value)
+	if err != nil {
+		return variable, err
+	}
+	variable.Value, err = jsondoc.Get(value, pointer)
+	return variable, err
+}
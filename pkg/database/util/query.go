@@ -0,0 +1,153 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"cmp"
+	"errors"
+	"sort"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+)
+
+// NewMatcher returns a filter function for in-process database implementations
+// which mirrors the filter semantics of model.VariablesQueryOptions in the mongo and postgres implementations;
+// expects variables with normalized values
+func NewMatcher(query model.VariablesQueryOptions) (func(variable model.VariableWithUnixTimestamp) bool, error) {
+	keys, err := ParseKeyFilters(query)
+	if err != nil {
+		return nil, err
+	}
+	valueFilters, err := ParseValueFilters(query.ValueFilters)
+	if err != nil {
+		return nil, err
+	}
+	cursor, err := ParseCursor(query)
+	if err != nil {
+		return nil, err
+	}
+	return func(variable model.VariableWithUnixTimestamp) bool {
+		if query.ProcessDefinitionId != "" && variable.ProcessDefinitionId != query.ProcessDefinitionId {
+			return false
+		}
+		if query.ProcessInstanceId != "" && variable.ProcessInstanceId != query.ProcessInstanceId {
+			return false
+		}
+		if keys != nil && !keys.Match(variable.Key) {
+			return false
+		}
+		if query.UpdatedAfter != 0 && variable.UnixTimestampInS <= query.UpdatedAfter {
+			return false
+		}
+		if query.UpdatedBefore != 0 && variable.UnixTimestampInS >= query.UpdatedBefore {
+			return false
+		}
+		for _, filter := range valueFilters {
+			if !filter.Match(variable.Value) {
+				return false
+			}
+		}
+		if cursor != nil && !cursor.After(variable) {
+			return false
+		}
+		return true
+	}, nil
+}
+
+// SortVariables sorts the list in place according to the unique sort of the query (see model.Sort.Unique)
+func SortVariables(list []model.VariableWithUnixTimestamp, query model.VariablesQueryOptions) error {
+	sortKeys, err := query.GetSortKeys()
+	if err != nil {
+		return err
+	}
+	comparators := []func(a, b model.VariableWithUnixTimestamp) int{}
+	for _, key := range sortKeys.Unique() {
+		var compare func(a, b model.VariableWithUnixTimestamp) int
+		if key.Field == model.SortFieldValue {
+			pointer, err := ParseSortPointer(key)
+			if err != nil {
+				return err
+			}
+			compare = func(a, b model.VariableWithUnixTimestamp) int {
+				return CompareValueElements(a.Value, b.Value, pointer)
+			}
+		} else {
+			compare = func(a, b model.VariableWithUnixTimestamp) int {
+				fieldA, _ := key.Get(a)
+				fieldB, _ := key.Get(b)
+				return compareSortFields(fieldA, fieldB)
+			}
+		}
+		if key.Desc {
+			ascending := compare
+			compare = func(a, b model.VariableWithUnixTimestamp) int {
+				return -ascending(a, b)
+			}
+		}
+		comparators = append(comparators, compare)
+	}
+	//variables of different owners may share a key (see model.Share); own variables (without owner) come first
+	comparators = append(comparators, func(a, b model.VariableWithUnixTimestamp) int {
+		return cmp.Compare(a.Owner, b.Owner)
+	})
+	sort.SliceStable(list, func(i, j int) bool {
+		for _, compare := range comparators {
+			if result := compare(list[i], list[j]); result != 0 {
+				return result < 0
+			}
+		}
+		return false
+	})
+	return nil
+}
+
+// ParseSortPointer returns the json pointer tokens of a sort key with model.SortFieldValue
+func ParseSortPointer(key model.SortKey) ([]string, error) {
+	pointer, err := jsondoc.ParsePointer(key.Pointer)
+	if err != nil {
+		return nil, errors.Join(model.ErrInvalidSort, err)
+	}
+	return pointer, nil
+}
+
+// compareSortFields compares the results of model.SortKey.Get
+func compareSortFields(a interface{}, b interface{}) int {
+	switch a := a.(type) {
+	case string:
+		return cmp.Compare(a, b.(string))
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	default:
+		return 0
+	}
+}
+
+// Paginate applies the limit and offset of the query to the (already sorted) list
+func Paginate(list []model.VariableWithUnixTimestamp, query model.VariablesQueryOptions) []model.VariableWithUnixTimestamp {
+	offset := query.Offset
+	if offset > len(list) {
+		offset = len(list)
+	}
+	if offset > 0 {
+		list = list[offset:]
+	}
+	if query.Limit > 0 && query.Limit < len(list) {
+		list = list[:query.Limit]
+	}
+	return list
+}
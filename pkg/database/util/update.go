@@ -0,0 +1,59 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"context"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+type compareAndSetDatabase interface {
+	GetVariable(ctx context.Context, userId string, key string) (model.VariableWithUser, error)
+	SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (newRevision int64, ok bool, err error) //ok is false if the stored revision is not revision
+}
+
+// maxUpdateAttempts limits the retries of UpdateVariable if the variable is changed between read and write
+const maxUpdateAttempts = 10
+
+// UpdateVariable implements Database.UpdateVariable with compare-and-set for databases without a lock held over read and write:
+// if the variable is changed between read and write, update is called again with the new state;
+// returns model.ErrUpdateConflict if the variable is changed in each of maxUpdateAttempts attempts
+func UpdateVariable(ctx context.Context, db compareAndSetDatabase, userId string, key string, update func(current model.VariableWithUser) (model.VariableWithUser, error)) (result model.VariableWithUser, err error) {
+	for i := 0; i < maxUpdateAttempts; i++ {
+		current, err := db.GetVariable(ctx, userId, key)
+		if err != nil {
+			return result, err
+		}
+		result, err = update(current)
+		if err != nil {
+			return result, err
+		}
+		revision, ok, err := db.SetVariableIfRevision(ctx, result, current.Revision)
+		if err != nil {
+			return result, err
+		}
+		if ok {
+			result.Revision = revision
+			return result, nil
+		}
+		if err = ctx.Err(); err != nil {
+			return result, err
+		}
+	}
+	return result, model.ErrUpdateConflict
+}
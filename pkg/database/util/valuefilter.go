@@ -0,0 +1,201 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package util
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model/jsondoc"
+)
+
+// ValueFilter is a validated model.ValueFilter
+type ValueFilter struct {
+	Pointer  []string
+	Operator string
+	Values   []interface{} //normalized scalars; one element except for model.ValueFilterIn, empty for model.ValueFilterExists
+}
+
+// ParseValueFilters validates the filters; returns model.ErrInvalidValueFilter for unknown operators, invalid paths and values
+func ParseValueFilters(filters []model.ValueFilter) (result []ValueFilter, err error) {
+	for _, filter := range filters {
+		parsed, err := parseValueFilter(filter)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", model.ErrInvalidValueFilter, err)
+		}
+		result = append(result, parsed)
+	}
+	return result, nil
+}
+
+func parseValueFilter(filter model.ValueFilter) (result ValueFilter, err error) {
+	result.Operator = filter.Operator
+	result.Pointer, err = jsondoc.ParsePointer(filter.Path)
+	if err != nil {
+		return result, err
+	}
+	value, err := jsondoc.Normalize(filter.Value)
+	if err != nil {
+		return result, err
+	}
+	switch filter.Operator {
+	case model.ValueFilterExists:
+		return result, nil
+	case model.ValueFilterIn:
+		list, ok := value.([]interface{})
+		if !ok || len(list) == 0 {
+			return result, fmt.Errorf("%v expects a non empty list", filter.Operator)
+		}
+		result.Values = list
+	case model.ValueFilterEq, model.ValueFilterNe:
+		result.Values = []interface{}{value}
+	case model.ValueFilterGt, model.ValueFilterGte, model.ValueFilterLt, model.ValueFilterLte:
+		switch value.(type) {
+		case float64, string:
+		default:
+			return result, fmt.Errorf("%v expects a number or string", filter.Operator)
+		}
+		result.Values = []interface{}{value}
+	default:
+		return result, fmt.Errorf("unknown operator %q", filter.Operator)
+	}
+	for _, value := range result.Values {
+		switch value.(type) {
+		case float64, string, bool, nil:
+		default:
+			return result, fmt.Errorf("%v expects scalar values", filter.Operator)
+		}
+	}
+	return result, nil
+}
+
+// Match checks the filter against the normalized value of a variable
+func (this ValueFilter) Match(value interface{}) bool {
+	element, err := jsondoc.Get(value, this.Pointer)
+	exists := err == nil
+	switch this.Operator {
+	case model.ValueFilterExists:
+		return exists
+	case model.ValueFilterEq, model.ValueFilterIn:
+		return exists && this.equalsAny(element)
+	case model.ValueFilterNe:
+		return !exists || !this.equalsAny(element)
+	}
+	if !exists {
+		return false
+	}
+	cmp, comparable := compareScalars(element, this.Values[0])
+	if !comparable {
+		return false
+	}
+	switch this.Operator {
+	case model.ValueFilterGt:
+		return cmp > 0
+	case model.ValueFilterGte:
+		return cmp >= 0
+	case model.ValueFilterLt:
+		return cmp < 0
+	case model.ValueFilterLte:
+		return cmp <= 0
+	default:
+		return false
+	}
+}
+
+func (this ValueFilter) equalsAny(element interface{}) bool {
+	for _, value := range this.Values {
+		switch element.(type) {
+		case map[string]interface{}, []interface{}:
+			continue
+		}
+		if element == value {
+			return true
+		}
+	}
+	return false
+}
+
+// compareScalars compares numbers with numbers, strings with strings and booleans with booleans;
+// comparable is false for other combinations
+func compareScalars(a interface{}, b interface{}) (result int, comparable bool) {
+	switch a := a.(type) {
+	case float64:
+		b, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(a, b), true
+	case bool:
+		b, ok := b.(bool)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case a == b:
+			return 0, true
+		case b:
+			return -1, true
+		}
+		return 1, true
+	default:
+		return 0, false
+	}
+}
+
+// CompareValueElements orders the elements referenced by the pointer in two normalized values like mongodb sorts them:
+// missing and null < numbers < strings < objects < arrays < booleans; objects and arrays are not ordered among each other
+func CompareValueElements(a interface{}, b interface{}, pointer []string) int {
+	a, _ = jsondoc.Get(a, pointer)
+	b, _ = jsondoc.Get(b, pointer)
+	rankA, rankB := valueTypeRank(a), valueTypeRank(b)
+	if rankA != rankB {
+		return rankA - rankB
+	}
+	result, _ := compareScalars(a, b)
+	return result
+}
+
+// valueTypeRank mirrors the type order used for sorting in mongodb
+func valueTypeRank(value interface{}) int {
+	switch value.(type) {
+	case float64:
+		return 2
+	case string:
+		return 3
+	case map[string]interface{}:
+		return 4
+	case []interface{}:
+		return 5
+	case bool:
+		return 6
+	default:
+		return 1
+	}
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+// VariablesCursor is the position of the last variable of a page in the order of the sort of the listing;
+// the following page starts with the first variable sorted after this position
+type VariablesCursor struct {
+	Sort   string        `json:"s"`           //Sort.Unique() of the listing
+	Values []interface{} `json:"v"`           //the sorted fields of the last variable, see SortKey.Get; one per element of Sort
+	Owner  string        `json:"o,omitempty"` //VariableWithUnixTimestamp.Owner of the last variable; orders shared variables with equal keys of different owners
+}
+
+// Encode returns the cursor as opaque url safe string
+func (this VariablesCursor) Encode() string {
+	temp, _ := json.Marshal(this)
+	return base64.RawURLEncoding.EncodeToString(temp)
+}
+
+// DecodeVariablesCursor decodes the result of VariablesCursor.Encode; numbers are decoded as int64
+func DecodeVariablesCursor(cursor string) (result VariablesCursor, err error) {
+	temp, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return result, errors.Join(ErrInvalidCursor, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(temp))
+	decoder.UseNumber()
+	err = decoder.Decode(&result)
+	if err != nil {
+		return result, errors.Join(ErrInvalidCursor, err)
+	}
+	for i, value := range result.Values {
+		if number, ok := value.(json.Number); ok {
+			result.Values[i], err = number.Int64()
+			if err != nil {
+				return result, errors.Join(ErrInvalidCursor, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// NewVariablesCursor returns the cursor of the position of the variable in the sort;
+// ok is false for sorts by elements of the value, which do not support cursors
+func NewVariablesCursor(sort Sort, variable VariableWithUnixTimestamp) (result VariablesCursor, ok bool) {
+	sort = sort.Unique()
+	result.Sort = sort.String()
+	result.Owner = variable.Owner
+	for _, key := range sort {
+		value, ok := key.Get(variable)
+		if !ok {
+			return result, false
+		}
+		result.Values = append(result.Values, value)
+	}
+	return result, true
+}
+
+// NextVariablesCursor returns the encoded cursor of the page following the list, which is the result of the query;
+// returns "" if the list is the last page, the query has no limit or its sort does not support cursors
+func NextVariablesCursor(query VariablesQueryOptions, list []VariableWithUnixTimestamp) string {
+	if query.Limit <= 0 || len(list) < query.Limit {
+		return ""
+	}
+	sort, err := query.GetSortKeys()
+	if err != nil {
+		return ""
+	}
+	cursor, ok := NewVariablesCursor(sort, list[len(list)-1])
+	if !ok {
+		return ""
+	}
+	return cursor.Encode()
+}
@@ -0,0 +1,292 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsondoc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// Patch is a parsed patch document, ready to be applied to normalized documents
+type Patch = func(doc interface{}) (interface{}, error)
+
+// NewPatch parses a patch of the given type (model.MergePatchContentType or model.JsonPatchContentType)
+func NewPatch(patch model.Patch) (Patch, error) {
+	switch patch.Type {
+	case model.MergePatchContentType:
+		var mergePatch interface{}
+		err := json.Unmarshal(patch.Patch, &mergePatch)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidPatch, err)
+		}
+		return func(doc interface{}) (interface{}, error) {
+			return MergePatch(doc, mergePatch), nil
+		}, nil
+	case model.JsonPatchContentType:
+		operations := []model.PatchOperation{}
+		err := json.Unmarshal(patch.Patch, &operations)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidPatch, err)
+		}
+		return newJsonPatch(operations)
+	default:
+		return nil, fmt.Errorf("%w: %q", model.ErrUnsupportedPatchType, patch.Type)
+	}
+}
+
+// MergePatch applies a JSON Merge Patch (RFC 7396); modifies and returns the normalized target
+func MergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = MergePatch(targetObject[key], value)
+		}
+	}
+	return targetObject
+}
+
+// jsonPatchOperation is a model.PatchOperation with parsed pointers and value
+type jsonPatchOperation struct {
+	op    string
+	path  []string
+	from  []string
+	value interface{}
+}
+
+// newJsonPatch validates the operations of a JSON Patch (RFC 6902) and returns a function applying them in order
+func newJsonPatch(operations []model.PatchOperation) (Patch, error) {
+	parsed := []jsonPatchOperation{}
+	for i, operation := range operations {
+		element := jsonPatchOperation{op: operation.Op}
+		var err error
+		element.path, err = ParsePointer(operation.Path)
+		if err != nil {
+			return nil, errors.Join(model.ErrInvalidPatch, fmt.Errorf("operation %v: %w", i, err))
+		}
+		switch operation.Op {
+		case "add", "replace", "test":
+			if len(operation.Value) == 0 {
+				return nil, fmt.Errorf("%w: operation %v: missing value", model.ErrInvalidPatch, i)
+			}
+			err = json.Unmarshal(operation.Value, &element.value)
+			if err != nil {
+				return nil, errors.Join(model.ErrInvalidPatch, fmt.Errorf("operation %v: %w", i, err))
+			}
+		case "remove":
+			if len(element.path) == 0 {
+				return nil, fmt.Errorf("%w: operation %v: can not remove the whole document", model.ErrInvalidPatch, i)
+			}
+		case "move", "copy":
+			element.from, err = ParsePointer(operation.From)
+			if err != nil {
+				return nil, errors.Join(model.ErrInvalidPatch, fmt.Errorf("operation %v: %w", i, err))
+			}
+			if operation.Op == "move" && len(element.path) > len(element.from) && reflect.DeepEqual(element.path[:len(element.from)], element.from) {
+				return nil, fmt.Errorf("%w: operation %v: can not move a value into one of its children", model.ErrInvalidPatch, i)
+			}
+		default:
+			return nil, fmt.Errorf("%w: operation %v: unknown op %q", model.ErrInvalidPatch, i, operation.Op)
+		}
+		parsed = append(parsed, element)
+	}
+	return func(doc interface{}) (result interface{}, err error) {
+		for i, operation := range parsed {
+			doc, err = operation.apply(doc)
+			if err != nil {
+				return nil, errors.Join(model.ErrPatchFailed, fmt.Errorf("operation %v (%v): %w", i, operation.op, err))
+			}
+		}
+		return doc, nil
+	}, nil
+}
+
+func (this jsonPatchOperation) apply(doc interface{}) (interface{}, error) {
+	switch this.op {
+	case "add":
+		value, err := Normalize(this.value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, this.path, value)
+	case "remove":
+		return remove(doc, this.path)
+	case "replace":
+		value, err := Normalize(this.value)
+		if err != nil {
+			return nil, err
+		}
+		return replace(doc, this.path, value)
+	case "move":
+		value, err := Get(doc, this.from)
+		if err != nil {
+			return nil, err
+		}
+		if reflect.DeepEqual(this.from, this.path) {
+			return doc, nil
+		}
+		doc, err = remove(doc, this.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, this.path, value)
+	case "copy":
+		value, err := Get(doc, this.from)
+		if err != nil {
+			return nil, err
+		}
+		value, err = Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, this.path, value)
+	case "test":
+		value, err := Get(doc, this.path)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := Normalize(this.value)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(value, expected) {
+			return nil, fmt.Errorf("value at %q does not match", FormatPointer(this.path))
+		}
+		return doc, nil
+	default:
+		return nil, fmt.Errorf("unknown op %q", this.op)
+	}
+}
+
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modify(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			if token == "-" {
+				return append(container, value), nil
+			}
+			index, err := arrayIndex(token, len(container))
+			if err != nil {
+				return nil, err
+			}
+			container = append(container, nil)
+			copy(container[index+1:], container[index:])
+			container[index] = value
+			return container, nil
+		default:
+			return nil, pointerNotFound(path)
+		}
+	})
+}
+
+func remove(doc interface{}, path []string) (interface{}, error) {
+	return modify(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, pointerNotFound(path)
+			}
+			delete(container, token)
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, pointerNotFound(path)
+			}
+			return append(container[:index], container[index+1:]...), nil
+		default:
+			return nil, pointerNotFound(path)
+		}
+	})
+}
+
+func replace(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	return modify(doc, path, func(container interface{}, token string) (interface{}, error) {
+		switch container := container.(type) {
+		case map[string]interface{}:
+			if _, ok := container[token]; !ok {
+				return nil, pointerNotFound(path)
+			}
+			container[token] = value
+			return container, nil
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, pointerNotFound(path)
+			}
+			container[index] = value
+			return container, nil
+		default:
+			return nil, pointerNotFound(path)
+		}
+	})
+}
+
+// modify replaces the parent container of the last path token with the result of f;
+// returns the document, which is only a new object if the path has a single token
+func modify(doc interface{}, path []string, f func(container interface{}, token string) (interface{}, error)) (interface{}, error) {
+	if len(path) == 1 {
+		return f(doc, path[0])
+	}
+	var child interface{}
+	var setChild func(value interface{})
+	switch container := doc.(type) {
+	case map[string]interface{}:
+		element, ok := container[path[0]]
+		if !ok {
+			return nil, pointerNotFound(path)
+		}
+		child = element
+		setChild = func(value interface{}) { container[path[0]] = value }
+	case []interface{}:
+		index, err := arrayIndex(path[0], len(container)-1)
+		if err != nil {
+			return nil, pointerNotFound(path)
+		}
+		child = container[index]
+		setChild = func(value interface{}) { container[index] = value }
+	default:
+		return nil, pointerNotFound(path)
+	}
+	child, err := modify(child, path[1:], f)
+	if err != nil {
+		return nil, err
+	}
+	setChild(child)
+	return doc, nil
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package jsondoc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// ParsePointer splits a JSON Pointer (RFC 6901) into its unescaped reference tokens; "" references the whole document
+func ParsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: pointer %q does not start with '/'", model.ErrInvalidPointer, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// Normalize returns a deep copy of the value, using the types of encoding/json
+// (map[string]interface{}, []interface{}, float64, string, bool, nil) independent of the database the value is read from
+func Normalize(value interface{}) (result interface{}, err error) {
+	temp, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(temp, &result)
+	return result, err
+}
+
+// Get returns the element of the normalized document referenced by the tokens
+func Get(doc interface{}, tokens []string) (interface{}, error) {
+	for i, token := range tokens {
+		switch container := doc.(type) {
+		case map[string]interface{}:
+			element, ok := container[token]
+			if !ok {
+				return nil, pointerNotFound(tokens[:i+1])
+			}
+			doc = element
+		case []interface{}:
+			index, err := arrayIndex(token, len(container)-1)
+			if err != nil {
+				return nil, pointerNotFound(tokens[:i+1])
+			}
+			doc = container[index]
+		default:
+			return nil, pointerNotFound(tokens[:i+1])
+		}
+	}
+	return doc, nil
+}
+
+// arrayIndex parses an array index token (digits without leading zeros) and checks it against max
+func arrayIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') || strings.TrimLeft(token, "0123456789") != "" {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	index, err := strconv.Atoi(token)
+	if err != nil {
+		return 0, err
+	}
+	if index > max {
+		return 0, fmt.Errorf("array index %v out of range", index)
+	}
+	return index, nil
+}
+
+// FormatPointer is the inverse of ParsePointer
+func FormatPointer(tokens []string) string {
+	result := ""
+	for _, token := range tokens {
+		result += "/" + strings.ReplaceAll(strings.ReplaceAll(token, "~", "~0"), "/", "~1")
+	}
+	return result
+}
+
+func pointerNotFound(tokens []string) error {
+	return fmt.Errorf("%w: %q", model.ErrPointerNotFound, FormatPointer(tokens))
+}
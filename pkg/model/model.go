@@ -17,38 +17,186 @@
 package model
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
+var ErrPreconditionFailed = errors.New("precondition failed")
+var ErrInvalidPrecondition = errors.New("invalid precondition")
+var ErrNotNumeric = errors.New("stored value is not numeric")
+var ErrUnsupportedPatchType = errors.New("unsupported patch type")
+var ErrInvalidPatch = errors.New("invalid patch")
+var ErrPatchFailed = errors.New("patch can not be applied to the stored value")
+var ErrInvalidPointer = errors.New("invalid json pointer")
+var ErrPointerNotFound = errors.New("json pointer references a missing value")
+var ErrHistoryDisabled = errors.New("variable history is disabled")
+var ErrVersionNotFound = errors.New("unknown variable version")
+var ErrInvalidExpiry = errors.New("invalid expires_at or ttl_in_s")
+var ErrInvalidValueFilter = errors.New("invalid value filter")
+var ErrInvalidImport = errors.New("invalid import")
+var ErrInvalidCursor = errors.New("invalid cursor")
+var ErrInvalidSort = errors.New("invalid sort")
+var ErrInvalidKeyFilter = errors.New("invalid key filter")
+var ErrImportConflict = errors.New("imported variable already exists")
+var ErrInvalidBinaryValue = errors.New("binary values must be base64 encoded strings")
+var ErrMissingDeleteFilter = errors.New("deleting variables without filter requires all=true")
+var ErrTransactionsUnsupported = errors.New("the database does not support transactions")
+var ErrUpdateConflict = errors.New("variable was changed concurrently, retry the request")
+var ErrInvalidId = errors.New("keys and ids must not contain null characters")
+
 type Count struct {
 	Count int64 `json:"count"`
 }
 
+// DeleteResult reports the number of deleted variables; dry runs report the variables that would be deleted
+type DeleteResult struct {
+	Count  int64    `json:"count"`
+	DryRun bool     `json:"dry_run,omitempty"`
+	Keys   []string `json:"keys,omitempty"` //only set for dry runs with DeleteOptions.IncludeKeys and matching variables
+}
+
+type DeleteOptions struct {
+	DryRun      bool //only count the matching variables without deleting them
+	IncludeKeys bool //list the keys of the matching variables; ignored without DryRun
+	All         bool //required to delete all variables with a query without filters, see VariablesQueryOptions.HasFilters
+}
+
+// VariablesListing is a folder like view of variables (see controller.Controller.Browse):
+// variables without delimiter after the prefix and the common prefixes of the other variables
+type VariablesListing struct {
+	Variables      []VariableWithUnixTimestamp `json:"variables"`
+	CommonPrefixes []string                    `json:"common_prefixes"` //prefix up to and including the first delimiter after it, like "plant1/line3/"
+}
+
 type Variable struct {
 	Key                 string      `json:"key"`
 	Value               interface{} `json:"value"`
 	ProcessDefinitionId string      `json:"process_definition_id,omitempty"`
 	ProcessInstanceId   string      `json:"process_instance_id,omitempty"`
+	ExpiresAt           int64       `json:"expires_at,omitempty"`   //unix timestamp in s after which the variable behaves as missing; 0 = never
+	ContentType         string      `json:"content_type,omitempty"` //media type of binary values, which are stored as base64 encoded string; empty for json values
+}
+
+// IsExpired checks if the variable is expired at the given unix timestamp in s
+func (this Variable) IsExpired(now int64) bool {
+	return this.ExpiresAt > 0 && this.ExpiresAt <= now
+}
+
+// NewBinaryVariable returns a variable storing the data base64 encoded with the media type
+func NewBinaryVariable(key string, data []byte, contentType string) Variable {
+	return Variable{Key: key, Value: base64.StdEncoding.EncodeToString(data), ContentType: contentType}
+}
+
+// IsBinary checks if the value of the variable is binary data with a media type (see NewBinaryVariable)
+func (this Variable) IsBinary() bool {
+	return this.ContentType != ""
+}
+
+// GetBinary returns the decoded data of binary values; returns ErrInvalidBinaryValue if the value is not base64 encoded
+func (this Variable) GetBinary() ([]byte, error) {
+	encoded, ok := this.Value.(string)
+	if !ok {
+		return nil, ErrInvalidBinaryValue
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidBinaryValue, err)
+	}
+	return data, nil
+}
+
+// ValidateIds checks the key and the process ids of the variable, see ValidateId
+func (this Variable) ValidateIds() error {
+	for _, id := range []string{this.Key, this.ProcessDefinitionId, this.ProcessInstanceId} {
+		if err := ValidateId(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ValidateId returns ErrInvalidId if the key or id contains a null character, which the bolt database uses to separate key parts
+func ValidateId(id string) error {
+	if strings.ContainsRune(id, 0) {
+		return ErrInvalidId
+	}
+	return nil
+}
+
+// ValidateBinary checks that the value of binary variables is base64 encoded; json values are always valid
+func (this Variable) ValidateBinary() error {
+	if !this.IsBinary() {
+		return nil
+	}
+	_, err := this.GetBinary()
+	return err
+}
+
+// ExpiresAtForTtl returns the expires_at value for a variable written at now, which expires after ttlInS seconds;
+// returns ErrInvalidExpiry for negative ttls
+func ExpiresAtForTtl(now int64, ttlInS int64) (int64, error) {
+	if ttlInS < 0 {
+		return 0, ErrInvalidExpiry
+	}
+	return now + ttlInS, nil
 }
 
 type VariableWithUnixTimestamp struct {
 	Variable
-	UnixTimestampInS int64 `json:"unix_timestamp_in_s"`
+	UnixTimestampInS int64  `json:"unix_timestamp_in_s"`
+	Owner            string `json:"owner,omitempty" bson:"-"` //id of the user who shared the variable; only set for variables of other users, see Share
 }
 
 type VariableWithUser struct {
 	VariableWithUnixTimestamp
-	UserId string `json:"user_id"`
+	UserId   string `json:"user_id"`
+	Revision int64  `json:"revision"` //incremented by the database on every write; 0 if the variable does not exist
 }
 
 type VariablesQueryOptions struct {
-	Limit               int
-	Offset              int
-	Sort                string
-	KeyRegex            string
-	ProcessDefinitionId string
-	ProcessInstanceId   string
+	Limit                 int
+	Offset                int
+	Sort                  string   //comma separated fields with .asc or .desc suffix, see ParseSort; default key.asc
+	Keys                  []string //only variables with one of these keys; empty = unlimited
+	KeyPrefix             string   //only variables with keys starting with this prefix (case-sensitive)
+	KeyGlob               string   //only variables with keys matching this glob pattern (case-sensitive): '*' matches any sequence, '?' a single character and '\' escapes the next character
+	KeyRegex              string   //only variables with keys matching this regular expression (unanchored, syntax shared by all databases, see util.ValidateRegex); case-insensitive unless KeyRegexCaseSensitive
+	KeyRegexCaseSensitive bool
+	ProcessDefinitionId   string
+	ProcessInstanceId     string
+	ValueFilters          []ValueFilter //all filters must match
+	UpdatedAfter          int64         //only variables with unix_timestamp_in_s > UpdatedAfter; 0 = unlimited
+	UpdatedBefore         int64         //only variables with unix_timestamp_in_s < UpdatedBefore; 0 = unlimited
+	Cursor                string        //NextVariablesCursor of the previous page; lists the variables after the previous page, may not be combined with Offset or another Sort
+}
+
+const (
+	ValueFilterEq     = "eq"
+	ValueFilterNe     = "ne"
+	ValueFilterGt     = "gt"
+	ValueFilterGte    = "gte"
+	ValueFilterLt     = "lt"
+	ValueFilterLte    = "lte"
+	ValueFilterExists = "exists"
+	ValueFilterIn     = "in"
+)
+
+// ValueFilter is a predicate on the element of the variable value referenced by Path:
+//   - eq: the element equals Value; arrays do not match by their elements
+//   - ne: the element is missing or does not equal Value
+//   - gt, gte, lt, lte: the element has the same type as Value (number or string) and compares accordingly
+//   - exists: the element exists, it may be null; Value is ignored
+//   - in: the element equals one of the elements of the Value list
+//
+// values (and the elements of the in list) must be strings, numbers, booleans or null
+type ValueFilter struct {
+	Path     string      `json:"path"` //json pointer (RFC 6901) into the value; "" references the whole value
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value,omitempty"`
 }
 
 func (this VariablesQueryOptions) GetLimit() int64 {
@@ -59,11 +207,11 @@ func (this VariablesQueryOptions) GetOffset() int64 {
 	return int64(this.Offset)
 }
 
-func (this VariablesQueryOptions) GetSort() string {
-	if this.Sort == "" {
-		return "key.asc"
-	}
-	return this.Sort
+// HasFilters returns true if the query restricts the variables by key, process, value or timestamp
+func (this VariablesQueryOptions) HasFilters() bool {
+	return len(this.Keys) > 0 || this.KeyPrefix != "" || this.KeyGlob != "" || this.KeyRegex != "" ||
+		this.ProcessDefinitionId != "" || this.ProcessInstanceId != "" || len(this.ValueFilters) > 0 ||
+		this.UpdatedAfter != 0 || this.UpdatedBefore != 0
 }
 
 func (this VariablesQueryOptions) Encode() string {
@@ -77,21 +225,184 @@ func (this VariablesQueryOptions) Encode() string {
 	if this.Sort != "" {
 		values["sort"] = []string{this.Sort}
 	}
+	for _, key := range this.Keys {
+		values["key"] = append(values["key"], key)
+	}
+	if this.KeyPrefix != "" {
+		values["key_prefix"] = []string{this.KeyPrefix}
+	}
+	if this.KeyGlob != "" {
+		values["key_glob"] = []string{this.KeyGlob}
+	}
 	if this.KeyRegex != "" {
 		values["key_regex"] = []string{this.KeyRegex}
 	}
+	if this.KeyRegexCaseSensitive {
+		values["key_regex_case_sensitive"] = []string{"true"}
+	}
 	if this.ProcessInstanceId != "" {
 		values["process_instance_id"] = []string{this.ProcessInstanceId}
 	}
 	if this.ProcessDefinitionId != "" {
 		values["process_definition_id"] = []string{this.ProcessDefinitionId}
 	}
+	for _, filter := range this.ValueFilters {
+		temp, _ := json.Marshal(filter)
+		values["value_filter"] = append(values["value_filter"], string(temp))
+	}
+	if this.UpdatedAfter != 0 {
+		values["updated_after"] = []string{strconv.FormatInt(this.UpdatedAfter, 10)}
+	}
+	if this.UpdatedBefore != 0 {
+		values["updated_before"] = []string{strconv.FormatInt(this.UpdatedBefore, 10)}
+	}
+	if this.Cursor != "" {
+		values["cursor"] = []string{this.Cursor}
+	}
+	return values.Encode()
+}
+
+// VariableVersion is a version of a variable stored in the history
+type VariableVersion struct {
+	VariableWithUnixTimestamp
+	Revision int64  `json:"revision"`
+	Writer   string `json:"writer"` //id of the user who wrote this version
+}
+
+type HistoryQueryOptions struct {
+	Limit  int
+	Offset int
+}
+
+func (this HistoryQueryOptions) Encode() string {
+	values := url.Values{}
+	if this.Limit > 0 {
+		values["limit"] = []string{strconv.Itoa(this.Limit)}
+	}
+	if this.Offset > 0 {
+		values["offset"] = []string{strconv.Itoa(this.Offset)}
+	}
 	return values.Encode()
 }
 
+// HistoryRetention selects the versions removed from the history of a variable; the current version is never removed
+type HistoryRetention struct {
+	MaxVersions         int64 //keep at most this many versions; 0 = unlimited
+	MinUnixTimestampInS int64 //remove versions written before this time; 0 = unlimited
+}
+
+type RestoreRequest struct {
+	Revision int64 `json:"revision"` //revision of the version in the history which is written as new version of the variable
+}
+
+type IncrementRequest struct {
+	Delta float64 `json:"delta"` //may be negative to decrement
+}
+
+const MergePatchContentType = "application/merge-patch+json"
+const JsonPatchContentType = "application/json-patch+json"
+
+type Patch struct {
+	Type  string          //MergePatchContentType (RFC 7396) or JsonPatchContentType (RFC 6902)
+	Patch json.RawMessage //the patch document
+}
+
+// PatchOperation is an element of a JSON Patch (RFC 6902)
+type PatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	From  string          `json:"from,omitempty"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
 type BulkRequest struct {
-	Get []string   `json:"get"`
-	Set []Variable `json:"set"`
+	Get    []string      `json:"get"`
+	Set    []BulkSetItem `json:"set"`
+	Atomic bool          `json:"atomic,omitempty"` //if true, the request is executed in a single database transaction: if any part fails, no variable is written
+}
+
+type BulkSetItem struct {
+	Variable
+	Precondition
+	TtlInS int64 `json:"ttl_in_s,omitempty"` //if set, overwrites expires_at with the time of the write plus ttl_in_s
 }
 
 type BulkResponse = []VariableWithUnixTimestamp
+
+const NdjsonContentType = "application/x-ndjson"
+
+const (
+	ExportFormatNdjson = "ndjson" //one json encoded variable per line
+	ExportFormatJson   = "json"   //json array of variables
+)
+
+// conflict policies of imports; a conflict is an imported variable whose key already exists
+const (
+	ImportConflictOverwrite = "overwrite" //the existing variable is replaced
+	ImportConflictSkip      = "skip"      //the existing variable is kept
+	ImportConflictFail      = "fail"      //the import is rejected with ErrImportConflict, before any variable is written
+)
+
+// ImportSummary lists the keys of the imported variables by result
+type ImportSummary struct {
+	Created []string `json:"created"`
+	Updated []string `json:"updated"`
+	Skipped []string `json:"skipped"`
+}
+
+// Precondition restricts a write to a state of the stored variable, like the http headers If-Match and If-None-Match.
+// both fields contain "*" or a comma separated list of revisions (optionally formatted as etags).
+type Precondition struct {
+	IfMatch     string `json:"if_match,omitempty"`      //the variable must exist and, if not "*", have one of the listed revisions
+	IfNoneMatch string `json:"if_none_match,omitempty"` //the variable must not exist or, if not "*", must not have one of the listed revisions
+}
+
+func (this Precondition) IsEmpty() bool {
+	return this.IfMatch == "" && this.IfNoneMatch == ""
+}
+
+// Check returns true if the precondition allows a write to a variable with the given revision (0 if the variable does not exist)
+func (this Precondition) Check(revision int64) (bool, error) {
+	if this.IfMatch != "" {
+		match, err := matchesRevision(this.IfMatch, revision)
+		if err != nil || !match {
+			return false, err
+		}
+	}
+	if this.IfNoneMatch != "" {
+		match, err := matchesRevision(this.IfNoneMatch, revision)
+		if err != nil || match {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchesRevision(list string, revision int64) (bool, error) {
+	if strings.TrimSpace(list) == "*" {
+		return revision > 0, nil
+	}
+	for _, element := range strings.Split(list, ",") {
+		parsed, err := ETagToRevision(element)
+		if err != nil {
+			return false, errors.Join(ErrInvalidPrecondition, err)
+		}
+		if parsed == revision && revision > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RevisionToETag formats a revision as strong etag
+func RevisionToETag(revision int64) string {
+	return "\"" + strconv.FormatInt(revision, 10) + "\""
+}
+
+// ETagToRevision parses etags created by RevisionToETag; quotes and the weak prefix are optional
+func ETagToRevision(etag string) (int64, error) {
+	etag = strings.TrimSpace(etag)
+	etag = strings.TrimPrefix(etag, "W/")
+	etag = strings.Trim(etag, "\"")
+	return strconv.ParseInt(etag, 10, 64)
+}
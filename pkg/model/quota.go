@@ -0,0 +1,64 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+var ErrValueTooLarge = errors.New("value exceeds max_value_bytes of the quota")
+var ErrInvalidQuota = errors.New("invalid quota")
+
+// Quota limits the variables of a user; 0 means unlimited.
+// the configured limits apply to all users without an admin override
+type Quota struct {
+	UserId        string `json:"user_id,omitempty"` //set by the service; empty for the configured limits
+	MaxVariables  int64  `json:"max_variables"`     //max number of not expired variables
+	MaxValueBytes int64  `json:"max_value_bytes"`   //max json size of a single value
+	MaxTotalBytes int64  `json:"max_total_bytes"`   //max sum of the value sizes of all variables
+}
+
+func (this Quota) Validate() error {
+	if this.MaxVariables < 0 || this.MaxValueBytes < 0 || this.MaxTotalBytes < 0 {
+		return errors.Join(ErrInvalidQuota, errors.New("limits may not be negative"))
+	}
+	return nil
+}
+
+// Usage counts the not expired variables of a user and the sum of their value sizes;
+// the sizes are measured by the database and may slightly differ from the json size of the written values
+type Usage struct {
+	Variables  int64 `json:"variables"`
+	TotalBytes int64 `json:"total_bytes"`
+}
+
+type QuotaStatus struct {
+	Quota    Quota `json:"quota"`
+	Usage    Usage `json:"usage"`
+	Override bool  `json:"override"` //the quota is an admin override of the configured limits
+}
+
+// ValueSize returns the json size of the value, as checked against Quota.MaxValueBytes
+func ValueSize(value interface{}) (int64, error) {
+	buf, err := json.Marshal(value)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(buf)), nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidShare = errors.New("invalid share")
+var ErrShareNotFound = errors.New("share not found")
+
+// Share grants a user or a group read (or read/write) access to the variables of the owner
+// with the given key or with keys starting with the given prefix
+type Share struct {
+	Id        string `json:"id"`                   //set by the service
+	OwnerId   string `json:"owner_id"`             //set by the service
+	Key       string `json:"key,omitempty"`        //shared key; either Key or KeyPrefix must be set
+	KeyPrefix string `json:"key_prefix,omitempty"` //shared key prefix, e.g. "plant1/"
+	UserId    string `json:"user_id,omitempty"`    //grantee; either UserId or Group must be set
+	Group     string `json:"group,omitempty"`      //grantee group, matched against the roles of the jwt
+	Write     bool   `json:"write"`                //grants read/write access; read access only otherwise
+}
+
+// Validate checks that exactly one key selector and exactly one grantee is set and that the owner does not share with itself
+func (this Share) Validate() error {
+	if (this.Key == "") == (this.KeyPrefix == "") {
+		return errors.Join(ErrInvalidShare, errors.New("expect either key or key_prefix"))
+	}
+	if (this.UserId == "") == (this.Group == "") {
+		return errors.Join(ErrInvalidShare, errors.New("expect either user_id or group"))
+	}
+	if this.UserId != "" && this.UserId == this.OwnerId {
+		return errors.Join(ErrInvalidShare, errors.New("variables can not be shared with their owner"))
+	}
+	return nil
+}
+
+// Matches checks if the share grants access to the variable with the given key
+func (this Share) Matches(key string) bool {
+	if this.Key != "" {
+		return this.Key == key
+	}
+	return strings.HasPrefix(key, this.KeyPrefix)
+}
+
+type groupsContextKey struct{}
+
+// WithGroups returns a context carrying the groups of the requesting user, to resolve shares granted to groups
+func WithGroups(ctx context.Context, groups []string) context.Context {
+	return context.WithValue(ctx, groupsContextKey{}, groups)
+}
+
+// GetGroups returns the groups set by WithGroups
+func GetGroups(ctx context.Context) []string {
+	groups, _ := ctx.Value(groupsContextKey{}).([]string)
+	return groups
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortable fields of variables
+const (
+	SortFieldKey                 = "key"
+	SortFieldTimestamp           = "unix_timestamp_in_s"
+	SortFieldProcessDefinitionId = "process_definition_id"
+	SortFieldProcessInstanceId   = "process_instance_id"
+	SortFieldValue               = "value" //element of the value referenced by SortKey.Pointer
+)
+
+// sortFieldAliases maps accepted field names to the sortable fields
+var sortFieldAliases = map[string]string{
+	"key":                   SortFieldKey,
+	"variable_key":          SortFieldKey,
+	"timestamp":             SortFieldTimestamp,
+	"unix_timestamp_in_s":   SortFieldTimestamp,
+	"process_definition_id": SortFieldProcessDefinitionId,
+	"process_instance_id":   SortFieldProcessInstanceId,
+}
+
+// SortKey is an element of a Sort
+type SortKey struct {
+	Field   string //one of the SortField constants
+	Pointer string //json pointer (RFC 6901) into the value, if Field is SortFieldValue; "" references the whole value
+	Desc    bool
+}
+
+func (this SortKey) String() string {
+	if this.Desc {
+		return this.Field + this.Pointer + ".desc"
+	}
+	return this.Field + this.Pointer + ".asc"
+}
+
+// Get returns the sorted field of the variable: a string for keys and process ids, an int64 for timestamps;
+// ok is false for sorts by elements of the value
+func (this SortKey) Get(variable VariableWithUnixTimestamp) (field interface{}, ok bool) {
+	switch this.Field {
+	case SortFieldKey:
+		return variable.Key, true
+	case SortFieldTimestamp:
+		return variable.UnixTimestampInS, true
+	case SortFieldProcessDefinitionId:
+		return variable.ProcessDefinitionId, true
+	case SortFieldProcessInstanceId:
+		return variable.ProcessInstanceId, true
+	default:
+		return nil, false
+	}
+}
+
+// Sort orders variables by the first key, ties by the following keys
+type Sort []SortKey
+
+// ParseSort parses a comma separated list of fields with optional .asc (default) or .desc suffix,
+// e.g. process_instance_id.asc,unix_timestamp_in_s.desc; value<json pointer> sorts by an element of the value, e.g. value/status.desc.
+// the empty string is sorted by key.
+// returns ErrInvalidSort for unknown or repeated fields
+func ParseSort(sort string) (result Sort, err error) {
+	if strings.TrimSpace(sort) == "" {
+		return Sort{{Field: SortFieldKey}}, nil
+	}
+	used := map[string]bool{}
+	for _, element := range strings.Split(sort, ",") {
+		key := SortKey{}
+		name := strings.TrimSpace(element)
+		if strings.HasSuffix(name, ".desc") {
+			key.Desc = true
+			name = strings.TrimSuffix(name, ".desc")
+		} else {
+			name = strings.TrimSuffix(name, ".asc")
+		}
+		switch {
+		case name == SortFieldValue || strings.HasPrefix(name, SortFieldValue+"/"):
+			key.Field = SortFieldValue
+			key.Pointer = strings.TrimPrefix(name, SortFieldValue)
+		case sortFieldAliases[name] != "":
+			key.Field = sortFieldAliases[name]
+		default:
+			return nil, fmt.Errorf("%w: unknown sort field %q", ErrInvalidSort, name)
+		}
+		if used[key.Field+key.Pointer] {
+			return nil, fmt.Errorf("%w: repeated sort field %q", ErrInvalidSort, name)
+		}
+		used[key.Field+key.Pointer] = true
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+func (this Sort) String() string {
+	parts := []string{}
+	for _, key := range this {
+		parts = append(parts, key.String())
+	}
+	return strings.Join(parts, ",")
+}
+
+// Unique returns the sort with the key as last sort key, so that every variable of a user has a unique position;
+// the key is added in the direction of the last sort key, keys following the key are removed
+func (this Sort) Unique() (result Sort) {
+	for _, key := range this {
+		result = append(result, key)
+		if key.Field == SortFieldKey {
+			return result
+		}
+	}
+	desc := len(result) > 0 && result[len(result)-1].Desc
+	return append(result, SortKey{Field: SortFieldKey, Desc: desc})
+}
+
+// GetSortKeys parses Sort with ParseSort
+func (this VariablesQueryOptions) GetSortKeys() (Sort, error) {
+	return ParseSort(this.Sort)
+}
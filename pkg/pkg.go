@@ -21,15 +21,22 @@ import (
 	"github.com/SENERGY-Platform/process-io-api/pkg/api"
 	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
 	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller/metrics"
 	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/cache"
 	"sync"
 )
 
 func Start(ctx context.Context, wg *sync.WaitGroup, config configuration.Config) (cmd *controller.Controller, err error) {
-	db, err := database.New(ctx, wg, config)
+	var db controller.Database
+	db, err = database.New(ctx, wg, config)
 	if err != nil {
 		return nil, err
 	}
-	cmd = controller.New(config, db)
+	m := metrics.New()
+	if config.CacheSize > 0 {
+		db = cache.New(config, db, m)
+	}
+	cmd = controller.NewWithMetrics(config, db, m)
 	return cmd, api.Start(ctx, config, cmd)
 }
@@ -63,6 +63,38 @@ func TestApiPostgres(t *testing.T) {
 	runApiTests(t, config)
 }
 
+func TestApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	runApiTests(t, config)
+}
+
+func TestApiBolt(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "bolt")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	runApiTests(t, config)
+}
+
 func runApiTests(t *testing.T, config configuration.Config) {
 	now := time.Now()
 	backup := configuration.TimeNow
@@ -359,6 +391,8 @@ func runApiTests(t *testing.T, config configuration.Config) {
 
 	t.Run("delete d-unknown ", testRequestWithToken(config, admintoken, "DELETE", "/process-definitions/d-unknown", nil, http.StatusNoContent, nil))
 	t.Run("delete i-unknown ", testRequestWithToken(config, admintoken, "DELETE", "/process-instances/i-unknown", nil, http.StatusNoContent, nil))
+	t.Run("delete d-unknown with count", testRequestWithToken(config, admintoken, "DELETE", "/process-definitions/d-unknown?count=true", nil, http.StatusOK, model.DeleteResult{Count: 0}))
+	t.Run("delete i-unknown with count", testRequestWithToken(config, admintoken, "DELETE", "/process-instances/i-unknown?count=true", nil, http.StatusOK, model.DeleteResult{Count: 0}))
 
 	t.Run("get variables", testRequest(config, "GET", "/variables", nil, http.StatusOK, []model.VariableWithUnixTimestamp{
 		{
@@ -138,16 +138,16 @@ func bulkBenchmark(config configuration.Config, count int) func(b *testing.B) {
 
 		bulkRequest := model.BulkRequest{
 			Get: []string{},
-			Set: []model.Variable{},
+			Set: []model.BulkSetItem{},
 		}
 		for j := 0; j < count*b.N; j++ {
 			bulkRequest.Get = append(bulkRequest.Get, "v"+strconv.Itoa(j))
-			bulkRequest.Set = append(bulkRequest.Set, model.Variable{
+			bulkRequest.Set = append(bulkRequest.Set, model.BulkSetItem{Variable: model.Variable{
 				Key:                 "v" + strconv.Itoa(j),
 				Value:               j,
 				ProcessDefinitionId: strconv.Itoa(j),
 				ProcessInstanceId:   strconv.Itoa(j),
-			})
+			}})
 		}
 		temp := new(bytes.Buffer)
 		err := json.NewEncoder(temp).Encode(bulkRequest)
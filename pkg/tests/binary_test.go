@@ -0,0 +1,178 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestBinary(t *testing.T) {
+	testWithBackends(t, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+	}, runBinaryTests)
+}
+
+func TestBinaryApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnvWithConfig(ctx, wg, "memory", func(config *configuration.Config) {})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	data := []byte{0x89, 'P', 'N', 'G', 0, 1, 2, 255}
+	t.Run("set binary value", testBinaryRequest(config, "PUT", "/values/images/logo", "image/png", data, http.StatusNoContent, "", nil))
+	t.Run("get binary value", testBinaryRequest(config, "GET", "/values/images/logo", "", nil, http.StatusOK, "image/png", data))
+	t.Run("get binary variable", func(t *testing.T) {
+		req, err := http.NewRequest("GET", "http://localhost:"+config.ServerPort+"/variables/images/logo", nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header.Set("Authorization", testtoken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		variable := model.VariableWithUnixTimestamp{}
+		err = json.NewDecoder(resp.Body).Decode(&variable)
+		if err != nil || variable.Variable != model.NewBinaryVariable("images/logo", data, "image/png") {
+			t.Error(err, variable)
+		}
+	})
+	t.Run("patch binary value", testBinaryRequest(config, "PATCH", "/values/images/logo", "application/merge-patch+json", []byte(`{"a":1}`), http.StatusConflict, "", nil))
+	t.Run("set json value", testBinaryRequest(config, "PUT", "/values/images/logo", "application/json; charset=utf-8", []byte(`{"a":1}`), http.StatusNoContent, "", nil))
+	t.Run("get json value", testBinaryRequest(config, "GET", "/values/images/logo", "", nil, http.StatusOK, "application/json", []byte("{\"a\":1}\n")))
+	t.Run("set json value like curl -d", testBinaryRequest(config, "PUT", "/values/counter", "application/x-www-form-urlencoded", []byte(`42`), http.StatusNoContent, "", nil))
+	t.Run("get json value like curl -d", testRequest(config, "GET", "/values/counter", nil, http.StatusOK, 42))
+	t.Run("set json text value", testBinaryRequest(config, "PUT", "/values/text", "text/plain; charset=utf-8", []byte(`"foo"`), http.StatusNoContent, "", nil))
+	t.Run("get json text value", testRequest(config, "GET", "/values/text", nil, http.StatusOK, "foo"))
+	t.Run("set plain text value", testBinaryRequest(config, "PUT", "/values/text", "text/plain", []byte(`foo`), http.StatusNoContent, "", nil))
+	t.Run("get plain text value", testBinaryRequest(config, "GET", "/values/text", "", nil, http.StatusOK, "text/plain", []byte(`foo`)))
+	t.Run("invalid binary variable", testRequest(config, "PUT", "/variables/csv", model.Variable{Key: "csv", Value: 1, ContentType: "text/csv"}, http.StatusBadRequest, nil))
+}
+
+// testBinaryRequest sends the body with the content type and compares the raw response body and content type
+func testBinaryRequest(config configuration.Config, method string, path string, contentType string, body []byte, expectedStatusCode int, expectedContentType string, expected []byte) func(t *testing.T) {
+	return func(t *testing.T) {
+		req, err := http.NewRequest(method, "http://localhost:"+config.ServerPort+path, bytes.NewReader(body))
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header.Set("Authorization", testtoken)
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		temp, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != expectedStatusCode {
+			t.Error(resp.StatusCode, string(temp), method, path)
+			return
+		}
+		if expectedContentType != "" && resp.Header.Get("Content-Type") != expectedContentType {
+			t.Error(resp.Header.Get("Content-Type"))
+		}
+		if expected != nil && !bytes.Equal(temp, expected) {
+			t.Error(temp, expected)
+		}
+	}
+}
+
+func runBinaryTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+	data := []byte("a,b\n1,2\n")
+
+	err := ctrl.Set(ctx, userid, model.NewBinaryVariable("export.csv", data, "text/csv"))
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("get", func(t *testing.T) {
+		variable, err := ctrl.Get(ctx, userid, "export.csv")
+		if err != nil || variable.ContentType != "text/csv" {
+			t.Error(err, variable)
+			return
+		}
+		result, err := variable.GetBinary()
+		if err != nil || !bytes.Equal(result, data) {
+			t.Error(err, string(result))
+		}
+	})
+
+	t.Run("list", func(t *testing.T) {
+		list, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Keys: []string{"export.csv"}})
+		if err != nil || len(list) != 1 || list[0].ContentType != "text/csv" {
+			t.Error(err, list)
+		}
+	})
+
+	t.Run("invalid binary value", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "invalid", Value: "not base64!", ContentType: "text/csv"})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		_, _, err := ctrl.Patch(ctx, userid, "export.csv", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"a":1}`)}, model.Precondition{})
+		if !errors.Is(err, model.ErrPatchFailed) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("history", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "export.csv", Value: 1.0})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, userid, "export.csv")
+		if err != nil || variable.ContentType != "" || variable.Value != 1.0 {
+			t.Error(err, variable)
+		}
+		versions, err := ctrl.History(ctx, userid, "export.csv", model.HistoryQueryOptions{})
+		if err != nil || len(versions) != 2 || versions[1].ContentType != "text/csv" {
+			t.Error(err, versions)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestBulkTransactionMongo(t *testing.T) {
+	testBulkTransaction(t, "mongodb")
+}
+
+func TestBulkTransactionPostgres(t *testing.T) {
+	testBulkTransaction(t, "postgres")
+}
+
+func TestBulkTransactionMemory(t *testing.T) {
+	testBulkTransaction(t, "memory")
+}
+
+func TestBulkTransactionBolt(t *testing.T) {
+	testBulkTransaction(t, "bolt")
+}
+
+func testBulkTransaction(t *testing.T, dbSelection string) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, ctrl, err := StartTestEnv(ctx, wg, dbSelection)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	runBulkTransactionTests(t, ctrl)
+}
+
+func runBulkTransactionTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	getValue := func(key string) interface{} {
+		variable, err := ctrl.Get(ctx, userid, key)
+		if err != nil {
+			t.Error(err)
+			return nil
+		}
+		return variable.Value
+	}
+
+	t.Run("failing atomic bulk writes nothing", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set:    []model.BulkSetItem{{Variable: model.Variable{Key: "a1", Value: "foo"}}, {Variable: model.Variable{Key: "a2", Value: "bar"}}},
+			Get:    []string{"a1", "calculate_unknown"},
+			Atomic: true,
+		})
+		if err == nil {
+			t.Error("expected error")
+			return
+		}
+		if value := getValue("a1"); value != nil {
+			t.Error(value)
+		}
+		if value := getValue("a2"); value != nil {
+			t.Error(value)
+		}
+	})
+
+	t.Run("failing non atomic bulk keeps previous writes", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set: []model.BulkSetItem{{Variable: model.Variable{Key: "b1", Value: "foo"}}},
+			Get: []string{"calculate_unknown"},
+		})
+		if err == nil {
+			t.Error("expected error")
+			return
+		}
+		if value := getValue("b1"); value != "foo" {
+			t.Error(value)
+		}
+	})
+
+	t.Run("atomic bulk reads its own writes", func(t *testing.T) {
+		result, err := ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set:    []model.BulkSetItem{{Variable: model.Variable{Key: "c1", Value: "foo"}}, {Variable: model.Variable{Key: "b1", Value: "bar"}}},
+			Get:    []string{"c1", "b1"},
+			Atomic: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		values := []interface{}{}
+		for _, variable := range result {
+			values = append(values, variable.Value)
+		}
+		if !reflect.DeepEqual(values, []interface{}{"foo", "bar"}) {
+			t.Error(values)
+		}
+	})
+
+	t.Run("failing atomic bulk restores overwritten values", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set:    []model.BulkSetItem{{Variable: model.Variable{Key: "c1", Value: "changed"}}},
+			Get:    []string{"calculate_unknown"},
+			Atomic: true,
+		})
+		if err == nil {
+			t.Error("expected error")
+			return
+		}
+		if value := getValue("c1"); value != "foo" {
+			t.Error(value)
+		}
+	})
+}
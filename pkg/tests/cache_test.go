@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestCache(t *testing.T) {
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			testCache(t, backend)
+		})
+	}
+}
+
+// testCache runs the cache specific tests and some of the general tests with an enabled cache
+func testCache(t *testing.T, backend testBackend) {
+	t.Run("cache", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runCacheTests(t, env.ctrl)
+			hits, misses := getCacheMetrics(t, env.local)
+			if hits == 0 || misses == 0 {
+				t.Error(hits, misses)
+			}
+		})
+	})
+	t.Run("client", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runClientTests(t, env.ctrl)
+		})
+	})
+	t.Run("revision", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runRevisionTests(t, env.ctrl)
+		})
+	})
+	t.Run("increment", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runIncrementTests(t, env.ctrl)
+		})
+	})
+	t.Run("patch", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runPatchTests(t, env.ctrl)
+		})
+	})
+	t.Run("delete", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runDeleteTests(t, env.ctrl)
+		})
+	})
+	t.Run("ttl", func(t *testing.T) {
+		now := atomic.Int64{}
+		now.Store(1000)
+		backup := configuration.TimeNow
+		defer func() { configuration.TimeNow = backup }()
+		configuration.TimeNow = func() time.Time {
+			return time.Unix(now.Load(), 0)
+		}
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runTtlTests(t, env.ctrl, &now)
+		})
+	})
+	t.Run("share", func(t *testing.T) {
+		testWithCache(t, backend, func(t *testing.T, env testEnv) {
+			runShareTests(t, env.ctrl)
+		})
+	})
+	t.Run("bulk transaction", func(t *testing.T) {
+		testWithCache(t, testBackend{name: backend.name, db: backend.db}, func(t *testing.T, env testEnv) {
+			runBulkTransactionTests(t, env.ctrl)
+		})
+	})
+}
+
+func testWithCache(t *testing.T, backend testBackend, run func(t *testing.T, env testEnv)) {
+	testWithBackend(t, backend, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+		config.CacheSize = 10
+		config.CacheTtl = "1h"
+	}, run)
+}
+
+var cacheMetricPattern = regexp.MustCompile(`(?m)^process_io_api_cache_(hits|misses) (\d+)$`)
+
+func getCacheMetrics(t *testing.T, ctrl *controller.Controller) (hits int, misses int) {
+	recorder := httptest.NewRecorder()
+	ctrl.GetMetrics().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, match := range cacheMetricPattern.FindAllStringSubmatch(recorder.Body.String(), -1) {
+		value, err := strconv.Atoi(match[2])
+		if err != nil {
+			t.Error(err)
+		}
+		if match[1] == "hits" {
+			hits = value
+		} else {
+			misses = value
+		}
+	}
+	return hits, misses
+}
+
+func runCacheTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+	adminid := adminTokenUser
+
+	set := func(variable model.Variable) func(t *testing.T) {
+		return func(t *testing.T) {
+			err := ctrl.Set(ctx, userid, variable)
+			if err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	check := func(key string, expected interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			//the second read is served by the cache
+			for i := 0; i < 2; i++ {
+				actual, err := ctrl.Get(ctx, userid, key)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if fmt.Sprint(actual.Value) != fmt.Sprint(expected) {
+					t.Error(i, key, actual.Value, expected)
+				}
+			}
+		}
+	}
+
+	t.Run("set v1", set(model.Variable{Key: "v1", Value: 1}))
+	t.Run("check v1", check("v1", 1))
+	t.Run("update v1", set(model.Variable{Key: "v1", Value: 2}))
+	t.Run("check updated v1", check("v1", 2))
+	t.Run("increment v1", func(t *testing.T) {
+		_, _, err := ctrl.Increment(ctx, userid, "v1", 1)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check incremented v1", check("v1", 3))
+	t.Run("patch v1", func(t *testing.T) {
+		_, _, err := ctrl.Patch(ctx, userid, "v1", model.Patch{Type: model.MergePatchContentType, Patch: json.RawMessage(`{"a":1}`)}, model.Precondition{})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check patched v1", check("v1", map[string]interface{}{"a": 1}))
+	t.Run("delete v1", func(t *testing.T) {
+		err := ctrl.Delete(ctx, userid, "v1")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check deleted v1", check("v1", nil))
+
+	t.Run("set v2", set(model.Variable{Key: "v2", Value: "a", ProcessInstanceId: "i1"}))
+	t.Run("check v2", check("v2", "a"))
+	t.Run("delete i1", func(t *testing.T) {
+		_, err := ctrl.DeleteProcessInstance(ctx, adminid, "i1")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check deleted v2", check("v2", nil))
+
+	t.Run("set v3", set(model.Variable{Key: "v3", Value: "b", ProcessDefinitionId: "d1"}))
+	t.Run("check v3", check("v3", "b"))
+	t.Run("delete d1", func(t *testing.T) {
+		_, err := ctrl.DeleteProcessDefinition(ctx, adminid, "d1")
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check deleted v3", check("v3", nil))
+
+	t.Run("set v4", set(model.Variable{Key: "v4", Value: "c"}))
+	t.Run("check v4", check("v4", "c"))
+	t.Run("delete by filter", func(t *testing.T) {
+		_, err := ctrl.DeleteVariables(ctx, userid, model.VariablesQueryOptions{KeyRegex: "^v4$"}, model.DeleteOptions{})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check deleted v4", check("v4", nil))
+
+	t.Run("set v5", set(model.Variable{Key: "v5", Value: "d"}))
+	t.Run("check v5", check("v5", "d"))
+	t.Run("failed bulk", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{Atomic: true, Set: []model.BulkSetItem{
+			{Variable: model.Variable{Key: "v5", Value: "e"}},
+			{Variable: model.Variable{Key: "v6", Value: "f"}, Precondition: model.Precondition{IfMatch: model.RevisionToETag(42)}},
+		}})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("check v5 after failed bulk", check("v5", "d"))
+	t.Run("bulk", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{Atomic: true, Set: []model.BulkSetItem{{Variable: model.Variable{Key: "v5", Value: "e"}}}})
+		if err != nil {
+			t.Error(err)
+		}
+	})
+	t.Run("check v5 after bulk", check("v5", "e"))
+
+	t.Run("eviction", func(t *testing.T) {
+		for i := 0; i < 25; i++ {
+			set(model.Variable{Key: fmt.Sprintf("e%v", i), Value: i})(t)
+		}
+		for i := 0; i < 25; i++ {
+			check(fmt.Sprintf("e%v", i), i)(t)
+		}
+		for i := 0; i < 25; i++ {
+			check(fmt.Sprintf("e%v", i), i)(t)
+		}
+	})
+}
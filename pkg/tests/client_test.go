@@ -68,6 +68,24 @@ func TestClientController(t *testing.T) {
 	runClientTests(t, ctrl)
 }
 
+func TestClientApiMemory(t *testing.T) {
+	testWithBackend(t, testBackend{name: "ClientMemory", db: "memory", useClient: true}, nil, func(t *testing.T, env testEnv) {
+		runClientTests(t, env.ctrl)
+	})
+}
+
+func TestClientControllerMemory(t *testing.T) {
+	testWithBackend(t, testBackend{name: "ControllerMemory", db: "memory"}, nil, func(t *testing.T, env testEnv) {
+		runClientTests(t, env.ctrl)
+	})
+}
+
+func TestClientControllerBolt(t *testing.T) {
+	testWithBackend(t, testBackend{name: "ControllerBolt", db: "bolt"}, nil, func(t *testing.T, env testEnv) {
+		runClientTests(t, env.ctrl)
+	})
+}
+
 func runClientTests(t *testing.T, client api.Controller) {
 	now := time.Now()
 	backup := configuration.TimeNow
@@ -76,17 +94,18 @@ func runClientTests(t *testing.T, client api.Controller) {
 		return now
 	}
 
+	ctx := context.Background()
 	userid := testTokenUser
 	adminid := adminTokenUser
 
 	t.Run("create value v1", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v1", Value: float64(13)})
+		err := client.Set(ctx, userid, model.Variable{Key: "v1", Value: float64(13)})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 	t.Run("get value v1", func(t *testing.T) {
-		actual, err := client.Get(userid, "v1")
+		actual, err := client.Get(ctx, userid, "v1")
 		if err != nil {
 			t.Error(err)
 			return
@@ -101,7 +120,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 		}
 	})
 	t.Run("get value unknown", func(t *testing.T) {
-		actual, err := client.Get(userid, "unknown")
+		actual, err := client.Get(ctx, userid, "unknown")
 		if err != nil {
 			t.Error(err)
 			return
@@ -116,13 +135,13 @@ func runClientTests(t *testing.T, client api.Controller) {
 		}
 	})
 	t.Run("update value v1", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v1", Value: float64(42)})
+		err := client.Set(ctx, userid, model.Variable{Key: "v1", Value: float64(42)})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 	t.Run("get updated value v1", func(t *testing.T) {
-		actual, err := client.Get(userid, "v1")
+		actual, err := client.Get(ctx, userid, "v1")
 		if err != nil {
 			t.Error(err)
 			return
@@ -137,7 +156,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -154,42 +173,42 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("create value d1 i1 v2", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v2", Value: "a", ProcessDefinitionId: "d1", ProcessInstanceId: "i1"})
+		err := client.Set(ctx, userid, model.Variable{Key: "v2", Value: "a", ProcessDefinitionId: "d1", ProcessInstanceId: "i1"})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value d2 i2 v3", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v3", Value: "b", ProcessDefinitionId: "d2", ProcessInstanceId: "i2"})
+		err := client.Set(ctx, userid, model.Variable{Key: "v3", Value: "b", ProcessDefinitionId: "d2", ProcessInstanceId: "i2"})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value d3 i3 v4", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v4", Value: "c", ProcessDefinitionId: "d3", ProcessInstanceId: "i3"})
+		err := client.Set(ctx, userid, model.Variable{Key: "v4", Value: "c", ProcessDefinitionId: "d3", ProcessInstanceId: "i3"})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value d4 v5", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v5", Value: "d", ProcessDefinitionId: "d4"})
+		err := client.Set(ctx, userid, model.Variable{Key: "v5", Value: "d", ProcessDefinitionId: "d4"})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value d5 v6", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "v6", Value: "e", ProcessDefinitionId: "d5"})
+		err := client.Set(ctx, userid, model.Variable{Key: "v6", Value: "e", ProcessDefinitionId: "d5"})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -256,14 +275,14 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete unknown", func(t *testing.T) {
-		err := client.Delete(userid, "unknown")
+		err := client.Delete(ctx, userid, "unknown")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -330,14 +349,14 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete v1", func(t *testing.T) {
-		err := client.Delete(userid, "v1")
+		err := client.Delete(ctx, userid, "v1")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -395,14 +414,14 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete d1", func(t *testing.T) {
-		err := client.DeleteProcessDefinition(adminid, "d1")
+		_, err := client.DeleteProcessDefinition(ctx, adminid, "d1")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -451,14 +470,14 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete i2", func(t *testing.T) {
-		err := client.DeleteProcessInstance(adminid, "i2")
+		_, err := client.DeleteProcessInstance(ctx, adminid, "i2")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -498,14 +517,14 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete d4", func(t *testing.T) {
-		err := client.DeleteProcessDefinition(adminid, "d4")
+		_, err := client.DeleteProcessDefinition(ctx, adminid, "d4")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -536,20 +555,20 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("delete d-unknown", func(t *testing.T) {
-		err := client.DeleteProcessDefinition(adminid, "d-unknown")
+		_, err := client.DeleteProcessDefinition(ctx, adminid, "d-unknown")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 	t.Run("delete i-unknown", func(t *testing.T) {
-		err := client.DeleteProcessInstance(adminid, "i-unknown")
+		_, err := client.DeleteProcessInstance(ctx, adminid, "i-unknown")
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("get variables", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{})
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -580,7 +599,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("list variables instance i3", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{
 			ProcessInstanceId: "i3",
 		})
 		if err != nil {
@@ -604,7 +623,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("list variables instance d5", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{
 			ProcessDefinitionId: "d5",
 		})
 		if err != nil {
@@ -628,7 +647,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count variables", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{})
 		if err != nil {
 			t.Error(err)
 			return
@@ -640,7 +659,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count variables instance i3", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{ProcessInstanceId: "i3"})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{ProcessInstanceId: "i3"})
 		if err != nil {
 			t.Error(err)
 			return
@@ -652,7 +671,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count variables definition d5", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{ProcessDefinitionId: "d5"})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{ProcessDefinitionId: "d5"})
 		if err != nil {
 			t.Error(err)
 			return
@@ -664,28 +683,28 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("create value foo", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "foo", Value: float64(13)})
+		err := client.Set(ctx, userid, model.Variable{Key: "foo", Value: float64(13)})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value bar", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "bar", Value: float64(13)})
+		err := client.Set(ctx, userid, model.Variable{Key: "bar", Value: float64(13)})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("create value foobar", func(t *testing.T) {
-		err := client.Set(userid, model.Variable{Key: "foobar", Value: float64(13)})
+		err := client.Set(ctx, userid, model.Variable{Key: "foobar", Value: float64(13)})
 		if err != nil {
 			t.Error(err)
 		}
 	})
 
 	t.Run("search foo", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{
 			KeyRegex: "foo",
 		})
 		if err != nil {
@@ -718,7 +737,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count foo variables", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{KeyRegex: "foo"})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{KeyRegex: "foo"})
 		if err != nil {
 			t.Error(err)
 			return
@@ -730,7 +749,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("search bar", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{
 			KeyRegex: "bar",
 		})
 		if err != nil {
@@ -763,7 +782,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count bar variables", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{KeyRegex: "bar"})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{KeyRegex: "bar"})
 		if err != nil {
 			t.Error(err)
 			return
@@ -775,7 +794,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("search b.r", func(t *testing.T) {
-		actual, err := client.List(userid, model.VariablesQueryOptions{
+		actual, err := client.List(ctx, userid, model.VariablesQueryOptions{
 			KeyRegex: "b.r",
 		})
 		if err != nil {
@@ -808,7 +827,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("count b.r variables", func(t *testing.T) {
-		actual, err := client.Count(userid, model.VariablesQueryOptions{KeyRegex: "b.r"})
+		actual, err := client.Count(ctx, userid, model.VariablesQueryOptions{KeyRegex: "b.r"})
 		if err != nil {
 			t.Error(err)
 			return
@@ -820,7 +839,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("get calculated utcOffset Asia/Hong_Kong", func(t *testing.T) {
-		actual, err := client.Get(userid, "calculate_UtcOffset_Asia/Hong_Kong")
+		actual, err := client.Get(ctx, userid, "calculate_UtcOffset_Asia/Hong_Kong")
 		if err != nil {
 			t.Error(err)
 			return
@@ -840,7 +859,7 @@ func runClientTests(t *testing.T, client api.Controller) {
 	})
 
 	t.Run("get calculated utcOffset US/Hawaii", func(t *testing.T) {
-		actual, err := client.Get(userid, "calculate_UtcOffset_US/Hawaii")
+		actual, err := client.Get(ctx, userid, "calculate_UtcOffset_US/Hawaii")
 		if err != nil {
 			t.Error(err)
 			return
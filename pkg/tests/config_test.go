@@ -0,0 +1,82 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+)
+
+func TestConfigDurations(t *testing.T) {
+	load := func(content string) (configuration.Config, error) {
+		location := filepath.Join(t.TempDir(), "config.json")
+		err := os.WriteFile(location, []byte(content), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return configuration.Load(location)
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		config, err := load(`{}`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if config.GetDatabaseTimeout() != 10*time.Second || config.GetReadinessTimeout() != time.Second || config.GetHistoryMaxAge() != 0 || config.GetExpirySweepInterval() != time.Minute || config.GetCacheTtl() != 10*time.Second {
+			t.Error(config.GetDatabaseTimeout(), config.GetReadinessTimeout(), config.GetHistoryMaxAge(), config.GetExpirySweepInterval(), config.GetCacheTtl())
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		config, err := load(`{"database_timeout": "2s", "history_max_age": "0s", "cache_ttl": "1m"}`)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if config.GetDatabaseTimeout() != 2*time.Second || config.GetHistoryMaxAge() != 0 || config.GetCacheTtl() != time.Minute {
+			t.Error(config.GetDatabaseTimeout(), config.GetHistoryMaxAge(), config.GetCacheTtl())
+		}
+	})
+
+	for name, content := range map[string]string{
+		"missing unit":      `{"database_timeout": "10"}`,
+		"negative":          `{"history_max_age": "-1h"}`,
+		"zero":              `{"readiness_timeout": "0s"}`,
+		"invalid interval":  `{"expiry_sweep_interval": "often"}`,
+		"invalid cache ttl": `{"cache_ttl": "1x"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := load(content)
+			if err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	t.Run("environment", func(t *testing.T) {
+		t.Setenv("DATABASE_TIMEOUT", "10")
+		_, err := load(`{}`)
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
@@ -0,0 +1,248 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestCursor(t *testing.T) {
+	testWithBackends(t, nil, runCursorTests)
+}
+
+func TestCursorApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, ctrl, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		err = ctrl.Set(ctx, testTokenUser, model.Variable{Key: key, Value: key})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	get := func(t *testing.T, path string) *http.Response {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:"+config.ServerPort+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Authorization", testtoken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp
+	}
+
+	t.Run("next page headers", func(t *testing.T) {
+		resp := get(t, "/variables?limit=2&offset=0&sort=key.asc")
+		cursor := resp.Header.Get("X-Next-Cursor")
+		if cursor == "" {
+			t.Error("missing cursor")
+			return
+		}
+		link := resp.Header.Get("Link")
+		if !strings.HasPrefix(link, "</variables?") || !strings.Contains(link, "cursor="+cursor) || strings.Contains(link, "offset") || !strings.HasSuffix(link, `>; rel="next"`) {
+			t.Error(link)
+		}
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		resp := get(t, "/variables?limit=2&cursor="+model.VariablesCursor{Sort: "key.asc", Values: []interface{}{"b"}}.Encode())
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Next-Cursor") != "" || resp.Header.Get("Link") != "" {
+			t.Error(resp.StatusCode, resp.Header)
+		}
+	})
+
+	t.Run("cursor page", testRequest(config, "GET", "/variables?limit=2&cursor="+model.VariablesCursor{Sort: "key.asc", Values: []interface{}{"a"}}.Encode(), nil, http.StatusOK, []model.VariableWithUnixTimestamp{
+		{Variable: model.Variable{Key: "b", Value: "b"}, UnixTimestampInS: mustGetTimestamp(ctrl, "b")},
+		{Variable: model.Variable{Key: "c", Value: "c"}, UnixTimestampInS: mustGetTimestamp(ctrl, "c")},
+	}))
+
+	t.Run("invalid cursor", testRequest(config, "GET", "/variables?limit=2&cursor=foo", nil, http.StatusBadRequest, nil))
+	t.Run("cursor of other sort", testRequest(config, "GET", "/variables?limit=2&sort=key.desc&cursor="+model.VariablesCursor{Sort: "key.asc", Values: []interface{}{"a"}}.Encode(), nil, http.StatusBadRequest, nil))
+	t.Run("cursor with offset", testRequest(config, "GET", "/variables?limit=2&offset=1&cursor="+model.VariablesCursor{Sort: "key.asc", Values: []interface{}{"a"}}.Encode(), nil, http.StatusBadRequest, nil))
+}
+
+func mustGetTimestamp(ctrl api.Controller, key string) int64 {
+	variable, err := ctrl.Get(context.Background(), testTokenUser, key)
+	if err != nil {
+		panic(err)
+	}
+	return variable.UnixTimestampInS
+}
+
+func runCursorTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	for i := 0; i < 23; i++ {
+		//equal timestamps and process definition ids are ordered by key
+		configuration.TimeNow = func() time.Time {
+			return time.Unix(int64(1000+i/3), 0)
+		}
+		err := ctrl.Set(ctx, userid, model.Variable{
+			Key:                 fmt.Sprintf("key%02d", (i*7)%23),
+			Value:               map[string]interface{}{"n": float64(i)},
+			ProcessDefinitionId: fmt.Sprintf("d%v", i%4),
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	configuration.TimeNow = backup
+
+	paginate := func(t *testing.T, query model.VariablesQueryOptions, insert func(page int)) (result []model.VariableWithUnixTimestamp) {
+		t.Helper()
+		for page := 0; page < 100; page++ {
+			list, err := ctrl.List(ctx, userid, query)
+			if err != nil {
+				t.Error(err)
+				return result
+			}
+			if len(list) > query.Limit {
+				t.Error(len(list), query.Limit)
+			}
+			result = append(result, list...)
+			query.Cursor = model.NextVariablesCursor(query, list)
+			if query.Cursor == "" {
+				return result
+			}
+			if insert != nil {
+				insert(page)
+			}
+		}
+		t.Error("too many pages")
+		return result
+	}
+
+	for _, sort := range []string{"key.asc", "key.desc", "unix_timestamp_in_s.desc", "unix_timestamp_in_s.asc", "process_definition_id.asc", "process_definition_id.desc"} {
+		t.Run(sort, func(t *testing.T) {
+			expected, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: sort})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(expected) != 23 {
+				t.Error(len(expected))
+			}
+			for _, limit := range []int{1, 4, 23, 30} {
+				actual := paginate(t, model.VariablesQueryOptions{Sort: sort, Limit: limit}, nil)
+				if !reflect.DeepEqual(actual, expected) {
+					t.Errorf("limit %v\n%#v\n%#v", limit, actual, expected)
+				}
+			}
+		})
+	}
+
+	t.Run("filtered", func(t *testing.T) {
+		query := model.VariablesQueryOptions{Sort: "unix_timestamp_in_s.asc", ProcessDefinitionId: "d1"}
+		expected, err := ctrl.List(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		query.Limit = 2
+		actual := paginate(t, query, nil)
+		if len(expected) != 6 || !reflect.DeepEqual(actual, expected) {
+			t.Errorf("\n%#v\n%#v", actual, expected)
+		}
+	})
+
+	t.Run("writes during pagination", func(t *testing.T) {
+		//variables inserted before the cursor position are not listed, variables after the position are listed once
+		actual := paginate(t, model.VariablesQueryOptions{Sort: "key.asc", Limit: 5}, func(page int) {
+			for _, key := range []string{fmt.Sprintf("key%02d_", page), "key99"} {
+				err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: page})
+				if err != nil {
+					t.Error(err)
+				}
+			}
+		})
+		seen := map[string]bool{}
+		for _, variable := range actual {
+			if seen[variable.Key] {
+				t.Error("duplicate", variable.Key)
+			}
+			seen[variable.Key] = true
+			if strings.HasSuffix(variable.Key, "_") {
+				t.Error("unexpected", variable.Key)
+			}
+		}
+		for i := 0; i < 23; i++ {
+			if key := fmt.Sprintf("key%02d", i); !seen[key] {
+				t.Error("missing", key)
+			}
+		}
+		if !seen["key99"] {
+			t.Error("missing key99")
+		}
+	})
+
+	t.Run("value sort has no cursor", func(t *testing.T) {
+		query := model.VariablesQueryOptions{Sort: "value/n.asc", Limit: 2}
+		list, err := ctrl.List(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(list) != 2 || model.NextVariablesCursor(query, list) != "" {
+			t.Errorf("%#v", list)
+		}
+		query.Cursor = model.VariablesCursor{Sort: "value/n.asc,key.asc", Values: []interface{}{"key01"}}.Encode()
+		_, err = ctrl.List(ctx, userid, query)
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("invalid cursor", func(t *testing.T) {
+		_, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: "key.desc", Limit: 2, Cursor: model.VariablesCursor{Sort: "key.asc", Values: []interface{}{"key01"}}.Encode()})
+		if err == nil {
+			t.Error("expected error")
+		}
+		_, err = ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: "unix_timestamp_in_s.asc", Limit: 2, Cursor: model.VariablesCursor{Sort: "unix_timestamp_in_s.asc,key.asc", Values: []interface{}{"foo", "key01"}}.Encode()})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+}
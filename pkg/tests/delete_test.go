@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestDelete(t *testing.T) {
+	testWithBackends(t, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+	}, runDeleteTests)
+}
+
+func TestDeleteApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("create v1", testRequest(config, "PUT", "/values/v1", 1, http.StatusNoContent, nil))
+	t.Run("create v2", testRequest(config, "PUT", "/values/v2", 2, http.StatusNoContent, nil))
+	t.Run("dry run", testRequest(config, "DELETE", "/variables?key_regex=1&dry_run=true", nil, http.StatusOK, model.DeleteResult{Count: 1, DryRun: true}))
+	t.Run("dry run with keys", testRequest(config, "DELETE", "/variables?dry_run=true&include_keys=true", nil, http.StatusOK, model.DeleteResult{Count: 2, DryRun: true, Keys: []string{"v1", "v2"}}))
+	t.Run("invalid dry run", testRequest(config, "DELETE", "/variables?dry_run=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid include keys", testRequest(config, "DELETE", "/variables?include_keys=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid value filter", testRequest(config, "DELETE", "/variables?value_filter=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid updated after", testRequest(config, "DELETE", "/variables?updated_after=foo", nil, http.StatusBadRequest, nil))
+	t.Run("delete without filter", testRequest(config, "DELETE", "/variables", nil, http.StatusBadRequest, nil))
+	t.Run("invalid all", testRequest(config, "DELETE", "/variables?all=foo", nil, http.StatusBadRequest, nil))
+	t.Run("delete", testRequest(config, "DELETE", "/variables?key_regex=1", nil, http.StatusOK, model.DeleteResult{Count: 1}))
+	t.Run("list", testRequest(config, "GET", "/variables", nil, http.StatusOK, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "v2", Value: 2}, UnixTimestampInS: configuration.TimeNow().Unix()}}))
+}
+
+func runDeleteTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+	other := secendOwnerTokenUser
+	adminid := adminTokenUser
+
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	set := func(userid string, timestamp int64, variable model.Variable) {
+		configuration.TimeNow = func() time.Time {
+			return time.Unix(timestamp, 0)
+		}
+		err := ctrl.Set(ctx, userid, variable)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	set(userid, 1000, model.Variable{Key: "a1", Value: map[string]interface{}{"status": "ok"}, ProcessInstanceId: "i1"})
+	set(userid, 1000, model.Variable{Key: "a1", Value: map[string]interface{}{"status": "ok"}, ProcessInstanceId: "i1"})
+	set(userid, 1010, model.Variable{Key: "a2", Value: map[string]interface{}{"status": "failed"}, ProcessInstanceId: "i1"})
+	set(userid, 1020, model.Variable{Key: "b1", Value: map[string]interface{}{"status": "failed"}, ProcessInstanceId: "i2"})
+	set(userid, 1030, model.Variable{Key: "b2", Value: map[string]interface{}{"status": "ok"}, ProcessInstanceId: "i2"})
+	set(userid, 1040, model.Variable{Key: "c1", Value: "x", ProcessDefinitionId: "d1"})
+	set(userid, 1040, model.Variable{Key: "c2", Value: "y", ProcessDefinitionId: "d1", ProcessInstanceId: "i3"})
+	set(userid, 1040, model.Variable{Key: "c3", Value: "z", ProcessDefinitionId: "d1", ExpiresAt: 1050}) //expired, not counted by the deletes
+	set(other, 1000, model.Variable{Key: "a1", Value: "other", ProcessInstanceId: "i1"})
+	configuration.TimeNow = backup
+
+	checkKeys := func(t *testing.T, userid string, expected []string) {
+		list, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		keys := []string{}
+		for _, variable := range list {
+			keys = append(keys, variable.Key)
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Error(keys, expected)
+		}
+	}
+
+	deleteVariables := func(userid string, query model.VariablesQueryOptions, options model.DeleteOptions, expected model.DeleteResult) func(t *testing.T) {
+		return func(t *testing.T) {
+			result, err := ctrl.DeleteVariables(ctx, userid, query, options)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !reflect.DeepEqual(result, expected) {
+				t.Errorf("%#v %#v", result, expected)
+			}
+		}
+	}
+
+	t.Run("dry run", deleteVariables(userid, model.VariablesQueryOptions{KeyRegex: "^a"}, model.DeleteOptions{DryRun: true}, model.DeleteResult{Count: 2, DryRun: true}))
+	t.Run("dry run with keys", deleteVariables(userid, model.VariablesQueryOptions{KeyRegex: "^a"}, model.DeleteOptions{DryRun: true, IncludeKeys: true}, model.DeleteResult{Count: 2, DryRun: true, Keys: []string{"a1", "a2"}}))
+	t.Run("dry run without matches", deleteVariables(userid, model.VariablesQueryOptions{KeyRegex: "^x"}, model.DeleteOptions{DryRun: true, IncludeKeys: true}, model.DeleteResult{Count: 0, DryRun: true}))
+	t.Run("dry run deletes nothing", func(t *testing.T) {
+		checkKeys(t, userid, []string{"a1", "a2", "b1", "b2", "c1", "c2"})
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := ctrl.DeleteVariables(ctx, userid, model.VariablesQueryOptions{ValueFilters: []model.ValueFilter{{Path: "/status", Operator: "foo"}}}, model.DeleteOptions{})
+		if err == nil {
+			t.Error("expected error")
+		}
+		checkKeys(t, userid, []string{"a1", "a2", "b1", "b2", "c1", "c2"})
+	})
+
+	t.Run("delete by instance and value", deleteVariables(userid, model.VariablesQueryOptions{
+		ProcessInstanceId: "i1",
+		ValueFilters:      []model.ValueFilter{{Path: "/status", Operator: model.ValueFilterEq, Value: "failed"}},
+	}, model.DeleteOptions{}, model.DeleteResult{Count: 1}))
+	t.Run("check delete by instance and value", func(t *testing.T) {
+		checkKeys(t, userid, []string{"a1", "b1", "b2", "c1", "c2"})
+	})
+
+	t.Run("delete by key", deleteVariables(userid, model.VariablesQueryOptions{KeyRegex: "^a"}, model.DeleteOptions{}, model.DeleteResult{Count: 1}))
+	t.Run("check delete by key", func(t *testing.T) {
+		checkKeys(t, userid, []string{"b1", "b2", "c1", "c2"})
+		checkKeys(t, other, []string{"a1"})
+	})
+
+	t.Run("history is deleted", func(t *testing.T) {
+		set(userid, 1050, model.Variable{Key: "a1", Value: "new"})
+		configuration.TimeNow = backup
+		versions, err := ctrl.History(ctx, userid, "a1", model.HistoryQueryOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//a1 reached revision 2 before it was deleted, the new history continues after it
+		if len(versions) != 1 || versions[0].Revision != 3 {
+			t.Errorf("%#v", versions)
+		}
+	})
+
+	t.Run("delete by timestamp", deleteVariables(userid, model.VariablesQueryOptions{UpdatedAfter: 1015, UpdatedBefore: 1035}, model.DeleteOptions{}, model.DeleteResult{Count: 2}))
+	t.Run("check delete by timestamp", func(t *testing.T) {
+		checkKeys(t, userid, []string{"a1", "c1", "c2"})
+	})
+
+	t.Run("delete without matches", deleteVariables(userid, model.VariablesQueryOptions{KeyRegex: "^x"}, model.DeleteOptions{}, model.DeleteResult{Count: 0}))
+
+	t.Run("delete without filter", func(t *testing.T) {
+		_, err := ctrl.DeleteVariables(ctx, userid, model.VariablesQueryOptions{}, model.DeleteOptions{})
+		if err == nil {
+			t.Error("expected error")
+		}
+		if _, isClient := ctrl.(*client.Client); !isClient && !errors.Is(err, model.ErrMissingDeleteFilter) {
+			t.Error(err)
+		}
+		checkKeys(t, userid, []string{"a1", "c1", "c2"})
+	})
+
+	t.Run("delete process definition", func(t *testing.T) {
+		result, err := ctrl.DeleteProcessDefinition(ctx, adminid, "d1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Count != 2 {
+			t.Error(result.Count)
+		}
+		checkKeys(t, userid, []string{"a1"})
+	})
+
+	t.Run("delete process instance", func(t *testing.T) {
+		result, err := ctrl.DeleteProcessInstance(ctx, adminid, "i1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Count != 1 {
+			t.Error(result.Count)
+		}
+		checkKeys(t, userid, []string{"a1"})
+		checkKeys(t, other, []string{})
+	})
+
+	t.Run("delete unknown process instance", func(t *testing.T) {
+		result, err := ctrl.DeleteProcessInstance(ctx, adminid, "i1")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if result.Count != 0 {
+			t.Error(result.Count)
+		}
+	})
+
+	t.Run("delete all", deleteVariables(userid, model.VariablesQueryOptions{}, model.DeleteOptions{All: true}, model.DeleteResult{Count: 1}))
+	t.Run("check delete all", func(t *testing.T) {
+		checkKeys(t, userid, []string{})
+	})
+}
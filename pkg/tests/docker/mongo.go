@@ -18,10 +18,16 @@ package docker
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/exec"
 	"github.com/testcontainers/testcontainers-go/wait"
+	"io"
 	"log"
+	"strings"
 	"sync"
+	"time"
 )
 
 func Mongo(ctx context.Context, wg *sync.WaitGroup) (hostport string, containerip string, err error) {
@@ -29,6 +35,7 @@ func Mongo(ctx context.Context, wg *sync.WaitGroup) (hostport string, containeri
 	c, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
 		ContainerRequest: testcontainers.ContainerRequest{
 			Image:        "mongo:4.1.11",
+			Cmd:          []string{"--replSet", "rs0"}, //transactions need a replica set
 			ExposedPorts: []string{"27017/tcp"},
 			WaitingFor: wait.ForAll(
 				wait.ForLog("waiting for connections"),
@@ -49,6 +56,11 @@ func Mongo(ctx context.Context, wg *sync.WaitGroup) (hostport string, containeri
 		log.Println("DEBUG: remove container mongo", c.Terminate(context.Background()))
 	}()
 
+	err = initReplicaSet(ctx, c)
+	if err != nil {
+		return "", "", err
+	}
+
 	containerip, err = c.ContainerIP(ctx)
 	if err != nil {
 		return "", "", err
@@ -61,3 +73,25 @@ func Mongo(ctx context.Context, wg *sync.WaitGroup) (hostport string, containeri
 
 	return hostport, containerip, err
 }
+
+func initReplicaSet(ctx context.Context, c testcontainers.Container) error {
+	code, _, err := c.Exec(ctx, []string{"mongo", "--quiet", "--eval", "rs.initiate()"})
+	if err != nil {
+		return err
+	}
+	if code != 0 {
+		return fmt.Errorf("unable to initiate replica set: exit code %v", code)
+	}
+	for i := 0; i < 30; i++ {
+		_, out, err := c.Exec(ctx, []string{"mongo", "--quiet", "--eval", "db.isMaster().ismaster"}, exec.Multiplexed())
+		if err != nil {
+			return err
+		}
+		temp, _ := io.ReadAll(out)
+		if strings.TrimSpace(string(temp)) == "true" {
+			return nil
+		}
+		time.Sleep(time.Second)
+	}
+	return errors.New("replica set not ready")
+}
@@ -20,41 +20,144 @@ import (
 	"context"
 	"errors"
 	"github.com/SENERGY-Platform/process-io-api/pkg"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
 	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
 	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
 	"github.com/SENERGY-Platform/process-io-api/pkg/tests/docker"
+	"net/http"
+	"os"
+	"path/filepath"
 	"sync"
+	"testing"
 	"time"
 )
 
+// testBackend is a database the shared tests run against; with useClient the tests access the service through the api client, otherwise they call the controller directly
+type testBackend struct {
+	name      string
+	db        string
+	useClient bool
+}
+
+var testBackends = []testBackend{
+	{name: "ClientMongo", db: "mongodb", useClient: true},
+	{name: "ControllerPostgres", db: "postgres", useClient: false},
+	{name: "ClientMemory", db: "memory", useClient: true},
+	{name: "ControllerBolt", db: "bolt", useClient: false},
+}
+
+// testEnv is a started test environment of a testBackend
+type testEnv struct {
+	testBackend
+	config configuration.Config
+	ctrl   api.Controller         //the api client or the controller, see testBackend.useClient
+	local  *controller.Controller //the controller of the started service
+}
+
+// testWithBackends runs the tests as subtest per testBackend, each against a new environment with the config changed by modify
+func testWithBackends(t *testing.T, modify func(config *configuration.Config), run func(t *testing.T, ctrl api.Controller)) {
+	testWithBackendEnvs(t, modify, func(t *testing.T, env testEnv) {
+		run(t, env.ctrl)
+	})
+}
+
+// testWithBackendEnvs is testWithBackends for tests that need more of the environment than the controller
+func testWithBackendEnvs(t *testing.T, modify func(config *configuration.Config), run func(t *testing.T, env testEnv)) {
+	for _, backend := range testBackends {
+		t.Run(backend.name, func(t *testing.T) {
+			testWithBackend(t, backend, modify, run)
+		})
+	}
+}
+
+// testWithBackend starts a new environment for the backend and runs the tests against it
+func testWithBackend(t *testing.T, backend testBackend, modify func(config *configuration.Config), run func(t *testing.T, env testEnv)) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, ctrl, err := StartTestEnvWithConfig(ctx, wg, backend.db, modify)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	env := testEnv{testBackend: backend, config: config, ctrl: ctrl, local: ctrl}
+	if backend.useClient {
+		env.ctrl = client.NewWithAuth("http://localhost:"+config.ServerPort, MockAuth(map[string]string{
+			testTokenUser:        testtoken,
+			secendOwnerTokenUser: secondOwnerToken,
+			adminTokenUser:       admintoken,
+		}), true)
+	}
+	run(t, env)
+}
+
 func StartTestEnv(ctx context.Context, wg *sync.WaitGroup, dbSelection string) (config configuration.Config, ctrl *controller.Controller, err error) {
-	config, err = configuration.Load("../../config.json")
+	return StartTestEnvWithConfig(ctx, wg, dbSelection, nil)
+}
+
+// StartTestEnvWithConfig is StartTestEnv with the possibility to change the loaded config before the service is started
+func StartTestEnvWithConfig(ctx context.Context, wg *sync.WaitGroup, dbSelection string, modify func(config *configuration.Config)) (config configuration.Config, ctrl *controller.Controller, err error) {
+	config, err = NewTestConfig(ctx, wg, dbSelection)
 	if err != nil {
 		return config, nil, err
 	}
+	if modify != nil {
+		modify(&config)
+	}
+
+	//all test environments listen on the same port; connections kept alive for previous environments must not be reused
+	http.DefaultClient.CloseIdleConnections()
+
+	ctrl, err = pkg.Start(ctx, wg, config)
+
+	time.Sleep(200 * time.Millisecond)
+
+	return config, ctrl, err
+}
+
+// NewTestConfig loads the default config and starts the selected database, if it needs an external service
+func NewTestConfig(ctx context.Context, wg *sync.WaitGroup, dbSelection string) (config configuration.Config, err error) {
+	config, err = configuration.Load("../../config.json")
+	if err != nil {
+		return config, err
+	}
 	config.DisableHttpLogger = true
 	config.DatabaseSelection = dbSelection
 	switch dbSelection {
 	case "mongodb":
 		port, _, err := docker.Mongo(ctx, wg)
 		if err != nil {
-			return config, nil, err
+			return config, err
 		}
-		config.MongoUrl = "mongodb://localhost:" + port
+		config.MongoUrl = "mongodb://localhost:" + port + "/?directConnection=true"
 	case "postgres":
 		config.PostgresConnString, err = docker.Postgres(ctx, wg, "test")
 		if err != nil {
-			return config, nil, err
+			return config, err
 		}
+	case "memory":
+	case "bolt":
+		dir, err := os.MkdirTemp("", "process-io-bolt")
+		if err != nil {
+			return config, err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-ctx.Done()
+			time.Sleep(100 * time.Millisecond) //give the database time to close the file
+			os.RemoveAll(dir)
+		}()
+		config.BoltFile = filepath.Join(dir, "test.db")
 	default:
-		return config, nil, errors.New("unknown database: " + dbSelection)
+		return config, errors.New("unknown database: " + dbSelection)
 	}
-
-	ctrl, err = pkg.Start(ctx, wg, config)
-
-	time.Sleep(200 * time.Millisecond)
-
-	return config, ctrl, err
+	return config, nil
 }
 
 const testTokenUser = "testOwner"
@@ -0,0 +1,371 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/memory"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestExport(t *testing.T) {
+	testWithBackends(t, func(config *configuration.Config) {
+		config.QuotaMaxValueBytes = 100
+	}, runExportTests)
+}
+
+func TestExportApiMemory(t *testing.T) {
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	sendImport := func(t *testing.T, query string, body string, expectedStatusCode int) string {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, "http://localhost:"+config.ServerPort+"/import"+query, strings.NewReader(body))
+		if err != nil {
+			t.Error(err)
+			return ""
+		}
+		req.Header.Set("Authorization", testtoken)
+		req.Header.Set("Content-Type", model.NdjsonContentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return ""
+		}
+		defer resp.Body.Close()
+		temp, _ := io.ReadAll(resp.Body)
+		if resp.StatusCode != expectedStatusCode {
+			t.Error(resp.StatusCode, string(temp))
+		}
+		return string(temp)
+	}
+
+	t.Run("import ndjson", func(t *testing.T) {
+		result := sendImport(t, "", `{"key":"b","value":{"foo":"bar"},"process_instance_id":"i1","unix_timestamp_in_s":42}
+{"key":"a","value":1}
+`, http.StatusOK)
+		summary := model.ImportSummary{}
+		err := json.Unmarshal([]byte(result), &summary)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(summary, model.ImportSummary{Created: []string{"b", "a"}, Updated: []string{}, Skipped: []string{}}) {
+			t.Errorf("%#v", summary)
+		}
+	})
+
+	t.Run("import invalid", func(t *testing.T) {
+		sendImport(t, "", `{"key":"c","value":1}
+{"key":`, http.StatusBadRequest)
+		sendImport(t, "?conflict=unknown", `[{"key":"c","value":1}]`, http.StatusBadRequest)
+		sendImport(t, "", `[{"key":"c","value":1},{"key":"c","value":2}]`, http.StatusBadRequest)
+	})
+
+	t.Run("import conflict", func(t *testing.T) {
+		sendImport(t, "?conflict=fail", `[{"key":"c","value":1},{"key":"a","value":2}]`, http.StatusConflict)
+	})
+
+	expected := []model.VariableWithUnixTimestamp{
+		{Variable: model.Variable{Key: "a", Value: 1}, UnixTimestampInS: 1000},
+		{Variable: model.Variable{Key: "b", Value: map[string]interface{}{"foo": "bar"}, ProcessInstanceId: "i1"}, UnixTimestampInS: 42},
+	}
+
+	t.Run("export json", testRequest(config, "GET", "/export?format=json", nil, http.StatusOK, expected))
+
+	t.Run("export ndjson", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodGet, "http://localhost:"+config.ServerPort+"/export", nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		req.Header.Set("Authorization", testtoken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != model.NdjsonContentType {
+			t.Error(resp.StatusCode, resp.Header.Get("Content-Type"))
+			return
+		}
+		temp, _ := io.ReadAll(resp.Body)
+		expectedBody := `{"key":"a","value":1,"unix_timestamp_in_s":1000}
+{"key":"b","value":{"foo":"bar"},"process_instance_id":"i1","unix_timestamp_in_s":42}
+`
+		if string(temp) != expectedBody {
+			t.Error(string(temp))
+		}
+	})
+
+	t.Run("export unknown format", testRequest(config, "GET", "/export?format=xml", nil, http.StatusBadRequest, nil))
+
+	t.Run("export empty json", testRequestWithToken(config, secondOwnerToken, "GET", "/export?format=json", nil, http.StatusOK, []interface{}{}))
+}
+
+func runExportTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	source := testTokenUser
+	target := secendOwnerTokenUser
+
+	export := func(t *testing.T, userid string) (result []model.VariableWithUnixTimestamp) {
+		t.Helper()
+		result = []model.VariableWithUnixTimestamp{}
+		err := ctrl.Export(ctx, userid, func(variable model.VariableWithUnixTimestamp) error {
+			result = append(result, variable)
+			return nil
+		})
+		if err != nil {
+			t.Error(err)
+		}
+		return result
+	}
+
+	for _, variable := range []model.Variable{
+		{Key: "b", Value: "foo", ProcessDefinitionId: "d1", ProcessInstanceId: "i1"},
+		{Key: "a", Value: map[string]interface{}{"n": 1.0}},
+		{Key: "c", Value: []interface{}{1.0, "x"}, ProcessDefinitionId: "d2", ExpiresAt: 4102444800},
+	} {
+		err := ctrl.Set(ctx, source, variable)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	exported := []model.VariableWithUnixTimestamp{}
+
+	t.Run("export", func(t *testing.T) {
+		exported = export(t, source)
+		keys := []string{}
+		for _, variable := range exported {
+			keys = append(keys, variable.Key)
+			if variable.UnixTimestampInS == 0 {
+				t.Errorf("%#v", variable)
+			}
+		}
+		if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+			t.Errorf("%#v", keys)
+		}
+		if exported[1].ProcessDefinitionId != "d1" || exported[1].ProcessInstanceId != "i1" || exported[2].ExpiresAt != 4102444800 {
+			t.Errorf("%#v", exported)
+		}
+	})
+
+	t.Run("export empty", func(t *testing.T) {
+		if result := export(t, target); len(result) != 0 {
+			t.Errorf("%#v", result)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		summary, err := ctrl.Import(ctx, target, exported, model.ImportConflictFail)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(summary, model.ImportSummary{Created: []string{"a", "b", "c"}, Updated: []string{}, Skipped: []string{}}) {
+			t.Errorf("%#v", summary)
+		}
+		if result := export(t, target); !reflect.DeepEqual(result, exported) {
+			t.Errorf("\n%#v\n%#v", result, exported)
+		}
+	})
+
+	changed := []model.VariableWithUnixTimestamp{
+		{Variable: model.Variable{Key: "a", Value: "changed"}, UnixTimestampInS: 10},
+		{Variable: model.Variable{Key: "d", Value: "new"}, UnixTimestampInS: 11},
+	}
+
+	t.Run("import conflict fail", func(t *testing.T) {
+		_, err := ctrl.Import(ctx, target, changed, model.ImportConflictFail)
+		if !errors.Is(err, model.ErrImportConflict) {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, target, "d")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != nil {
+			t.Errorf("%#v", variable)
+		}
+	})
+
+	t.Run("import conflict skip", func(t *testing.T) {
+		summary, err := ctrl.Import(ctx, target, changed, model.ImportConflictSkip)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(summary, model.ImportSummary{Created: []string{"d"}, Updated: []string{}, Skipped: []string{"a"}}) {
+			t.Errorf("%#v", summary)
+		}
+		variable, err := ctrl.Get(ctx, target, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(variable, exported[0]) {
+			t.Errorf("%#v", variable)
+		}
+	})
+
+	t.Run("import conflict overwrite", func(t *testing.T) {
+		summary, err := ctrl.Import(ctx, target, changed, model.ImportConflictOverwrite)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(summary, model.ImportSummary{Created: []string{}, Updated: []string{"a", "d"}, Skipped: []string{}}) {
+			t.Errorf("%#v", summary)
+		}
+		variable, err := ctrl.Get(ctx, target, "a")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(variable, changed[0]) {
+			t.Errorf("%#v", variable)
+		}
+	})
+
+	t.Run("import invalid", func(t *testing.T) {
+		_, err := ctrl.Import(ctx, target, changed, "unknown")
+		if !errors.Is(err, model.ErrInvalidImport) {
+			t.Error(err)
+		}
+		_, err = ctrl.Import(ctx, target, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Value: 1}}}, model.ImportConflictOverwrite)
+		if !errors.Is(err, model.ErrInvalidImport) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("import conflict fail is atomic", func(t *testing.T) {
+		//the second variable exceeds max_value_bytes after the first is written
+		_, err := ctrl.Import(ctx, target, []model.VariableWithUnixTimestamp{
+			{Variable: model.Variable{Key: "new", Value: 1.0}},
+			{Variable: model.Variable{Key: "large", Value: strings.Repeat("x", 100)}},
+		}, model.ImportConflictFail)
+		if !errors.Is(err, model.ErrValueTooLarge) {
+			t.Error(err)
+		}
+		variable, err := ctrl.Get(ctx, target, "new")
+		if err != nil || variable.Value != nil {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("source unchanged", func(t *testing.T) {
+		if result := export(t, source); !reflect.DeepEqual(result, exported) {
+			t.Errorf("\n%#v\n%#v", result, exported)
+		}
+	})
+}
+
+// TestImportWithoutTransactionsMemory imports into a database without transaction support, like mongodb without replica set
+func TestImportWithoutTransactionsMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := NewTestConfig(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	db, err := memory.New(ctx, wg, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	ctrl := controller.New(config, noTransactions{Database: db})
+	userid := testTokenUser
+
+	err = ctrl.Set(ctx, userid, model.Variable{Key: "a", Value: 1.0})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("import conflict fail", func(t *testing.T) {
+		_, err := ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{
+			{Variable: model.Variable{Key: "b", Value: 2.0}},
+			{Variable: model.Variable{Key: "a", Value: 3.0}},
+		}, model.ImportConflictFail)
+		if !errors.Is(err, model.ErrImportConflict) {
+			t.Error(err)
+		}
+		variable, err := ctrl.Get(ctx, userid, "b")
+		if err != nil || variable.Value != nil {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("import without conflict", func(t *testing.T) {
+		summary, err := ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{
+			{Variable: model.Variable{Key: "b", Value: 2.0}},
+			{Variable: model.Variable{Key: "c", Value: 3.0}},
+		}, model.ImportConflictFail)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(summary, model.ImportSummary{Created: []string{"b", "c"}, Updated: []string{}, Skipped: []string{}}) {
+			t.Errorf("%#v", summary)
+		}
+	})
+}
+
+type noTransactions struct {
+	controller.Database
+}
+
+func (this noTransactions) Transaction(ctx context.Context, f func(ctx context.Context) error) error {
+	return model.ErrTransactionsUnsupported
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/controller"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/memory"
+)
+
+func TestHealthMongo(t *testing.T) {
+	testHealth(t, "mongodb", true)
+}
+
+func TestHealthPostgres(t *testing.T) {
+	testHealth(t, "postgres", true)
+}
+
+func TestHealthMemory(t *testing.T) {
+	testHealth(t, "memory", false)
+}
+
+func TestHealthBolt(t *testing.T) {
+	testHealth(t, "bolt", true)
+}
+
+func TestHealthCacheBolt(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, ctrl, err := StartTestEnvWithConfig(ctx, wg, "bolt", func(config *configuration.Config) {
+		config.CacheSize = 10
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if err = ctrl.Ping(ctx); err != nil {
+		t.Error(err)
+	}
+	cancel()
+	wg.Wait()
+	if err = ctrl.Ping(context.Background()); err == nil {
+		t.Error("expected ping through the cache to fail after shutdown")
+	}
+}
+
+func TestHealthReadinessTimeout(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := NewTestConfig(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.ReadinessTimeout = "50ms"
+	db, err := memory.New(ctx, wg, config)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	ready := api.GetRouter(config, controller.New(config, slowPinger{Database: db, delay: 10 * time.Millisecond}))
+	t.Run("fast ping", testHealthRequest(ready, "/health/ready", http.StatusOK))
+
+	slow := api.GetRouter(config, controller.New(config, slowPinger{Database: db, delay: time.Minute}))
+	start := time.Now()
+	t.Run("slow ping", testHealthRequest(slow, "/health/ready", http.StatusServiceUnavailable))
+	if time.Since(start) > 10*time.Second {
+		t.Error("readiness timeout not applied", time.Since(start))
+	}
+	t.Run("live with slow ping", testHealthRequest(slow, "/health/live", http.StatusOK))
+}
+
+type slowPinger struct {
+	controller.Database
+	delay time.Duration
+}
+
+func (this slowPinger) Ping(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(this.delay):
+		return nil
+	}
+}
+
+func testHealthRequest(handler http.Handler, path string, expectedStatusCode int) func(t *testing.T) {
+	return func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+		if recorder.Code != expectedStatusCode {
+			t.Error(recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func testHealth(t *testing.T, dbSelection string, unreadyAfterShutdown bool) {
+	wg := &sync.WaitGroup{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, ctrl, err := StartTestEnv(ctx, wg, dbSelection)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	for _, path := range []string{"/health/live", "/health/ready"} {
+		t.Run(path+" without token", func(t *testing.T) {
+			resp, err := http.Get("http://localhost:" + config.ServerPort + path)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Error(resp.StatusCode)
+			}
+		})
+	}
+
+	t.Run("client ping", func(t *testing.T) {
+		err := client.NewWithAuth("http://localhost:"+config.ServerPort, MockAuth(map[string]string{}), true).Ping(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+	})
+
+	cancel()
+	wg.Wait()
+
+	t.Run("ping after shutdown", func(t *testing.T) {
+		err := ctrl.Ping(context.Background())
+		if unreadyAfterShutdown && err == nil {
+			t.Error("expected error")
+		}
+		if !unreadyAfterShutdown && err != nil {
+			t.Error(err)
+		}
+	})
+	expectedStatusCode := http.StatusOK
+	if unreadyAfterShutdown {
+		expectedStatusCode = http.StatusServiceUnavailable
+	}
+	t.Run("ready after shutdown", testHealthRequest(api.GetRouter(config, ctrl), "/health/ready", expectedStatusCode))
+}
@@ -0,0 +1,301 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestHierarchy(t *testing.T) {
+	testWithBackends(t, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+	}, runHierarchyTests)
+}
+
+// TestHierarchyApiMemory uses unescaped slashes in the paths, the client escapes them
+func TestHierarchyApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnvWithConfig(ctx, wg, "memory", func(config *configuration.Config) {
+		config.HistoryEnabled = true
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("set variable", testRequest(config, "PUT", "/variables/a/b/c", model.Variable{Key: "a/b/c", Value: 1}, http.StatusNoContent, nil))
+	t.Run("update variable", testRequest(config, "PUT", "/variables/a/b/c", model.Variable{Key: "a/b/c", Value: 2}, http.StatusNoContent, nil))
+	t.Run("get variable value", testRequest(config, "GET", "/values/a/b/c", nil, http.StatusOK, 2))
+	t.Run("history", testRequest(config, "GET", "/history/variables/a/b/c?limit=1", nil, http.StatusOK, nil))
+	t.Run("restore", testRequest(config, "POST", "/restore/variables/a/b/c", model.RestoreRequest{Revision: 1}, http.StatusOK, nil))
+	t.Run("get restored value", testRequest(config, "GET", "/values/a/b/c", nil, http.StatusOK, 1))
+	t.Run("set variable ending with history", testRequest(config, "PUT", "/variables/a/history", model.Variable{Key: "a/history", Value: 3}, http.StatusNoContent, nil))
+	t.Run("get variable ending with history", testRequest(config, "GET", "/variables/a/history", nil, http.StatusOK, nil))
+	t.Run("history of variable ending with history", testRequest(config, "GET", "/history/variables/a/history", nil, http.StatusOK, nil))
+	t.Run("restore variable ending with restore", testRequest(config, "POST", "/restore/variables/a/restore", model.RestoreRequest{Revision: 1}, http.StatusNotFound, nil))
+
+	t.Run("set value", testRequest(config, "PUT", "/values/a/b/d", 2, http.StatusNoContent, nil))
+	t.Run("increment", testRequest(config, "POST", "/increment/values/a/b/d", model.IncrementRequest{Delta: 1}, http.StatusOK, 3))
+	t.Run("set value ending with increment", testRequest(config, "PUT", "/values/a/increment", 4, http.StatusNoContent, nil))
+	t.Run("increment value ending with increment", testRequest(config, "POST", "/increment/values/a/increment", model.IncrementRequest{Delta: 1}, http.StatusOK, 5))
+	t.Run("set process definition value", testRequest(config, "PUT", "/process-definitions/p/values/a/e", 5, http.StatusNoContent, nil))
+	t.Run("set process instance value", testRequest(config, "PUT", "/process-definitions/p/process-instances/i/values/a/f/g", 6, http.StatusNoContent, nil))
+
+	t.Run("browse root", testRequest(config, "GET", "/browse/variables?delimiter=/", nil, http.StatusOK, model.VariablesListing{Variables: []model.VariableWithUnixTimestamp{}, CommonPrefixes: []string{"a/"}}))
+	t.Run("browse a", testRequest(config, "GET", "/browse/variables?prefix=a/&delimiter=/&limit=1", nil, http.StatusOK, model.VariablesListing{Variables: []model.VariableWithUnixTimestamp{}, CommonPrefixes: []string{"a/b/"}}))
+	t.Run("count a/b", testRequest(config, "GET", "/count/variables?key_prefix=a/b/", nil, http.StatusOK, model.Count{Count: 2}))
+
+	t.Run("delete value", testRequest(config, "DELETE", "/values/a/b/d", nil, http.StatusNoContent, nil))
+	t.Run("delete variable", testRequest(config, "DELETE", "/variables/a/b/c", nil, http.StatusNoContent, nil))
+	t.Run("count a/b after delete", testRequest(config, "GET", "/count/variables?key_prefix=a/b/", nil, http.StatusOK, model.Count{Count: 0}))
+	t.Run("invalid limit", testRequest(config, "GET", "/browse/variables?limit=foo", nil, http.StatusBadRequest, nil))
+	t.Run("null character in key", testRequest(config, "PUT", "/values/a%00b", 1, http.StatusBadRequest, nil))
+	t.Run("null character in instance id", testRequestWithToken(config, admintoken, "DELETE", "/process-instances/i%00", nil, http.StatusBadRequest, nil))
+}
+
+func runHierarchyTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	for _, key := range []string{"top", "plant1/name", "plant1/line3/threshold", "plant1/line3/speed", "plant1/line4/threshold", "plant2/name"} {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: key})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	t.Run("get", func(t *testing.T) {
+		variable, err := ctrl.Get(ctx, userid, "plant1/line3/threshold")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Key != "plant1/line3/threshold" || variable.Value != "plant1/line3/threshold" {
+			t.Error(variable)
+		}
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		_, _, err := ctrl.Patch(ctx, userid, "plant1/line3/config", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"max":10}`)}, model.Precondition{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		value, _, err := ctrl.GetAtPointer(ctx, userid, "plant1/line3/config", "/max")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != float64(10) {
+			t.Error(value)
+		}
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		value, _, err := ctrl.Increment(ctx, userid, "plant1/line3/count", 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != float64(2) {
+			t.Error(value)
+		}
+	})
+
+	t.Run("history and restore", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "plant1/name", Value: "renamed"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		history, err := ctrl.History(ctx, userid, "plant1/name", model.HistoryQueryOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(history) != 2 {
+			t.Error(history)
+			return
+		}
+		variable, _, err := ctrl.Restore(ctx, userid, "plant1/name", history[1].Revision, model.Precondition{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != "plant1/name" {
+			t.Error(variable)
+		}
+	})
+
+	//plant1/line3/ contains config, count, speed and threshold
+	tests := []struct {
+		name             string
+		query            model.VariablesQueryOptions
+		delimiter        string
+		expectedKeys     []string
+		expectedPrefixes []string
+	}{
+		{name: "root", delimiter: "/", expectedKeys: []string{"top"}, expectedPrefixes: []string{"plant1/", "plant2/"}},
+		{name: "plant1", query: model.VariablesQueryOptions{KeyPrefix: "plant1/"}, delimiter: "/", expectedKeys: []string{"plant1/name"}, expectedPrefixes: []string{"plant1/line3/", "plant1/line4/"}},
+		{name: "leaf", query: model.VariablesQueryOptions{KeyPrefix: "plant1/line3/"}, delimiter: "/", expectedKeys: []string{"plant1/line3/config", "plant1/line3/count", "plant1/line3/speed", "plant1/line3/threshold"}, expectedPrefixes: []string{}},
+		{name: "partial segment", query: model.VariablesQueryOptions{KeyPrefix: "plant"}, delimiter: "/", expectedKeys: []string{}, expectedPrefixes: []string{"plant1/", "plant2/"}},
+		{name: "without delimiter", query: model.VariablesQueryOptions{KeyPrefix: "plant1/line"}, expectedKeys: []string{"plant1/line3/config", "plant1/line3/count", "plant1/line3/speed", "plant1/line3/threshold", "plant1/line4/threshold"}, expectedPrefixes: []string{}},
+		{name: "other delimiter", query: model.VariablesQueryOptions{KeyPrefix: "plant1/"}, delimiter: "/t", expectedKeys: []string{"plant1/line3/config", "plant1/line3/count", "plant1/line3/speed", "plant1/name"}, expectedPrefixes: []string{"plant1/line3/t", "plant1/line4/t"}},
+		{name: "limit", query: model.VariablesQueryOptions{KeyPrefix: "plant1/", Limit: 2}, delimiter: "/", expectedKeys: []string{}, expectedPrefixes: []string{"plant1/line3/", "plant1/line4/"}},
+		{name: "offset", query: model.VariablesQueryOptions{KeyPrefix: "plant1/", Limit: 2, Offset: 1}, delimiter: "/", expectedKeys: []string{"plant1/name"}, expectedPrefixes: []string{"plant1/line4/"}},
+		{name: "offset after end", query: model.VariablesQueryOptions{KeyPrefix: "plant1/", Offset: 5}, delimiter: "/", expectedKeys: []string{}, expectedPrefixes: []string{}},
+		{name: "filter", query: model.VariablesQueryOptions{KeyGlob: "*/threshold"}, delimiter: "/", expectedKeys: []string{}, expectedPrefixes: []string{"plant1/"}},
+	}
+	for _, test := range tests {
+		t.Run("browse "+test.name, func(t *testing.T) {
+			listing, err := ctrl.Browse(ctx, userid, test.query, test.delimiter)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range listing.Variables {
+				keys = append(keys, variable.Key)
+			}
+			if !reflect.DeepEqual(keys, test.expectedKeys) {
+				t.Error(keys, test.expectedKeys)
+			}
+			if !reflect.DeepEqual(listing.CommonPrefixes, test.expectedPrefixes) {
+				t.Error(listing.CommonPrefixes, test.expectedPrefixes)
+			}
+		})
+	}
+
+	t.Run("browse prefix larger than page", func(t *testing.T) {
+		//big/sub0 is the first key after all keys starting with big/sub/
+		keys := []string{"big/sub0", "big/sup"}
+		for i := range 250 {
+			keys = append(keys, fmt.Sprintf("big/sub/%03d", i))
+		}
+		for _, key := range keys {
+			err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: key})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		for _, test := range []struct {
+			query    model.VariablesQueryOptions
+			expected string
+		}{
+			{query: model.VariablesQueryOptions{KeyPrefix: "big/"}, expected: "[big/sub/] [big/sub0 big/sup]"},
+			{query: model.VariablesQueryOptions{KeyPrefix: "big/", Limit: 1}, expected: "[big/sub/] []"},
+			{query: model.VariablesQueryOptions{KeyPrefix: "big/", Limit: 2}, expected: "[big/sub/] [big/sub0]"},
+			{query: model.VariablesQueryOptions{KeyPrefix: "big/", Limit: 1, Offset: 2}, expected: "[] [big/sup]"},
+			{query: model.VariablesQueryOptions{KeyPrefix: "big/", Keys: []string{"big/sub/001", "big/sup"}}, expected: "[big/sub/] [big/sup]"},
+		} {
+			listing, err := ctrl.Browse(ctx, userid, test.query, "/")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range listing.Variables {
+				keys = append(keys, variable.Key)
+			}
+			if actual := fmt.Sprint(listing.CommonPrefixes, keys); actual != test.expected {
+				t.Error(test.query, actual, test.expected)
+			}
+		}
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		err := ctrl.Delete(ctx, userid, "plant1/line3/speed")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		count, err := ctrl.Count(ctx, userid, model.VariablesQueryOptions{KeyPrefix: "plant1/line3/"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count.Count != 3 {
+			t.Error(count.Count)
+		}
+	})
+
+	t.Run("null character", func(t *testing.T) {
+		_, isClient := ctrl.(*client.Client)
+		check := func(t *testing.T, err error) {
+			if err == nil || (!isClient && !errors.Is(err, model.ErrInvalidId)) {
+				t.Error(err)
+			}
+		}
+		check(t, ctrl.Set(ctx, userid, model.Variable{Key: "plant1\x00line3", Value: 1}))
+		check(t, ctrl.Set(ctx, userid, model.Variable{Key: "plant1/line5", Value: 1, ProcessInstanceId: "i\x00"}))
+		_, _, err := ctrl.Increment(ctx, userid, "plant1\x00count", 1)
+		check(t, err)
+		_, _, err = ctrl.Patch(ctx, userid, "plant1\x00config", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"max":10}`)}, model.Precondition{})
+		check(t, err)
+		_, err = ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "plant1\x00import", Value: 1}}}, model.ImportConflictOverwrite)
+		check(t, err)
+		_, err = ctrl.DeleteProcessDefinition(ctx, userid, "p\x00")
+		check(t, err)
+		_, err = ctrl.DeleteProcessInstance(ctx, userid, "i\x00")
+		check(t, err)
+		count, err := ctrl.Count(ctx, userid, model.VariablesQueryOptions{KeyPrefix: "plant1"})
+		if err != nil || count.Count != 5 {
+			t.Error(err, count.Count)
+		}
+	})
+	t.Run("keys ending with route names", func(t *testing.T) {
+		for _, key := range []string{"plant3/history", "plant3/restore", "plant3/increment"} {
+			err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: 1})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			value, _, err := ctrl.Increment(ctx, userid, key, 1)
+			if err != nil || value != float64(2) {
+				t.Error(key, err, value)
+				return
+			}
+			history, err := ctrl.History(ctx, userid, key, model.HistoryQueryOptions{})
+			if err != nil || len(history) != 2 {
+				t.Error(key, err, history)
+				return
+			}
+			variable, _, err := ctrl.Restore(ctx, userid, key, history[1].Revision, model.Precondition{})
+			if err != nil || variable.Key != key || variable.Value != float64(1) {
+				t.Error(key, err, variable)
+			}
+		}
+	})
+}
@@ -0,0 +1,225 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"net/http"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHistoryDisabledMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	c := client.NewWithAuth("http://localhost:"+config.ServerPort, MockAuth(map[string]string{testTokenUser: testtoken, adminTokenUser: admintoken}), true)
+	_, err = c.History(ctx, testTokenUser, "foo", model.HistoryQueryOptions{})
+	if !errors.Is(err, model.ErrHistoryDisabled) {
+		t.Error(err)
+	}
+	_, _, err = c.GetAt(ctx, testTokenUser, "foo", 42)
+	if !errors.Is(err, model.ErrHistoryDisabled) {
+		t.Error(err)
+	}
+}
+
+func TestHistory(t *testing.T) {
+	now := atomic.Int64{}
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(now.Load(), 0)
+	}
+
+	testWithBackendEnvs(t, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+		config.HistoryMaxVersions = 4
+		config.HistoryMaxAge = "1h"
+	}, func(t *testing.T, env testEnv) {
+		now.Store(1000)
+		runHistoryTests(t, env.ctrl, &now)
+		if env.useClient {
+			t.Run("api history", testRequest(env.config, "GET", "/history/variables/h1?limit=1", nil, http.StatusOK, []model.VariableVersion{{
+				VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "h1", Value: "v2"}, UnixTimestampInS: 1006},
+				Revision:                  7,
+				Writer:                    testTokenUser,
+			}}))
+			t.Run("api at", testRequest(env.config, "GET", "/variables/h1?at=1004", nil, http.StatusOK, model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "h1", Value: "v4", ProcessInstanceId: "i1"}, UnixTimestampInS: 1003}))
+			t.Run("api invalid at", testRequest(env.config, "GET", "/variables/h1?at=foo", nil, http.StatusBadRequest, nil))
+		}
+	})
+}
+
+func runHistoryTests(t *testing.T, ctrl api.Controller, now *atomic.Int64) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	expectHistory := func(t *testing.T, key string, query model.HistoryQueryOptions, expectedValues []interface{}, expectedRevisions []int64) {
+		t.Helper()
+		history, err := ctrl.History(ctx, userid, key, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		values := []interface{}{}
+		revisions := []int64{}
+		for _, version := range history {
+			values = append(values, version.Value)
+			revisions = append(revisions, version.Revision)
+			if version.Writer != userid || version.Key != key {
+				t.Error(version)
+			}
+		}
+		if !reflect.DeepEqual(values, expectedValues) || !reflect.DeepEqual(revisions, expectedRevisions) {
+			t.Error(values, revisions, expectedValues, expectedRevisions)
+		}
+	}
+	expectAt := func(t *testing.T, key string, at int64, expectedValue interface{}, expectedRevision int64) {
+		t.Helper()
+		variable, revision, err := ctrl.GetAt(ctx, userid, key, at)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != expectedValue || revision != expectedRevision {
+			t.Error(variable.Value, revision, expectedValue, expectedRevision)
+		}
+	}
+
+	t.Run("writes are recorded", func(t *testing.T) {
+		for i, value := range []string{"v1", "v2", "v3"} {
+			now.Store(int64(1000 + i))
+			err := ctrl.Set(ctx, userid, model.Variable{Key: "h1", Value: value, ProcessInstanceId: "i1"})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		now.Store(1003)
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "h1", Value: "v4", ProcessInstanceId: "i1"}, model.Precondition{IfMatch: "3"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "h1", model.HistoryQueryOptions{}, []interface{}{"v4", "v3", "v2", "v1"}, []int64{4, 3, 2, 1})
+	})
+
+	t.Run("pagination", func(t *testing.T) {
+		expectHistory(t, "h1", model.HistoryQueryOptions{Limit: 2, Offset: 1}, []interface{}{"v3", "v2"}, []int64{3, 2})
+	})
+
+	t.Run("increment and patch are recorded", func(t *testing.T) {
+		now.Store(1004)
+		_, _, err := ctrl.Increment(ctx, userid, "counter", 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _, err = ctrl.Patch(ctx, userid, "counter", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"a": 1}`)}, model.Precondition{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "counter", model.HistoryQueryOptions{Limit: 1, Offset: 1}, []interface{}{float64(2)}, []int64{1})
+	})
+
+	t.Run("max versions", func(t *testing.T) {
+		now.Store(1005)
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "h1", Value: "v5", ProcessInstanceId: "i1"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "h1", model.HistoryQueryOptions{}, []interface{}{"v5", "v4", "v3", "v2"}, []int64{5, 4, 3, 2})
+	})
+
+	t.Run("get at", func(t *testing.T) {
+		expectAt(t, "h1", 999, nil, 0)
+		expectAt(t, "h1", 1000, nil, 0) //pruned
+		expectAt(t, "h1", 1001, "v2", 2)
+		expectAt(t, "h1", 1002, "v3", 3)
+		expectAt(t, "h1", 1004, "v4", 4)
+		expectAt(t, "h1", 2000, "v5", 5)
+		expectAt(t, "unknown", 2000, nil, 0)
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		now.Store(1006)
+		_, _, err := ctrl.Restore(ctx, userid, "h1", 2, model.Precondition{IfMatch: "4"})
+		if !errors.Is(err, model.ErrPreconditionFailed) {
+			t.Error(err)
+		}
+		_, _, err = ctrl.Restore(ctx, userid, "h1", 1, model.Precondition{})
+		if !errors.Is(err, model.ErrVersionNotFound) {
+			t.Error(err)
+		}
+		variable, revision, err := ctrl.Restore(ctx, userid, "h1", 2, model.Precondition{IfMatch: "5"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != "v2" || variable.ProcessInstanceId != "i1" || variable.UnixTimestampInS != 1006 || revision != 6 {
+			t.Error(variable, revision)
+		}
+		expectHistory(t, "h1", model.HistoryQueryOptions{Limit: 2}, []interface{}{"v2", "v5"}, []int64{6, 5})
+	})
+
+	t.Run("bulk is recorded", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{Set: []model.BulkSetItem{{Variable: model.Variable{Key: "h1", Value: "v2"}}}, Atomic: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "h1", model.HistoryQueryOptions{Limit: 1}, []interface{}{"v2"}, []int64{7})
+	})
+
+	t.Run("max age", func(t *testing.T) {
+		now.Store(1004 + 3600 + 1)
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "counter", Value: "new"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "counter", model.HistoryQueryOptions{}, []interface{}{"new"}, []int64{3})
+	})
+
+	t.Run("delete removes history", func(t *testing.T) {
+		err := ctrl.Delete(ctx, userid, "counter")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectHistory(t, "counter", model.HistoryQueryOptions{}, []interface{}{}, []int64{})
+	})
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"sync"
+	"testing"
+)
+
+func TestIncrement(t *testing.T) {
+	testWithBackends(t, nil, runIncrementTests)
+}
+
+func runIncrementTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	expectIncrement := func(t *testing.T, key string, delta float64, expectedValue float64, expectedRevision int64) {
+		t.Helper()
+		value, revision, err := ctrl.Increment(ctx, userid, key, delta)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != expectedValue {
+			t.Error(value, expectedValue)
+		}
+		if revision != expectedRevision {
+			t.Error(revision, expectedRevision)
+		}
+		variable, err := ctrl.Get(ctx, userid, key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != expectedValue {
+			t.Error(variable.Value, expectedValue)
+		}
+	}
+
+	t.Run("increment missing variable", func(t *testing.T) {
+		expectIncrement(t, "counter", 1, 1, 1)
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		expectIncrement(t, "counter", 2, 3, 2)
+	})
+
+	t.Run("decrement", func(t *testing.T) {
+		expectIncrement(t, "counter", -4, -1, 3)
+	})
+
+	t.Run("increment by fraction", func(t *testing.T) {
+		expectIncrement(t, "counter", 0.5, -0.5, 4)
+	})
+
+	t.Run("increment variable set by put", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "set", Value: 40, ProcessInstanceId: "i1"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectIncrement(t, "set", 2, 42, 2)
+		variable, err := ctrl.Get(ctx, userid, "set")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.ProcessInstanceId != "i1" {
+			t.Error(variable.ProcessInstanceId)
+		}
+	})
+
+	t.Run("increment non numeric variable", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "text", Value: "foo"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _, err = ctrl.Increment(ctx, userid, "text", 1)
+		if !errors.Is(err, model.ErrNotNumeric) {
+			t.Error(err)
+		}
+		variable, err := ctrl.Get(ctx, userid, "text")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != "foo" {
+			t.Error(variable.Value)
+		}
+	})
+
+	t.Run("concurrent increments", func(t *testing.T) {
+		const workers = 10
+		const increments = 10
+		wg := sync.WaitGroup{}
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < increments; j++ {
+					_, _, err := ctrl.Increment(ctx, userid, "parallel", 1)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+				}
+			}()
+		}
+		wg.Wait()
+		expectIncrement(t, "parallel", 0, workers*increments, workers*increments+1)
+	})
+}
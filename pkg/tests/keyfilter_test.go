@@ -0,0 +1,161 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestKeyFilter(t *testing.T) {
+	testWithBackends(t, nil, runKeyFilterTests)
+}
+
+func TestKeyFilterApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("list", testRequest(config, "GET", "/variables?key=a&key=b&key_prefix=a&key_glob=*&key_regex=a&key_regex_case_sensitive=true", nil, http.StatusOK, []interface{}{}))
+	t.Run("count", testRequest(config, "GET", "/count/variables?key=a&key=b&key_prefix=a&key_glob=*&key_regex=a&key_regex_case_sensitive=true", nil, http.StatusOK, model.Count{Count: 0}))
+	t.Run("invalid list glob", testRequest(config, "GET", "/variables?key_glob="+url.QueryEscape(`a\`), nil, http.StatusBadRequest, nil))
+	t.Run("invalid list regex", testRequest(config, "GET", "/variables?key_regex="+url.QueryEscape("("), nil, http.StatusBadRequest, nil))
+	t.Run("invalid list case sensitivity", testRequest(config, "GET", "/variables?key_regex=a&key_regex_case_sensitive=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid count glob", testRequest(config, "GET", "/count/variables?key_glob="+url.QueryEscape(`a\`), nil, http.StatusBadRequest, nil))
+	t.Run("invalid count regex", testRequest(config, "GET", "/count/variables?key_regex="+url.QueryEscape("("), nil, http.StatusBadRequest, nil))
+	t.Run("invalid delete regex", testRequest(config, "DELETE", "/variables?key_regex="+url.QueryEscape("("), nil, http.StatusBadRequest, nil))
+	t.Run("unsupported list regex", testRequest(config, "GET", "/variables?key_regex="+url.QueryEscape(`\bfoo`), nil, http.StatusBadRequest, nil))
+}
+
+// runKeyFilterTests checks that every database matches keys identically;
+// results are compared as sorted sets because the key order of the databases depends on their collation
+func runKeyFilterTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	for _, key := range []string{"Foo", "foo", "foo-bar", "foo-baz", "foobar", "bar", "a.b", "a*b", "a%b", "a_b", "aXb"} {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: key})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    model.VariablesQueryOptions
+		expected []string
+	}{
+		{name: "keys", query: model.VariablesQueryOptions{Keys: []string{"foo", "bar", "missing"}}, expected: []string{"bar", "foo"}},
+		{name: "keys are case-sensitive", query: model.VariablesQueryOptions{Keys: []string{"FOO"}}, expected: []string{}},
+		{name: "prefix", query: model.VariablesQueryOptions{KeyPrefix: "foo"}, expected: []string{"foo", "foo-bar", "foo-baz", "foobar"}},
+		{name: "prefix is case-sensitive", query: model.VariablesQueryOptions{KeyPrefix: "Foo"}, expected: []string{"Foo"}},
+		{name: "prefix with separator", query: model.VariablesQueryOptions{KeyPrefix: "foo-"}, expected: []string{"foo-bar", "foo-baz"}},
+		{name: "prefix with underscore", query: model.VariablesQueryOptions{KeyPrefix: "a_"}, expected: []string{"a_b"}},
+		{name: "prefix with percent", query: model.VariablesQueryOptions{KeyPrefix: "a%"}, expected: []string{"a%b"}},
+		{name: "prefix with dot", query: model.VariablesQueryOptions{KeyPrefix: "a."}, expected: []string{"a.b"}},
+		{name: "prefix with star", query: model.VariablesQueryOptions{KeyPrefix: "a*"}, expected: []string{"a*b"}},
+		{name: "glob star", query: model.VariablesQueryOptions{KeyGlob: "foo*"}, expected: []string{"foo", "foo-bar", "foo-baz", "foobar"}},
+		{name: "glob leading star", query: model.VariablesQueryOptions{KeyGlob: "*bar"}, expected: []string{"bar", "foo-bar", "foobar"}},
+		{name: "glob question mark", query: model.VariablesQueryOptions{KeyGlob: "foo-ba?"}, expected: []string{"foo-bar", "foo-baz"}},
+		{name: "glob single character", query: model.VariablesQueryOptions{KeyGlob: "a?b"}, expected: []string{"a%b", "a*b", "a.b", "aXb", "a_b"}},
+		{name: "glob escaped star", query: model.VariablesQueryOptions{KeyGlob: `a\*b`}, expected: []string{"a*b"}},
+		{name: "glob literal underscore", query: model.VariablesQueryOptions{KeyGlob: "a_b"}, expected: []string{"a_b"}},
+		{name: "glob literal dot", query: model.VariablesQueryOptions{KeyGlob: "a.b"}, expected: []string{"a.b"}},
+		{name: "glob is anchored", query: model.VariablesQueryOptions{KeyGlob: "oo*"}, expected: []string{}},
+		{name: "glob is case-sensitive", query: model.VariablesQueryOptions{KeyGlob: "F*"}, expected: []string{"Foo"}},
+		{name: "regex", query: model.VariablesQueryOptions{KeyRegex: "bar$"}, expected: []string{"bar", "foo-bar", "foobar"}},
+		{name: "regex is unanchored", query: model.VariablesQueryOptions{KeyRegex: "o-b"}, expected: []string{"foo-bar", "foo-baz"}},
+		{name: "regex is case-insensitive", query: model.VariablesQueryOptions{KeyRegex: "^foo"}, expected: []string{"Foo", "foo", "foo-bar", "foo-baz", "foobar"}},
+		{name: "regex case-sensitive", query: model.VariablesQueryOptions{KeyRegex: "^foo", KeyRegexCaseSensitive: true}, expected: []string{"foo", "foo-bar", "foo-baz", "foobar"}},
+		{name: "regex common syntax", query: model.VariablesQueryOptions{KeyRegex: `^fo{2}(?:-|\w)ba[[:alpha:]]$`, KeyRegexCaseSensitive: true}, expected: []string{"foo-bar", "foo-baz"}},
+		{name: "regex escaped punctuation", query: model.VariablesQueryOptions{KeyRegex: `^a\.b$`}, expected: []string{"a.b"}},
+		{name: "prefix and glob", query: model.VariablesQueryOptions{KeyPrefix: "foo", KeyGlob: "*r"}, expected: []string{"foo-bar", "foobar"}},
+		{name: "keys and regex", query: model.VariablesQueryOptions{Keys: []string{"foo", "Foo", "bar"}, KeyRegex: "^f", KeyRegexCaseSensitive: true}, expected: []string{"foo"}},
+		{name: "all modes", query: model.VariablesQueryOptions{Keys: []string{"foo-bar", "foo-baz", "foobar"}, KeyPrefix: "foo-", KeyGlob: "*a?", KeyRegex: "R$"}, expected: []string{"foo-bar"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, err := ctrl.List(ctx, userid, test.query)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range list {
+				keys = append(keys, variable.Key)
+			}
+			sort.Strings(keys)
+			expected := append([]string{}, test.expected...)
+			sort.Strings(expected)
+			if !reflect.DeepEqual(keys, expected) {
+				t.Error(keys, expected)
+			}
+			count, err := ctrl.Count(ctx, userid, test.query)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if count.Count != int64(len(test.expected)) {
+				t.Error(count.Count, len(test.expected))
+			}
+		})
+	}
+
+	t.Run("invalid glob", func(t *testing.T) {
+		_, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{KeyGlob: `a\`})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("invalid regex", func(t *testing.T) {
+		_, err := ctrl.Count(ctx, userid, model.VariablesQueryOptions{KeyRegex: "("})
+		if err == nil {
+			t.Error("expected error")
+		}
+	})
+	t.Run("regex syntax not shared by all databases", func(t *testing.T) {
+		for _, regex := range []string{`\bfoo`, `(?i)foo`, `(?s)a.b`, `(?P<name>foo)`, `\pL`, `\Qa.b\E`, `foo\z`, `[\D]`, `a{256}`} {
+			_, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{KeyRegex: regex})
+			if err == nil {
+				t.Error("expected error", regex)
+			}
+			if _, isClient := ctrl.(*client.Client); !isClient && !errors.Is(err, model.ErrInvalidKeyFilter) {
+				t.Error(regex, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/postgres"
+	"github.com/SENERGY-Platform/process-io-api/pkg/tests/docker"
+)
+
+// legacySchemaSql is the schema created by versions before schema migrations were introduced
+const legacySchemaSql = `CREATE TABLE IF NOT EXISTS variables (
+    user_id VARCHAR ( 50 ) NOT NULL,
+    variable_key VARCHAR ( 255 ) NOT NULL,
+    process_definition_id VARCHAR ( 64 ),
+    process_instance_id VARCHAR ( 64 ),
+    unix_timestamp_in_s INT,
+    variable_value json,
+    PRIMARY KEY (user_id, variable_key)
+);
+INSERT INTO variables (user_id, variable_key, process_definition_id, process_instance_id, unix_timestamp_in_s, variable_value)
+VALUES ('user', 'legacy', 'd1', 'i1', 42, '{"foo": [1, 2]}');`
+
+func TestPostgresMigrations(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, err := configuration.Load("../../config.json")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	config.PostgresConnString, err = docker.Postgres(ctx, wg, "test")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	db, err := sql.Open("postgres", config.PostgresConnString)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer db.Close()
+	_, err = db.ExecContext(ctx, legacySchemaSql)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("migrate legacy schema", func(t *testing.T) {
+		pg, err := postgres.New(ctx, wg, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := pg.GetVariable(ctx, "user", "legacy")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Revision != 1 || variable.UnixTimestampInS != 42 || variable.ProcessInstanceId != "i1" || variable.ExpiresAt != 0 {
+			t.Error(variable)
+		}
+		value, ok := variable.Value.(map[string]interface{})
+		if !ok || len(value["foo"].([]interface{})) != 2 {
+			t.Error(variable.Value)
+		}
+	})
+
+	t.Run("migrations are applied once", func(t *testing.T) {
+		_, err := postgres.New(ctx, wg, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		var count, version int64
+		err = db.QueryRowContext(ctx, `SELECT COUNT(*), MAX(version) FROM schema_migrations`).Scan(&count, &version)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count != int64(len(postgres.Migrations)) || version != postgres.Migrations[len(postgres.Migrations)-1].Version {
+			t.Error(count, version)
+		}
+	})
+
+	t.Run("column types", func(t *testing.T) {
+		expected := map[string]string{
+			"user_id":             "character varying",
+			"unix_timestamp_in_s": "bigint",
+			"variable_value":      "jsonb",
+		}
+		for column, expectedType := range expected {
+			var dataType string
+			err := db.QueryRowContext(ctx, `SELECT data_type FROM information_schema.columns WHERE table_name = 'variables' AND column_name = $1`, column).Scan(&dataType)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if dataType != expectedType {
+				t.Error(column, dataType, expectedType)
+			}
+		}
+		var maxLength int64
+		err := db.QueryRowContext(ctx, `SELECT character_maximum_length FROM information_schema.columns WHERE table_name = 'variables' AND column_name = 'process_instance_id'`).Scan(&maxLength)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if maxLength != 255 {
+			t.Error(maxLength)
+		}
+	})
+
+	t.Run("big timestamps", func(t *testing.T) {
+		pg, err := postgres.New(ctx, wg, config)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := pg.GetVariable(ctx, "user", "legacy")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable.UnixTimestampInS = 1 << 40
+		err = pg.SetVariable(ctx, variable)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err = pg.GetVariable(ctx, "user", "legacy")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.UnixTimestampInS != 1<<40 || variable.Revision != 2 {
+			t.Error(variable)
+		}
+	})
+
+	t.Run("sort indexes", func(t *testing.T) {
+		//the ORDER BY clauses of postgres.getOrderBy for the sortable columns
+		expected := map[string]string{
+			`variable_key COLLATE "C" ASC`:                                        "variables_user_key_c_index",
+			`variable_key COLLATE "C" DESC`:                                       "variables_user_key_c_index",
+			`unix_timestamp_in_s DESC, variable_key COLLATE "C" DESC`:             "variables_user_timestamp_key_c_index",
+			`process_definition_id COLLATE "C" ASC, variable_key COLLATE "C" ASC`: "variables_user_definition_key_c_index",
+			`process_instance_id COLLATE "C" ASC, variable_key COLLATE "C" ASC`:   "variables_user_instance_key_c_index",
+		}
+		for orderBy, index := range expected {
+			plan, err := explainWithoutSort(ctx, db, `SELECT variable_key FROM variables WHERE user_id = 'user' ORDER BY `+orderBy+` LIMIT 10`)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if !strings.Contains(plan, index) || strings.Contains(plan, "Sort") {
+				t.Error(orderBy, plan)
+			}
+		}
+	})
+}
+
+// explainWithoutSort returns the query plan, with sequential scans and sorts disabled as far as possible,
+// so that the plan of the small test table shows, whether an index provides the order
+func explainWithoutSort(ctx context.Context, db *sql.DB, query string) (plan string, err error) {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx, `SET LOCAL enable_seqscan = off; SET LOCAL enable_sort = off;`)
+	if err != nil {
+		return "", err
+	}
+	rows, err := tx.QueryContext(ctx, "EXPLAIN "+query)
+	if err != nil {
+		return "", err
+	}
+	defer rows.Close()
+	lines := []string{}
+	for rows.Next() {
+		var line string
+		err = rows.Scan(&line)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n"), rows.Err()
+}
@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestPatch(t *testing.T) {
+	testWithBackendEnvs(t, nil, func(t *testing.T, env testEnv) {
+		runPatchTests(t, env.ctrl)
+		if env.useClient {
+			runPatchValuesApiTests(t, env.config)
+		}
+	})
+}
+
+func runPatchTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	mergePatch := func(patch string) model.Patch {
+		return model.Patch{Type: model.MergePatchContentType, Patch: json.RawMessage(patch)}
+	}
+	jsonPatch := func(patch string) model.Patch {
+		return model.Patch{Type: model.JsonPatchContentType, Patch: json.RawMessage(patch)}
+	}
+	expectValue := func(t *testing.T, key string, expectedJson string) {
+		t.Helper()
+		var expected interface{}
+		err := json.Unmarshal([]byte(expectedJson), &expected)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, userid, key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		actual, _ := json.Marshal(variable.Value)
+		var normalized interface{}
+		_ = json.Unmarshal(actual, &normalized)
+		if !reflect.DeepEqual(normalized, expected) {
+			t.Error(string(actual), expectedJson)
+		}
+	}
+	expectPatch := func(t *testing.T, key string, patch model.Patch, expectedJson string, expectedRevision int64) {
+		t.Helper()
+		_, revision, err := ctrl.Patch(ctx, userid, key, patch, model.Precondition{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if revision != expectedRevision {
+			t.Error(revision, expectedRevision)
+		}
+		expectValue(t, key, expectedJson)
+	}
+	expectPatchError := func(t *testing.T, key string, patch model.Patch, expectedErr error) {
+		t.Helper()
+		_, _, err := ctrl.Patch(ctx, userid, key, patch, model.Precondition{})
+		if !errors.Is(err, expectedErr) {
+			t.Error(err, expectedErr)
+		}
+	}
+
+	t.Run("merge patch creates missing variable", func(t *testing.T) {
+		expectPatch(t, "config", mergePatch(`{"a": 1, "b": {"c": "foo", "d": [1, 2]}}`), `{"a": 1, "b": {"c": "foo", "d": [1, 2]}}`, 1)
+	})
+
+	t.Run("merge patch", func(t *testing.T) {
+		expectPatch(t, "config", mergePatch(`{"a": null, "b": {"c": "bar", "e": true}}`), `{"b": {"c": "bar", "d": [1, 2], "e": true}}`, 2)
+	})
+
+	t.Run("merge patch keeps process ids", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "instance", Value: map[string]interface{}{"a": 1}, ProcessDefinitionId: "d1", ProcessInstanceId: "i1"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectPatch(t, "instance", mergePatch(`{"b": 2}`), `{"a": 1, "b": 2}`, 2)
+		variable, err := ctrl.Get(ctx, userid, "instance")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.ProcessDefinitionId != "d1" || variable.ProcessInstanceId != "i1" {
+			t.Error(variable)
+		}
+	})
+
+	t.Run("json patch", func(t *testing.T) {
+		expectPatch(t, "config", jsonPatch(`[
+			{"op": "test", "path": "/b/c", "value": "bar"},
+			{"op": "add", "path": "/b/d/1", "value": 42},
+			{"op": "add", "path": "/b/d/-", "value": null},
+			{"op": "remove", "path": "/b/e"},
+			{"op": "replace", "path": "/b/c", "value": {"x": "y"}},
+			{"op": "copy", "from": "/b/c", "path": "/copy"},
+			{"op": "move", "from": "/b/d", "path": "/list"},
+			{"op": "add", "path": "/a~1b", "value": "escaped"}
+		]`), `{"b": {"c": {"x": "y"}}, "copy": {"x": "y"}, "list": [1, 42, 2, null], "a/b": "escaped"}`, 3)
+	})
+
+	t.Run("failing json patch test", func(t *testing.T) {
+		expectPatchError(t, "config", jsonPatch(`[
+			{"op": "add", "path": "/foo", "value": "bar"},
+			{"op": "test", "path": "/copy/x", "value": "z"}
+		]`), model.ErrPatchFailed)
+		expectValue(t, "config", `{"b": {"c": {"x": "y"}}, "copy": {"x": "y"}, "list": [1, 42, 2, null], "a/b": "escaped"}`)
+	})
+
+	t.Run("json patch on missing path", func(t *testing.T) {
+		expectPatchError(t, "config", jsonPatch(`[{"op": "replace", "path": "/unknown", "value": 1}]`), model.ErrPatchFailed)
+		expectPatchError(t, "config", jsonPatch(`[{"op": "add", "path": "/unknown/foo", "value": 1}]`), model.ErrPatchFailed)
+		expectPatchError(t, "config", jsonPatch(`[{"op": "add", "path": "/list/5", "value": 1}]`), model.ErrPatchFailed)
+	})
+
+	t.Run("invalid patches", func(t *testing.T) {
+		expectPatchError(t, "config", jsonPatch(`[{"op": "foo", "path": "/a"}]`), model.ErrInvalidPatch)
+		expectPatchError(t, "config", jsonPatch(`[{"op": "add", "path": "/a"}]`), model.ErrInvalidPatch)
+		expectPatchError(t, "config", jsonPatch(`[{"op": "add", "path": "a", "value": 1}]`), model.ErrInvalidPatch)
+		expectPatchError(t, "config", jsonPatch(`{"op": "add"}`), model.ErrInvalidPatch)
+		expectPatchError(t, "config", model.Patch{Type: "application/json", Patch: json.RawMessage(`{}`)}, model.ErrUnsupportedPatchType)
+		expectValue(t, "config", `{"b": {"c": {"x": "y"}}, "copy": {"x": "y"}, "list": [1, 42, 2, null], "a/b": "escaped"}`)
+	})
+
+	t.Run("patch with precondition", func(t *testing.T) {
+		_, _, err := ctrl.Patch(ctx, userid, "config", mergePatch(`{"foo": "bar"}`), model.Precondition{IfMatch: "2"})
+		if !errors.Is(err, model.ErrPreconditionFailed) {
+			t.Error(err)
+		}
+		_, revision, err := ctrl.Patch(ctx, userid, "config", mergePatch(`{"foo": "bar"}`), model.Precondition{IfMatch: "3"})
+		if err != nil {
+			t.Error(err)
+		}
+		if revision != 4 {
+			t.Error(revision)
+		}
+	})
+
+	t.Run("concurrent merge patches", func(t *testing.T) {
+		const workers = 10
+		wg := sync.WaitGroup{}
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, _, err := ctrl.Patch(ctx, userid, "shared", mergePatch(`{"field`+strconv.Itoa(i)+`": `+strconv.Itoa(i)+`}`), model.Precondition{})
+				if err != nil {
+					t.Error(err)
+				}
+			}(i)
+		}
+		wg.Wait()
+		expected := map[string]interface{}{}
+		for i := 0; i < workers; i++ {
+			expected["field"+strconv.Itoa(i)] = i
+		}
+		temp, _ := json.Marshal(expected)
+		expectValue(t, "shared", string(temp))
+	})
+}
+
+func runPatchValuesApiTests(t *testing.T, config configuration.Config) {
+	patchValue := func(contentType string, body string, expectedStatusCode int, expectedJson string) func(t *testing.T) {
+		return func(t *testing.T) {
+			req, err := http.NewRequest("PATCH", "http://localhost:"+config.ServerPort+"/values/api_patch", bytes.NewBufferString(body))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			req.Header.Set("Authorization", testtoken)
+			req.Header.Set("Content-Type", contentType)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			defer resp.Body.Close()
+			temp, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != expectedStatusCode {
+				t.Error(resp.StatusCode, string(temp))
+				return
+			}
+			if expectedJson != "" && !bytes.Equal(bytes.TrimSpace(temp), []byte(expectedJson)) {
+				t.Error(string(temp), expectedJson)
+			}
+		}
+	}
+	t.Run("patch value with merge patch", patchValue("application/merge-patch+json; charset=utf-8", `{"a": {"b": 1}}`, http.StatusOK, `{"a":{"b":1}}`))
+	t.Run("patch value with json patch", patchValue("application/json-patch+json", `[{"op": "add", "path": "/a/c", "value": 2}]`, http.StatusOK, `{"a":{"b":1,"c":2}}`))
+	t.Run("patch value with failing json patch", patchValue("application/json-patch+json", `[{"op": "test", "path": "/a/c", "value": 3}]`, http.StatusConflict, ""))
+	t.Run("patch value with invalid json patch", patchValue("application/json-patch+json", `[{"op": "foo", "path": "/a/c"}]`, http.StatusBadRequest, ""))
+	t.Run("patch value with unsupported content type", patchValue("application/json", `{}`, http.StatusUnsupportedMediaType, ""))
+	t.Run("get patched value", testRequest(config, "GET", "/values/api_patch", nil, http.StatusOK, map[string]interface{}{"a": map[string]interface{}{"b": 1, "c": 2}}))
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+func TestPointer(t *testing.T) {
+	testWithBackendEnvs(t, nil, func(t *testing.T, env testEnv) {
+		runPointerTests(t, env.ctrl)
+		if env.useClient {
+			t.Run("api pointer", testRequest(env.config, "GET", "/values/doc?pointer="+url.QueryEscape("/settings/threshold"), nil, http.StatusOK, 42))
+			t.Run("api missing pointer", testRequest(env.config, "GET", "/values/doc?pointer="+url.QueryEscape("/settings/unknown"), nil, http.StatusNotFound, nil))
+			t.Run("api invalid pointer", testRequest(env.config, "GET", "/values/doc?pointer=settings", nil, http.StatusBadRequest, nil))
+		}
+	})
+}
+
+func runPointerTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	var doc interface{}
+	err := json.Unmarshal([]byte(`{
+		"settings": {"threshold": 42, "name": "foo", "empty": null, "a.b": "dot", "c/d": "slash", "0": "zero"},
+		"list": [{"id": "first"}, {"id": "second"}]
+	}`), &doc)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	err = ctrl.Set(ctx, userid, model.Variable{Key: "doc", Value: doc})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expectElement := func(pointer string, expected interface{}) func(t *testing.T) {
+		return func(t *testing.T) {
+			value, revision, err := ctrl.GetAtPointer(ctx, userid, "doc", pointer)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if revision != 1 {
+				t.Error(revision)
+			}
+			if !reflect.DeepEqual(value, expected) {
+				t.Errorf("%#v %#v", value, expected)
+			}
+		}
+	}
+	expectError := func(key string, pointer string, expected error) func(t *testing.T) {
+		return func(t *testing.T) {
+			_, _, err := ctrl.GetAtPointer(ctx, userid, key, pointer)
+			if !errors.Is(err, expected) {
+				t.Error(err, expected)
+			}
+		}
+	}
+
+	t.Run("whole document", expectElement("", doc))
+	t.Run("object", expectElement("/settings", doc.(map[string]interface{})["settings"]))
+	t.Run("number", expectElement("/settings/threshold", float64(42)))
+	t.Run("string", expectElement("/settings/name", "foo"))
+	t.Run("null", expectElement("/settings/empty", nil))
+	t.Run("key with dot", expectElement("/settings/a.b", "dot"))
+	t.Run("escaped key", expectElement("/settings/c~1d", "slash"))
+	t.Run("numeric object key", expectElement("/settings/0", "zero"))
+	t.Run("array element", expectElement("/list/1", map[string]interface{}{"id": "second"}))
+	t.Run("array element field", expectElement("/list/0/id", "first"))
+
+	t.Run("missing field", expectError("doc", "/settings/unknown", model.ErrPointerNotFound))
+	t.Run("missing array element", expectError("doc", "/list/2", model.ErrPointerNotFound))
+	t.Run("field of scalar", expectError("doc", "/settings/name/foo", model.ErrPointerNotFound))
+	t.Run("invalid pointer", expectError("doc", "settings", model.ErrInvalidPointer))
+
+	t.Run("unknown variable", func(t *testing.T) {
+		value, revision, err := ctrl.GetAtPointer(ctx, userid, "unknown", "/foo")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != nil || revision != 0 {
+			t.Error(value, revision)
+		}
+	})
+
+	t.Run("calculated value", func(t *testing.T) {
+		value, _, err := ctrl.GetAtPointer(ctx, userid, "calculate_UtcOffset_Europe/Berlin", "")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if _, ok := value.(float64); !ok {
+			t.Errorf("%#v", value)
+		}
+	})
+}
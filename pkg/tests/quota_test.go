@@ -0,0 +1,277 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestQuota(t *testing.T) {
+	testWithBackends(t, func(config *configuration.Config) {
+		config.QuotaMaxVariables = 3
+		config.QuotaMaxValueBytes = 20
+		config.QuotaMaxTotalBytes = 40
+		config.HistoryEnabled = true
+	}, runQuotaTests)
+}
+
+func TestQuotaApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnvWithConfig(ctx, wg, "memory", func(config *configuration.Config) {
+		config.QuotaMaxVariables = 1
+		config.MaxRequestBodyBytes = 100
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("set variable", testRequest(config, "PUT", "/values/a", 1, http.StatusNoContent, nil))
+	t.Run("exceed max_variables", testRequest(config, "PUT", "/values/b", 1, http.StatusTooManyRequests, nil))
+	t.Run("exceed max_request_body_bytes", testRequest(config, "PUT", "/values/a", strings.Repeat("x", 100), http.StatusRequestEntityTooLarge, nil))
+	t.Run("get quota", testRequest(config, "GET", "/quota", nil, http.StatusOK, model.QuotaStatus{
+		Quota: model.Quota{MaxVariables: 1},
+		Usage: model.Usage{Variables: 1, TotalBytes: 1},
+	}))
+	t.Run("set quota as user", testRequest(config, "PUT", "/quota/"+testTokenUser, model.Quota{MaxVariables: 2}, http.StatusForbidden, nil))
+	t.Run("set quota as admin", testRequestWithToken(config, admintoken, "PUT", "/quota/"+testTokenUser, model.Quota{MaxVariables: 2}, http.StatusNoContent, nil))
+	t.Run("get user quota", testRequestWithToken(config, admintoken, "GET", "/quota/"+testTokenUser, nil, http.StatusOK, model.QuotaStatus{
+		Quota:    model.Quota{UserId: testTokenUser, MaxVariables: 2},
+		Usage:    model.Usage{Variables: 1, TotalBytes: 1},
+		Override: true,
+	}))
+	t.Run("set variable with override", testRequest(config, "PUT", "/values/b", 1, http.StatusNoContent, nil))
+	t.Run("delete quota", testRequestWithToken(config, admintoken, "DELETE", "/quota/"+testTokenUser, nil, http.StatusNoContent, nil))
+	t.Run("exceed max_variables after delete", testRequest(config, "PUT", "/values/c", 1, http.StatusTooManyRequests, nil))
+}
+
+// TestMaxRequestBodyBytesMemory sends bodies without Content-Length, which exceed max_request_body_bytes while they are read
+func TestMaxRequestBodyBytesMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, ctrl, err := StartTestEnvWithConfig(ctx, wg, "memory", func(config *configuration.Config) {
+		config.MaxRequestBodyBytes = 100
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	router := api.GetRouter(config, ctrl)
+
+	body := `{"key":"a","value":"` + strings.Repeat("x", 100) + `"}`
+	for _, endpoint := range []struct {
+		method      string
+		path        string
+		contentType string
+	}{
+		{method: "PUT", path: "/values/a"},
+		{method: "PATCH", path: "/values/a", contentType: model.MergePatchContentType},
+		{method: "POST", path: "/increment/values/a"},
+		{method: "PUT", path: "/variables/a"},
+		{method: "PATCH", path: "/variables/a", contentType: model.MergePatchContentType},
+		{method: "POST", path: "/restore/variables/a"},
+		{method: "POST", path: "/bulk"},
+		{method: "POST", path: "/import"},
+		{method: "POST", path: "/shares"},
+		{method: "PUT", path: "/process-definitions/d/values/a"},
+	} {
+		t.Run(endpoint.method+" "+endpoint.path, func(t *testing.T) {
+			request := httptest.NewRequest(endpoint.method, endpoint.path, io.MultiReader(strings.NewReader(body)))
+			request.Header.Set("Authorization", testtoken)
+			if endpoint.contentType != "" {
+				request.Header.Set("Content-Type", endpoint.contentType)
+			}
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, request)
+			if recorder.Code != http.StatusRequestEntityTooLarge {
+				t.Error(recorder.Code, recorder.Body.String())
+			}
+		})
+	}
+}
+
+func runQuotaTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+	value18 := strings.Repeat("x", 18) //20 bytes as json
+
+	t.Run("initial quota", func(t *testing.T) {
+		status, err := ctrl.GetQuota(ctx, userid)
+		expected := model.QuotaStatus{Quota: model.Quota{MaxVariables: 3, MaxValueBytes: 20, MaxTotalBytes: 40}}
+		if err != nil || !reflect.DeepEqual(status, expected) {
+			t.Error(err, status)
+		}
+	})
+
+	for key, value := range map[string]interface{}{"a": "x", "b": 1.0, "c": value18} {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: value})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	t.Run("max_value_bytes", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "a", Value: value18 + "x"})
+		if !errors.Is(err, model.ErrValueTooLarge) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("max_variables", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "d", Value: 1.0})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		_, err = ctrl.Bulk(ctx, userid, model.BulkRequest{Set: []model.BulkSetItem{{Variable: model.Variable{Key: "d", Value: 1.0}}}})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "b", Value: 2.0})
+		if err != nil {
+			t.Error("overwriting existing variables is allowed", err)
+		}
+	})
+
+	t.Run("max_total_bytes", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "b", Value: value18})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "c", Value: "y"})
+		if err != nil {
+			t.Error("shrinking values is allowed", err)
+		}
+	})
+
+	t.Run("increment", func(t *testing.T) {
+		_, _, err := ctrl.Increment(ctx, userid, "d", 1)
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		_, _, err = ctrl.Increment(ctx, userid, "b", 1)
+		if err != nil {
+			t.Error("incrementing existing variables is allowed", err)
+		}
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		_, _, err := ctrl.Patch(ctx, userid, "d", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"x":1}`)}, model.Precondition{})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		_, _, err = ctrl.Patch(ctx, userid, "a", model.Patch{Type: model.MergePatchContentType, Patch: []byte(`{"x":"` + value18 + `"}`)}, model.Precondition{})
+		if !errors.Is(err, model.ErrValueTooLarge) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		//a: 20 bytes, b: 1 byte, c: 3 bytes; restoring the first version of c (20 bytes) exceeds max_total_bytes
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "a", Value: value18})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		_, _, err = ctrl.Restore(ctx, userid, "c", 1, model.Precondition{})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("import", func(t *testing.T) {
+		_, err := ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "d", Value: 1.0}}}, model.ImportConflictOverwrite)
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+		_, err = ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "b", Value: value18 + "x"}}}, model.ImportConflictOverwrite)
+		if !errors.Is(err, model.ErrValueTooLarge) {
+			t.Error(err)
+		}
+		_, err = ctrl.Import(ctx, userid, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "b", Value: value18}}}, model.ImportConflictOverwrite)
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		status, err := ctrl.GetQuota(ctx, userid)
+		if err != nil || status.Usage.Variables != 3 || status.Override {
+			t.Error(err, status)
+		}
+	})
+
+	t.Run("invalid override", func(t *testing.T) {
+		err := ctrl.SetQuota(ctx, adminTokenUser, userid, model.Quota{MaxVariables: -1})
+		if !errors.Is(err, model.ErrInvalidQuota) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("override", func(t *testing.T) {
+		err := ctrl.SetQuota(ctx, adminTokenUser, userid, model.Quota{MaxVariables: 4})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		status, err := ctrl.GetQuota(ctx, userid)
+		if err != nil || !status.Override || status.Quota != (model.Quota{UserId: userid, MaxVariables: 4}) {
+			t.Error(err, status)
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "d", Value: value18 + value18})
+		if err != nil {
+			t.Error(err)
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "e", Value: 1.0})
+		if !errors.Is(err, model.ErrQuotaExceeded) {
+			t.Error(err)
+		}
+	})
+
+	t.Run("delete override", func(t *testing.T) {
+		err := ctrl.DeleteQuota(ctx, adminTokenUser, userid)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		status, err := ctrl.GetQuota(ctx, userid)
+		if err != nil || status.Override || status.Quota.MaxVariables != 3 {
+			t.Error(err, status)
+		}
+	})
+}
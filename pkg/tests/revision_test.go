@@ -0,0 +1,200 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestRevision(t *testing.T) {
+	testWithBackends(t, nil, runRevisionTests)
+}
+
+func runRevisionTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	expectRevision := func(t *testing.T, key string, expectedValue interface{}, expectedRevision int64) {
+		t.Helper()
+		variable, revision, err := ctrl.GetWithRevision(ctx, userid, key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if revision != expectedRevision {
+			t.Error(revision, expectedRevision)
+		}
+		if variable.Value != expectedValue {
+			t.Error(variable.Value, expectedValue)
+		}
+	}
+
+	expectPreconditionFailed := func(t *testing.T, err error) {
+		t.Helper()
+		if !errors.Is(err, model.ErrPreconditionFailed) {
+			t.Error(err)
+		}
+	}
+
+	t.Run("unknown variable has no revision", func(t *testing.T) {
+		expectRevision(t, "r1", nil, 0)
+	})
+
+	t.Run("if-match on unknown variable", func(t *testing.T) {
+		expectPreconditionFailed(t, ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "foo"}, model.Precondition{IfMatch: "*"}))
+		expectRevision(t, "r1", nil, 0)
+	})
+
+	t.Run("create with if-none-match", func(t *testing.T) {
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "foo"}, model.Precondition{IfNoneMatch: "*"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r1", "foo", 1)
+	})
+
+	t.Run("if-none-match on existing variable", func(t *testing.T) {
+		expectPreconditionFailed(t, ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "bar"}, model.Precondition{IfNoneMatch: "*"}))
+		expectRevision(t, "r1", "foo", 1)
+	})
+
+	t.Run("blind set increments revision", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "r1", Value: "bar"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r1", "bar", 2)
+	})
+
+	t.Run("if-match with outdated revision", func(t *testing.T) {
+		expectPreconditionFailed(t, ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "batz"}, model.Precondition{IfMatch: model.RevisionToETag(1)}))
+		expectRevision(t, "r1", "bar", 2)
+	})
+
+	t.Run("if-match with current revision", func(t *testing.T) {
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "batz"}, model.Precondition{IfMatch: model.RevisionToETag(2)})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r1", "batz", 3)
+	})
+
+	t.Run("if-match with revision list", func(t *testing.T) {
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "42"}, model.Precondition{IfMatch: "1, 3"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r1", "42", 4)
+	})
+
+	t.Run("invalid precondition", func(t *testing.T) {
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r1", Value: "foo"}, model.Precondition{IfMatch: "foo"})
+		if err == nil {
+			t.Error("expected error")
+		}
+		expectRevision(t, "r1", "42", 4)
+	})
+
+	t.Run("bulk with precondition", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set: []model.BulkSetItem{
+				{Variable: model.Variable{Key: "r2", Value: "foo"}, Precondition: model.Precondition{IfNoneMatch: "*"}},
+				{Variable: model.Variable{Key: "r1", Value: "foo"}, Precondition: model.Precondition{IfMatch: "3"}},
+			},
+			Atomic: true,
+		})
+		expectPreconditionFailed(t, err)
+		expectRevision(t, "r1", "42", 4)
+		expectRevision(t, "r2", nil, 0)
+
+		_, err = ctrl.Bulk(ctx, userid, model.BulkRequest{
+			Set: []model.BulkSetItem{
+				{Variable: model.Variable{Key: "r2", Value: "foo"}, Precondition: model.Precondition{IfNoneMatch: "*"}},
+				{Variable: model.Variable{Key: "r1", Value: "foo"}, Precondition: model.Precondition{IfMatch: "4"}},
+			},
+			Atomic: true,
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r1", "foo", 5)
+		expectRevision(t, "r2", "foo", 1)
+	})
+
+	t.Run("stale if-match after delete and recreate", func(t *testing.T) {
+		err := ctrl.Delete(ctx, userid, "r2")
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "r2", Value: "bar"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectRevision(t, "r2", "bar", 2)
+		expectPreconditionFailed(t, ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "r2", Value: "batz"}, model.Precondition{IfMatch: model.RevisionToETag(1)}))
+		expectRevision(t, "r2", "bar", 2)
+	})
+
+	t.Run("concurrent compare and set", func(t *testing.T) {
+		const workers = 5
+		const increments = 5
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "counter", Value: float64(0)})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		wg := sync.WaitGroup{}
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for done := 0; done < increments; {
+					variable, revision, err := ctrl.GetWithRevision(ctx, userid, "counter")
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					count, _ := variable.Value.(float64)
+					err = ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "counter", Value: count + 1}, model.Precondition{IfMatch: strconv.FormatInt(revision, 10)})
+					if errors.Is(err, model.ErrPreconditionFailed) {
+						continue
+					}
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					done++
+				}
+			}()
+		}
+		wg.Wait()
+		expectRevision(t, "counter", float64(workers*increments), workers*increments+1)
+	})
+}
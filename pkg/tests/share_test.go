@@ -0,0 +1,270 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestShare(t *testing.T) {
+	testWithBackends(t, nil, runShareTests)
+}
+
+func runShareTests(t *testing.T, ctrl api.Controller) {
+	//the controller reads the groups from the context; the api sets them from the roles of the jwt
+	ctx := model.WithGroups(context.Background(), []string{"user"})
+	adminCtx := model.WithGroups(context.Background(), []string{"user", "admin"})
+	owner := testTokenUser
+	reader := secendOwnerTokenUser
+
+	for key, value := range map[string]interface{}{"plant1/a": 1.0, "plant1/b": 2.0, "other": 3.0} {
+		err := ctrl.Set(ctx, owner, model.Variable{Key: key, Value: value})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	err := ctrl.Set(ctx, reader, model.Variable{Key: "own", Value: 4.0})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("invalid share", func(t *testing.T) {
+		_, err := ctrl.CreateShare(ctx, owner, model.Share{UserId: reader})
+		if !errors.Is(err, model.ErrInvalidShare) {
+			t.Error(err)
+		}
+		_, err = ctrl.CreateShare(ctx, owner, model.Share{Key: "other", UserId: owner})
+		if !errors.Is(err, model.ErrInvalidShare) {
+			t.Error(err)
+		}
+	})
+
+	prefixShare, err := ctrl.CreateShare(ctx, owner, model.Share{KeyPrefix: "plant1/", UserId: reader})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if prefixShare.Id == "" || prefixShare.OwnerId != owner {
+		t.Error(prefixShare)
+	}
+	_, err = ctrl.CreateShare(ctx, owner, model.Share{Key: "other", Group: "admin", Write: true})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("list shares", func(t *testing.T) {
+		shares, err := ctrl.ListShares(ctx, owner)
+		if err != nil || len(shares) != 2 {
+			t.Error(err, shares)
+		}
+		received, err := ctrl.ListReceivedShares(ctx, reader)
+		if err != nil || !reflect.DeepEqual(received, []model.Share{prefixShare}) {
+			t.Error(err, received)
+		}
+		received, err = ctrl.ListReceivedShares(adminCtx, adminTokenUser)
+		if err != nil || len(received) != 1 || received[0].Key != "other" {
+			t.Error(err, received)
+		}
+	})
+
+	t.Run("read shared", func(t *testing.T) {
+		variable, err := ctrl.Get(ctx, reader, "plant1/a")
+		if err != nil || variable.Value != 1.0 || variable.Owner != owner {
+			t.Error(err, variable)
+		}
+		variable, err = ctrl.Get(ctx, reader, "other")
+		if err != nil || variable.Value != nil {
+			t.Error(err, variable)
+		}
+		variable, err = ctrl.Get(ctx, owner, "plant1/a")
+		if err != nil || variable.Value != 1.0 || variable.Owner != "" {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("list shared", func(t *testing.T) {
+		list, err := ctrl.List(ctx, reader, model.VariablesQueryOptions{Sort: "key.asc"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		keys := []string{}
+		owners := []string{}
+		for _, variable := range list {
+			keys = append(keys, variable.Key)
+			owners = append(owners, variable.Owner)
+		}
+		if !reflect.DeepEqual(keys, []string{"own", "plant1/a", "plant1/b"}) || !reflect.DeepEqual(owners, []string{"", owner, owner}) {
+			t.Error(keys, owners)
+		}
+		list, err = ctrl.List(ctx, reader, model.VariablesQueryOptions{Sort: "key.desc", Limit: 1, Offset: 1})
+		if err != nil || len(list) != 1 || list[0].Key != "plant1/a" {
+			t.Error(err, list)
+		}
+		count, err := ctrl.Count(ctx, reader, model.VariablesQueryOptions{})
+		if err != nil || count.Count != 3 {
+			t.Error(err, count)
+		}
+		count, err = ctrl.Count(ctx, reader, model.VariablesQueryOptions{KeyPrefix: "plant1/b"})
+		if err != nil || count.Count != 1 {
+			t.Error(err, count)
+		}
+	})
+
+	t.Run("write read-only share", func(t *testing.T) {
+		//the reader creates an own variable, which hides the shared variable
+		err := ctrl.Set(ctx, reader, model.Variable{Key: "plant1/b", Value: 10.0})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		value, _, err := ctrl.Increment(ctx, reader, "plant1/b", 1)
+		if err != nil || value != 11.0 {
+			t.Error(err, value)
+		}
+		variable, err := ctrl.Get(ctx, reader, "plant1/b")
+		if err != nil || variable.Value != 11.0 || variable.Owner != "" {
+			t.Error(err, variable)
+		}
+		variable, err = ctrl.Get(ctx, owner, "plant1/b")
+		if err != nil || variable.Value != 2.0 {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("write shared with group", func(t *testing.T) {
+		err := ctrl.Set(adminCtx, adminTokenUser, model.Variable{Key: "other", Value: 5.0})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		value, _, err := ctrl.Increment(adminCtx, adminTokenUser, "other", 1)
+		if err != nil || value != 6.0 {
+			t.Error(err, value)
+		}
+		variable, err := ctrl.Get(ctx, owner, "other")
+		if err != nil || variable.Value != 6.0 {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("write shared by multiple owners", func(t *testing.T) {
+		//the admin shares read-only and has the lower id, the owner shares writable
+		for _, sharer := range []string{adminTokenUser, owner} {
+			err := ctrl.Set(ctx, sharer, model.Variable{Key: "multi", Value: sharer})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			_, err = ctrl.CreateShare(ctx, sharer, model.Share{Key: "multi", UserId: reader, Write: sharer == owner})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		err := ctrl.Set(ctx, reader, model.Variable{Key: "multi", Value: "written"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, owner, "multi")
+		if err != nil || variable.Value != "written" {
+			t.Error(err, variable)
+		}
+		variable, err = ctrl.Get(ctx, reader, "multi")
+		if err != nil || variable.Value != "written" || variable.Owner != owner {
+			t.Error(err, variable)
+		}
+		variable, err = ctrl.Get(ctx, adminTokenUser, "multi")
+		if err != nil || variable.Value != adminTokenUser {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("create in shared prefix", func(t *testing.T) {
+		err := ctrl.Set(ctx, reader, model.Variable{Key: "plant1/c", Value: 7.0})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, owner, "plant1/c")
+		if err != nil || variable.Value != nil {
+			t.Error(err, variable)
+		}
+	})
+
+	t.Run("page shared with equal keys", func(t *testing.T) {
+		//the reader has an own and a shared "plant1/b", "multi" is shared by two owners
+		for _, sort := range []string{"key.asc", "key.desc", "unix_timestamp_in_s.asc"} {
+			expected, err := ctrl.List(ctx, reader, model.VariablesQueryOptions{Sort: sort})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if len(expected) != 7 {
+				t.Error(sort, expected)
+				return
+			}
+			actual := []model.VariableWithUnixTimestamp{}
+			query := model.VariablesQueryOptions{Sort: sort, Limit: 1}
+			for range expected {
+				page, err := ctrl.List(ctx, reader, query)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				actual = append(actual, page...)
+				query.Cursor = model.NextVariablesCursor(query, page)
+				if query.Cursor == "" {
+					break
+				}
+			}
+			if !reflect.DeepEqual(actual, expected) {
+				t.Error(sort, actual, expected)
+			}
+		}
+		listing, err := ctrl.Browse(ctx, reader, model.VariablesQueryOptions{KeyPrefix: "plant1/"}, "/")
+		if err != nil || len(listing.Variables) != 4 {
+			t.Error(err, listing)
+		}
+	})
+
+	t.Run("delete share", func(t *testing.T) {
+		err := ctrl.DeleteShare(ctx, reader, prefixShare.Id)
+		if !errors.Is(err, model.ErrShareNotFound) {
+			t.Error(err)
+		}
+		err = ctrl.DeleteShare(ctx, owner, prefixShare.Id)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		variable, err := ctrl.Get(ctx, reader, "plant1/a")
+		if err != nil || variable.Value != nil {
+			t.Error(err, variable)
+		}
+	})
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/api/client"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestSort(t *testing.T) {
+	testWithBackends(t, nil, runSortTests)
+}
+
+func TestSortApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("multiple fields", testRequest(config, "GET", "/variables?sort=process_instance_id.asc,timestamp.desc", nil, http.StatusOK, []interface{}{}))
+	t.Run("unknown field", testRequest(config, "GET", "/variables?sort=foo.asc", nil, http.StatusBadRequest, nil))
+	t.Run("sql", testRequest(config, "GET", "/variables?sort=variable_key%3B%20DROP%20TABLE%20variables", nil, http.StatusBadRequest, nil))
+	t.Run("bson path", testRequest(config, "GET", "/variables?sort=variablewithunixtimestamp.variable.key.asc", nil, http.StatusBadRequest, nil))
+	t.Run("repeated field", testRequest(config, "GET", "/variables?sort=key.asc,variable_key.desc", nil, http.StatusBadRequest, nil))
+	t.Run("empty field", testRequest(config, "GET", "/variables?sort=key.asc,", nil, http.StatusBadRequest, nil))
+}
+
+func runSortTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	for i := 0; i < 8; i++ {
+		configuration.TimeNow = func() time.Time {
+			return time.Unix(int64(1000+i%4), 0)
+		}
+		err := ctrl.Set(ctx, userid, model.Variable{
+			Key:               fmt.Sprintf("v%v", i),
+			Value:             map[string]interface{}{"n": float64(i % 2)},
+			ProcessInstanceId: fmt.Sprintf("i%v", i%3),
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	configuration.TimeNow = backup
+
+	//v0: i0 1000 0, v1: i1 1001 1, v2: i2 1002 0, v3: i0 1003 1, v4: i1 1000 0, v5: i2 1001 1, v6: i0 1002 0, v7: i1 1003 1
+	expectedKeys := map[string][]string{
+		"":                                       {"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"},
+		"key":                                    {"v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"},
+		"variable_key.desc":                      {"v7", "v6", "v5", "v4", "v3", "v2", "v1", "v0"},
+		"timestamp.asc":                          {"v0", "v4", "v1", "v5", "v2", "v6", "v3", "v7"},
+		"unix_timestamp_in_s.desc":               {"v7", "v3", "v6", "v2", "v5", "v1", "v4", "v0"},
+		"process_instance_id.asc,timestamp.desc": {"v3", "v6", "v0", "v7", "v1", "v4", "v2", "v5"},
+		"process_instance_id.desc,key.asc":       {"v2", "v5", "v1", "v4", "v7", "v0", "v3", "v6"},
+		"value/n.desc,unix_timestamp_in_s.asc":   {"v1", "v5", "v3", "v7", "v0", "v4", "v2", "v6"},
+		"key.desc,process_instance_id.asc":       {"v7", "v6", "v5", "v4", "v3", "v2", "v1", "v0"},
+	}
+
+	for sort, expected := range expectedKeys {
+		t.Run("sort "+sort, func(t *testing.T) {
+			list, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: sort})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range list {
+				keys = append(keys, variable.Key)
+			}
+			if !reflect.DeepEqual(keys, expected) {
+				t.Error(keys, expected)
+			}
+			if model.NextVariablesCursor(model.VariablesQueryOptions{Sort: sort, Limit: 3}, list[:3]) == "" {
+				return //value sorts have no cursor
+			}
+			for _, limit := range []int{1, 3} {
+				query := model.VariablesQueryOptions{Sort: sort, Limit: limit}
+				keys = []string{}
+				for page := 0; page < 10; page++ {
+					list, err = ctrl.List(ctx, userid, query)
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					for _, variable := range list {
+						keys = append(keys, variable.Key)
+					}
+					query.Cursor = model.NextVariablesCursor(query, list)
+					if query.Cursor == "" {
+						break
+					}
+				}
+				if !reflect.DeepEqual(keys, expected) {
+					t.Error("cursor", limit, keys, expected)
+				}
+			}
+		})
+	}
+
+	t.Run("mixed case", func(t *testing.T) {
+		//strings are ordered by bytes in all databases, independent of the collation of the database
+		for _, key := range []string{"a", "B"} {
+			err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: map[string]interface{}{"s": key}})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+		}
+		expected := map[string][]string{
+			"key.asc":      {"B", "a", "v0", "v1", "v2", "v3", "v4", "v5", "v6", "v7"},
+			"value/s.desc": {"a", "B", "v7", "v6", "v5", "v4", "v3", "v2", "v1", "v0"},
+		}
+		for sort, expectedKeys := range expected {
+			list, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: sort})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range list {
+				keys = append(keys, variable.Key)
+			}
+			if !reflect.DeepEqual(keys, expectedKeys) {
+				t.Error(sort, keys, expectedKeys)
+			}
+		}
+		query := model.VariablesQueryOptions{Sort: "key.asc", Limit: 1}
+		keys := []string{}
+		for page := 0; page < 3; page++ {
+			list, err := ctrl.List(ctx, userid, query)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			for _, variable := range list {
+				keys = append(keys, variable.Key)
+			}
+			query.Cursor = model.NextVariablesCursor(query, list)
+		}
+		if !reflect.DeepEqual(keys, []string{"B", "a", "v0"}) {
+			t.Error("cursor", keys)
+		}
+	})
+
+	t.Run("invalid sort", func(t *testing.T) {
+		for _, sort := range []string{"foo", "key.asc,key.desc", "key; DROP TABLE variables", "process_instance_id.asc,", "values/n"} {
+			_, err := ctrl.List(ctx, userid, model.VariablesQueryOptions{Sort: sort})
+			if err == nil {
+				t.Error("expected error", sort)
+			}
+			if _, isClient := ctrl.(*client.Client); !isClient && !errors.Is(err, model.ErrInvalidSort) {
+				t.Error(sort, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,276 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+	"github.com/SENERGY-Platform/process-io-api/pkg/transfer"
+)
+
+func TestTransferMongoToPostgres(t *testing.T) {
+	testTransfer(t, "mongodb", "postgres")
+}
+
+func TestTransferMemoryToBolt(t *testing.T) {
+	testTransfer(t, "memory", "bolt")
+}
+
+func TestTransferBoltToMemory(t *testing.T) {
+	testTransfer(t, "bolt", "memory")
+}
+
+func newTestDatabase(ctx context.Context, wg *sync.WaitGroup, dbSelection string) (database.Database, error) {
+	config, err := NewTestConfig(ctx, wg, dbSelection)
+	if err != nil {
+		return nil, err
+	}
+	return database.New(ctx, wg, config)
+}
+
+func testTransfer(t *testing.T, sourceSelection string, targetSelection string) {
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := newTestDatabase(ctx, wg, sourceSelection)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	expected := []model.VariableWithUser{}
+	for _, userId := range []string{"user1", "user2", "user3"} {
+		for i := 0; i < 7; i++ {
+			variable := model.VariableWithUser{
+				VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{
+					Variable: model.Variable{
+						Key:                 "key" + strconv.Itoa(i),
+						Value:               map[string]interface{}{"n": float64(i), "user": userId},
+						ProcessDefinitionId: "d" + strconv.Itoa(i%2),
+						ProcessInstanceId:   "i" + strconv.Itoa(i),
+					},
+					UnixTimestampInS: int64(100 + i),
+				},
+				UserId: userId,
+			}
+			if i == 3 {
+				variable.ExpiresAt = 2000
+			}
+			err = source.SetVariable(ctx, variable)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			expected = append(expected, variable)
+		}
+	}
+	err = source.SetVariable(ctx, model.VariableWithUser{
+		VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "expired", Value: 1, ExpiresAt: 500}},
+		UserId:                    "user1",
+	})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	dir := t.TempDir()
+	dumpFile := filepath.Join(dir, "dump.ndjson")
+	options := transfer.Options{BatchSize: 3, ProgressInterval: 5}
+
+	t.Run("dump", func(t *testing.T) {
+		stats, err := transfer.Dump(ctx, source, dumpFile, options)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stats.Users != 3 || stats.Variables != 21 || stats.Skipped != 0 {
+			t.Errorf("%#v", stats)
+		}
+		mismatches, err := transfer.VerifyDump(ctx, source, dumpFile)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(mismatches) > 0 {
+			t.Errorf("%#v", mismatches)
+		}
+	})
+
+	t.Run("resume dump", func(t *testing.T) {
+		complete, err := os.ReadFile(dumpFile)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		lines := bytes.SplitAfter(complete, []byte("\n"))
+		//interrupted in the middle of the second user, with a partially written line
+		partial := append(bytes.Join(lines[:10], nil), lines[10][:5]...)
+		resumedFile := filepath.Join(dir, "resumed.ndjson")
+		err = os.WriteFile(resumedFile, partial, 0600)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		stats, err := transfer.Dump(ctx, source, resumedFile, transfer.Options{BatchSize: 3, ProgressInterval: 5, Resume: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stats.Users != 3 || stats.Variables != 11 || stats.Skipped != 10 {
+			t.Errorf("%#v", stats)
+		}
+		resumed, err := os.ReadFile(resumedFile)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !bytes.Equal(complete, resumed) {
+			t.Errorf("\n%v\n%v", string(complete), string(resumed))
+		}
+	})
+
+	t.Run("restore", func(t *testing.T) {
+		target, err := newTestDatabase(ctx, wg, targetSelection)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		stats, err := transfer.Restore(ctx, target, dumpFile, options)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stats.Users != 3 || stats.Variables != 21 {
+			t.Errorf("%#v", stats)
+		}
+		checkTransferTarget(t, target, expected)
+
+		stats, err = transfer.Restore(ctx, target, dumpFile, transfer.Options{Resume: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stats.Variables != 0 || stats.Skipped != 21 {
+			t.Errorf("%#v", stats)
+		}
+		checkTransferTarget(t, target, expected)
+	})
+
+	t.Run("copy", func(t *testing.T) {
+		target, err := newTestDatabase(ctx, wg, targetSelection)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		//state of an interrupted copy
+		err = target.SetVariable(ctx, expected[0])
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		mismatches, err := transfer.Verify(ctx, source, target)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if !reflect.DeepEqual(mismatches, []transfer.Mismatch{
+			{UserId: "user1", Expected: 7, Actual: 1},
+			{UserId: "user2", Expected: 7, Actual: 0},
+			{UserId: "user3", Expected: 7, Actual: 0},
+		}) {
+			t.Errorf("%#v", mismatches)
+		}
+
+		stats, err := transfer.Copy(ctx, source, target, transfer.Options{BatchSize: 3, Resume: true})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if stats.Users != 3 || stats.Variables != 20 || stats.Skipped != 1 {
+			t.Errorf("%#v", stats)
+		}
+		checkTransferTarget(t, target, expected)
+		mismatches, err = transfer.Verify(ctx, source, target)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(mismatches) > 0 {
+			t.Errorf("%#v", mismatches)
+		}
+	})
+}
+
+func checkTransferTarget(t *testing.T, target database.Database, expected []model.VariableWithUser) {
+	t.Helper()
+	ctx := context.Background()
+	userIds, err := target.ListUserIds(ctx)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	if !reflect.DeepEqual(userIds, []string{"user1", "user2", "user3"}) {
+		t.Errorf("%#v", userIds)
+	}
+	for _, variable := range expected {
+		actual, err := target.GetVariable(ctx, variable.UserId, variable.Key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if actual.Revision != 1 {
+			t.Errorf("%#v", actual)
+		}
+		actual.Revision = 0
+		if !reflect.DeepEqual(actual, variable) {
+			t.Errorf("\n%#v\n%#v", actual, variable)
+		}
+	}
+	list, err := target.ListVariables(ctx, "user1", model.VariablesQueryOptions{})
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	keys := []string{}
+	for _, variable := range list {
+		keys = append(keys, variable.Key)
+	}
+	sort.Strings(keys)
+	if !reflect.DeepEqual(keys, []string{"key0", "key1", "key2", "key3", "key4", "key5", "key6"}) {
+		t.Errorf("%#v", keys)
+	}
+}
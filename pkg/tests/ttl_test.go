@@ -0,0 +1,258 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestTtl(t *testing.T) {
+	now := atomic.Int64{}
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(now.Load(), 0)
+	}
+
+	testWithBackendEnvs(t, func(config *configuration.Config) {
+		config.HistoryEnabled = true
+	}, func(t *testing.T, env testEnv) {
+		now.Store(1000)
+		runTtlTests(t, env.ctrl, &now)
+		if env.useClient {
+			now.Store(2000)
+			t.Run("api set value with ttl", testRequest(env.config, "PUT", "/values/v1?ttl_in_s=5", "foo", http.StatusNoContent, nil))
+			t.Run("api set value with expires_at", testRequest(env.config, "PUT", "/process-definitions/d1/values/v2?expires_at=2010", "bar", http.StatusNoContent, nil))
+			t.Run("api get value", testRequest(env.config, "GET", "/values/v1", nil, http.StatusOK, "foo"))
+			t.Run("api get variable", testRequest(env.config, "GET", "/variables/v2", nil, http.StatusOK, model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "v2", Value: "bar", ProcessDefinitionId: "d1", ExpiresAt: 2010}, UnixTimestampInS: 2000}))
+			t.Run("api negative ttl", testRequest(env.config, "PUT", "/values/v1?ttl_in_s=-1", "foo", http.StatusBadRequest, nil))
+			t.Run("api invalid expires_at", testRequest(env.config, "PUT", "/values/v1?expires_at=foo", "foo", http.StatusBadRequest, nil))
+			t.Run("api variable ttl overwrites body", testRequest(env.config, "PUT", "/variables/v3?ttl_in_s=1", model.Variable{Key: "v3", Value: "baz", ExpiresAt: 3000}, http.StatusNoContent, nil))
+			now.Store(2005)
+			t.Run("api get expired value", testRequest(env.config, "GET", "/values/v1", nil, http.StatusOK, nil))
+			t.Run("api get expired variable", testRequest(env.config, "GET", "/variables/v3", nil, http.StatusOK, model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "v3"}}))
+			t.Run("api get not expired variable", testRequest(env.config, "GET", "/values/v2", nil, http.StatusOK, "bar"))
+		}
+	})
+}
+
+func runTtlTests(t *testing.T, ctrl api.Controller, now *atomic.Int64) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	expectVariable := func(t *testing.T, key string, expectedValue interface{}, expectedRevision int64, expectedExpiresAt int64) {
+		t.Helper()
+		variable, revision, err := ctrl.GetWithRevision(ctx, userid, key)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if variable.Value != expectedValue || revision != expectedRevision || variable.ExpiresAt != expectedExpiresAt {
+			t.Error(variable, revision, expectedValue, expectedRevision, expectedExpiresAt)
+		}
+	}
+	expectCount := func(t *testing.T, expected int64) {
+		t.Helper()
+		query := model.VariablesQueryOptions{KeyRegex: "^t"}
+		count, err := ctrl.Count(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		list, err := ctrl.List(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count.Count != expected || int64(len(list)) != expected {
+			t.Error(count, list, expected)
+		}
+	}
+
+	t.Run("set with expiry", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "t1", Value: "v1", ExpiresAt: 1010})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		err = ctrl.Set(ctx, userid, model.Variable{Key: "t1", Value: "v2", ExpiresAt: 1010})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectVariable(t, "t1", "v2", 2, 1010)
+	})
+
+	t.Run("bulk with ttl", func(t *testing.T) {
+		_, err := ctrl.Bulk(ctx, userid, model.BulkRequest{Set: []model.BulkSetItem{{Variable: model.Variable{Key: "t2", Value: "v1"}, TtlInS: 5}}})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectVariable(t, "t2", "v1", 1, 1005)
+		expectCount(t, 2)
+	})
+
+	t.Run("increment keeps expiry", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "t3", Value: 1, ExpiresAt: 1010})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		value, revision, err := ctrl.Increment(ctx, userid, "t3", 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != float64(3) || revision != 2 {
+			t.Error(value, revision)
+		}
+		expectVariable(t, "t3", float64(3), 2, 1010)
+	})
+
+	t.Run("invalid expiry", func(t *testing.T) {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: "t4", Value: "v1", ExpiresAt: -1})
+		if err == nil {
+			t.Error("expected error")
+		}
+		_, err = ctrl.Bulk(ctx, userid, model.BulkRequest{Set: []model.BulkSetItem{{Variable: model.Variable{Key: "t4", Value: "v1"}, TtlInS: -1}}})
+		if err == nil {
+			t.Error("expected error")
+		}
+		expectVariable(t, "t4", nil, 0, 0)
+	})
+
+	t.Run("expired variables behave as missing", func(t *testing.T) {
+		now.Store(1005)
+		expectVariable(t, "t2", nil, 0, 0)
+		expectVariable(t, "t1", "v2", 2, 1010)
+		expectCount(t, 2)
+
+		now.Store(1010)
+		expectVariable(t, "t1", nil, 0, 0)
+		expectVariable(t, "t3", nil, 0, 0)
+		expectCount(t, 0)
+		value, revision, err := ctrl.GetAtPointer(ctx, userid, "t1", "")
+		if err != nil || value != nil || revision != 0 {
+			t.Error(value, revision, err)
+		}
+	})
+
+	t.Run("write after expiry starts new history with higher revision", func(t *testing.T) {
+		err := ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "t1", Value: "v3"}, model.Precondition{IfNoneMatch: "*"})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		expectVariable(t, "t1", "v3", 3, 0)
+		err = ctrl.CompareAndSet(ctx, userid, model.Variable{Key: "t1", Value: "v4"}, model.Precondition{IfMatch: model.RevisionToETag(1)})
+		if !errors.Is(err, model.ErrPreconditionFailed) {
+			t.Error(err)
+		}
+		history, err := ctrl.History(ctx, userid, "t1", model.HistoryQueryOptions{})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if len(history) != 1 || history[0].Value != "v3" || history[0].Revision != 3 {
+			t.Error(history)
+		}
+
+		value, revision, err := ctrl.Increment(ctx, userid, "t3", 2)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if value != float64(2) || revision != 3 {
+			t.Error(value, revision)
+		}
+		expectVariable(t, "t3", float64(2), 3, 0)
+	})
+}
+
+func TestExpirySweeper(t *testing.T) {
+	now := atomic.Int64{}
+	now.Store(1000)
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(now.Load(), 0)
+	}
+
+	for _, dbSelection := range []string{"memory", "bolt"} {
+		t.Run(dbSelection, func(t *testing.T) {
+			wg := &sync.WaitGroup{}
+			defer wg.Wait()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			config, err := NewTestConfig(ctx, wg, dbSelection)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			config.ExpirySweepInterval = "10ms"
+			db, err := database.New(ctx, wg, config)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			now.Store(1000)
+			variable := model.VariableWithUser{VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: "a", Value: "foo", ExpiresAt: 1005}}, UserId: testTokenUser}
+			err = db.SetVariable(ctx, variable)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			now.Store(1010)
+			//ListUserIds includes users with expired variables, which are not yet deleted
+			var userIds []string
+			for i := 0; i < 100; i++ {
+				userIds, err = db.ListUserIds(ctx)
+				if err != nil || len(userIds) == 0 {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			if err != nil || len(userIds) != 0 {
+				t.Error(userIds, err)
+				return
+			}
+			variable.ExpiresAt = 0
+			err = db.SetVariable(ctx, variable)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			current, err := db.GetVariable(ctx, testTokenUser, "a")
+			if err != nil || current.Revision != 2 {
+				t.Error(current, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database/util"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// conflictingDatabase changes the variable between every read and write
+type conflictingDatabase struct {
+	reads int
+}
+
+func (this *conflictingDatabase) GetVariable(ctx context.Context, userId string, key string) (model.VariableWithUser, error) {
+	this.reads++
+	return model.VariableWithUser{VariableWithUnixTimestamp: model.VariableWithUnixTimestamp{Variable: model.Variable{Key: key}}, UserId: userId, Revision: int64(this.reads)}, nil
+}
+
+func (this *conflictingDatabase) SetVariableIfRevision(ctx context.Context, variable model.VariableWithUser, revision int64) (int64, bool, error) {
+	return 0, false, nil
+}
+
+func TestUpdateVariableConflict(t *testing.T) {
+	db := &conflictingDatabase{}
+	_, err := util.UpdateVariable(context.Background(), db, testTokenUser, "a", func(current model.VariableWithUser) (model.VariableWithUser, error) {
+		current.Value = 1.0
+		return current, nil
+	})
+	if !errors.Is(err, model.ErrUpdateConflict) {
+		t.Error(err)
+	}
+	if db.reads < 2 || db.reads > 100 {
+		t.Error(db.reads)
+	}
+}
@@ -0,0 +1,122 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestUpdated(t *testing.T) {
+	testWithBackends(t, nil, runUpdatedTests)
+}
+
+func TestUpdatedApiMemory(t *testing.T) {
+	wg := &sync.WaitGroup{}
+	defer wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	config, _, err := StartTestEnv(ctx, wg, "memory")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	t.Run("list", testRequest(config, "GET", "/variables?updated_after=1000&updated_before=2000", nil, http.StatusOK, []interface{}{}))
+	t.Run("count", testRequest(config, "GET", "/count/variables?updated_after=1000&updated_before=2000", nil, http.StatusOK, model.Count{Count: 0}))
+	t.Run("invalid list after", testRequest(config, "GET", "/variables?updated_after=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid list before", testRequest(config, "GET", "/variables?updated_before=1.5", nil, http.StatusBadRequest, nil))
+	t.Run("invalid count after", testRequest(config, "GET", "/count/variables?updated_after=foo", nil, http.StatusBadRequest, nil))
+	t.Run("invalid count before", testRequest(config, "GET", "/count/variables?updated_before=1.5", nil, http.StatusBadRequest, nil))
+}
+
+func runUpdatedTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	for i := 0; i < 5; i++ {
+		configuration.TimeNow = func() time.Time {
+			return time.Unix(int64(1000+i*10), 0)
+		}
+		err := ctrl.Set(ctx, userid, model.Variable{
+			Key:               fmt.Sprintf("v%v", i),
+			Value:             i,
+			ProcessInstanceId: fmt.Sprintf("i%v", i%2),
+		})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+	configuration.TimeNow = backup
+
+	//v0: 1000, v1: 1010, v2: 1020, v3: 1030, v4: 1040
+	tests := []struct {
+		name     string
+		query    model.VariablesQueryOptions
+		expected []string
+	}{
+		{name: "unlimited", query: model.VariablesQueryOptions{}, expected: []string{"v0", "v1", "v2", "v3", "v4"}},
+		{name: "after", query: model.VariablesQueryOptions{UpdatedAfter: 1010}, expected: []string{"v2", "v3", "v4"}},
+		{name: "before", query: model.VariablesQueryOptions{UpdatedBefore: 1020}, expected: []string{"v0", "v1"}},
+		{name: "range", query: model.VariablesQueryOptions{UpdatedAfter: 1000, UpdatedBefore: 1040}, expected: []string{"v1", "v2", "v3"}},
+		{name: "empty range", query: model.VariablesQueryOptions{UpdatedAfter: 1010, UpdatedBefore: 1020}, expected: []string{}},
+		{name: "range with filter", query: model.VariablesQueryOptions{UpdatedAfter: 1000, UpdatedBefore: 1040, ProcessInstanceId: "i1"}, expected: []string{"v1", "v3"}},
+		{name: "range with limit", query: model.VariablesQueryOptions{UpdatedAfter: 1000, Limit: 2, Sort: "timestamp.desc"}, expected: []string{"v4", "v3"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list, err := ctrl.List(ctx, userid, test.query)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			keys := []string{}
+			for _, variable := range list {
+				keys = append(keys, variable.Key)
+			}
+			if !reflect.DeepEqual(keys, test.expected) {
+				t.Error(keys, test.expected)
+			}
+			if test.query.Limit > 0 {
+				return
+			}
+			count, err := ctrl.Count(ctx, userid, test.query)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if count.Count != int64(len(test.expected)) {
+				t.Error(count.Count, len(test.expected))
+			}
+		})
+	}
+}
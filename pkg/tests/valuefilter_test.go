@@ -0,0 +1,168 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/api"
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+func TestValueFilter(t *testing.T) {
+	backup := configuration.TimeNow
+	defer func() { configuration.TimeNow = backup }()
+	configuration.TimeNow = func() time.Time {
+		return time.Unix(1000, 0)
+	}
+
+	testWithBackendEnvs(t, nil, func(t *testing.T, env testEnv) {
+		runValueFilterTests(t, env.ctrl)
+		if env.useClient {
+			filter := url.QueryEscape(`{"path": "/status", "operator": "eq", "value": "ok"}`)
+			t.Run("api list", testRequest(env.config, "GET", "/variables?value_filter="+filter, nil, http.StatusOK, []model.VariableWithUnixTimestamp{{Variable: model.Variable{Key: "k2", Value: map[string]interface{}{"status": "ok", "n": 10}}, UnixTimestampInS: 1000}}))
+			t.Run("api count", testRequest(env.config, "GET", "/count/variables?value_filter="+filter+"&value_filter="+url.QueryEscape(`{"path": "/n", "operator": "gt", "value": 10}`), nil, http.StatusOK, model.Count{Count: 0}))
+			t.Run("api invalid json", testRequest(env.config, "GET", "/variables?value_filter=foo", nil, http.StatusBadRequest, nil))
+			t.Run("api invalid operator", testRequest(env.config, "GET", "/count/variables?value_filter="+url.QueryEscape(`{"path": "/n", "operator": "foo"}`), nil, http.StatusBadRequest, nil))
+		}
+	})
+}
+
+func runValueFilterTests(t *testing.T, ctrl api.Controller) {
+	ctx := context.Background()
+	userid := testTokenUser
+
+	values := map[string]interface{}{
+		"k1": map[string]interface{}{"status": "failed", "n": 3, "tags": []interface{}{"a", "b"}},
+		"k2": map[string]interface{}{"status": "ok", "n": 10},
+		"k3": map[string]interface{}{"status": "failed", "n": "3"},
+		"k4": map[string]interface{}{"status": nil},
+		"k5": "plain",
+		"k6": map[string]interface{}{"nested": map[string]interface{}{"x": true}},
+	}
+	for key, value := range values {
+		err := ctrl.Set(ctx, userid, model.Variable{Key: key, Value: value})
+		if err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	expectKeys := func(t *testing.T, query model.VariablesQueryOptions, expected []string) {
+		t.Helper()
+		list, err := ctrl.List(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		keys := []string{}
+		for _, variable := range list {
+			keys = append(keys, variable.Key)
+		}
+		if !reflect.DeepEqual(keys, expected) {
+			t.Error(keys, expected)
+		}
+		if query.Limit > 0 {
+			return
+		}
+		count, err := ctrl.Count(ctx, userid, query)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		if count.Count != int64(len(expected)) {
+			t.Error(count, expected)
+		}
+	}
+	filter := func(filters ...model.ValueFilter) model.VariablesQueryOptions {
+		return model.VariablesQueryOptions{Sort: "key.asc", ValueFilters: filters}
+	}
+
+	t.Run("eq", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/status", Operator: model.ValueFilterEq, Value: "failed"}), []string{"k1", "k3"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/n", Operator: model.ValueFilterEq, Value: 3}), []string{"k1"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/status", Operator: model.ValueFilterEq, Value: nil}), []string{"k4"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/nested/x", Operator: model.ValueFilterEq, Value: true}), []string{"k6"})
+		expectKeys(t, filter(model.ValueFilter{Path: "", Operator: model.ValueFilterEq, Value: "plain"}), []string{"k5"})
+	})
+
+	t.Run("arrays do not match by elements", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/tags", Operator: model.ValueFilterEq, Value: "a"}), []string{})
+		expectKeys(t, filter(model.ValueFilter{Path: "/tags/0", Operator: model.ValueFilterEq, Value: "a"}), []string{"k1"})
+	})
+
+	t.Run("ne", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/status", Operator: model.ValueFilterNe, Value: "failed"}), []string{"k2", "k4", "k5", "k6"})
+	})
+
+	t.Run("comparisons", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/n", Operator: model.ValueFilterGt, Value: 3}), []string{"k2"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/n", Operator: model.ValueFilterGte, Value: 3}), []string{"k1", "k2"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/n", Operator: model.ValueFilterLt, Value: "4"}), []string{"k3"})
+		expectKeys(t, filter(model.ValueFilter{Path: "/n", Operator: model.ValueFilterLte, Value: 10}), []string{"k1", "k2"})
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/status", Operator: model.ValueFilterExists}), []string{"k1", "k2", "k3", "k4"})
+	})
+
+	t.Run("in", func(t *testing.T) {
+		expectKeys(t, filter(model.ValueFilter{Path: "/status", Operator: model.ValueFilterIn, Value: []interface{}{"ok", nil}}), []string{"k2", "k4"})
+	})
+
+	t.Run("combined", func(t *testing.T) {
+		expectKeys(t, filter(
+			model.ValueFilter{Path: "/status", Operator: model.ValueFilterEq, Value: "failed"},
+			model.ValueFilter{Path: "/tags", Operator: model.ValueFilterExists},
+		), []string{"k1"})
+	})
+
+	t.Run("sort by value", func(t *testing.T) {
+		expectKeys(t, model.VariablesQueryOptions{Sort: "value/n.asc"}, []string{"k4", "k5", "k6", "k1", "k2", "k3"})
+		expectKeys(t, model.VariablesQueryOptions{Sort: "value/n.desc"}, []string{"k3", "k2", "k1", "k6", "k5", "k4"})
+		expectKeys(t, model.VariablesQueryOptions{
+			Sort:         "value/n.desc",
+			Limit:        2,
+			ValueFilters: []model.ValueFilter{{Path: "/n", Operator: model.ValueFilterExists}},
+		}, []string{"k3", "k2"})
+	})
+
+	t.Run("invalid filters", func(t *testing.T) {
+		for _, invalid := range []model.ValueFilter{
+			{Path: "/n", Operator: "foo"},
+			{Path: "n", Operator: model.ValueFilterEq, Value: 1},
+			{Path: "/n", Operator: model.ValueFilterGt, Value: true},
+			{Path: "/n", Operator: model.ValueFilterIn, Value: []interface{}{}},
+			{Path: "/n", Operator: model.ValueFilterEq, Value: map[string]interface{}{"a": 1}},
+		} {
+			_, err := ctrl.List(ctx, userid, filter(invalid))
+			if err == nil {
+				t.Error("expected error", invalid)
+			}
+			_, err = ctrl.Count(ctx, userid, filter(invalid))
+			if err == nil {
+				t.Error("expected error", invalid)
+			}
+		}
+	})
+}
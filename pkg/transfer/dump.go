@@ -0,0 +1,195 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package transfer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/configuration"
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+// a dump is a NDJSON file with one model.VariableWithUser per line; the variables of a user are written in one block
+
+// Dump writes all variables of the database to the file at location.
+// with Options.Resume, an existing file is continued: a partially written last line is removed
+// and only the variables missing in the file are appended.
+func Dump(ctx context.Context, db database.Database, location string, options Options) (stats Stats, err error) {
+	state := dumpState{users: map[string]bool{}, lastUserKeys: map[string]bool{}}
+	if options.Resume {
+		state, err = readDumpState(location)
+		if err != nil {
+			return stats, err
+		}
+		stats.Skipped = state.records
+	}
+	file, err := os.OpenFile(location, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return stats, err
+	}
+	defer file.Close()
+	err = file.Truncate(state.size)
+	if err != nil {
+		return stats, err
+	}
+	_, err = file.Seek(state.size, io.SeekStart)
+	if err != nil {
+		return stats, err
+	}
+
+	userIds, err := db.ListUserIds(ctx)
+	if err != nil {
+		return stats, err
+	}
+	progress := newProgress(options, "dump", &stats)
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, userId := range userIds {
+		if state.users[userId] && userId != state.lastUser {
+			stats.Users++
+			continue
+		}
+		err = forEachVariable(ctx, db, userId, options.getBatchSize(), func(variable model.VariableWithUser) error {
+			if userId == state.lastUser && state.lastUserKeys[variable.Key] {
+				return nil
+			}
+			err := encoder.Encode(variable)
+			if err != nil {
+				return err
+			}
+			progress.add(false)
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+		err = writer.Flush()
+		if err != nil {
+			return stats, err
+		}
+		stats.Users++
+	}
+	progress.log()
+	return stats, file.Sync()
+}
+
+// dumpState describes the content of an existing dump
+type dumpState struct {
+	size         int64           //size of the complete lines; following bytes belong to an interrupted write
+	records      int64           //number of complete lines
+	users        map[string]bool //users with variables in the dump
+	lastUser     string          //the user of the last line, whose variables may be incomplete
+	lastUserKeys map[string]bool //keys of the last user contained in the dump
+}
+
+func readDumpState(location string) (state dumpState, err error) {
+	state = dumpState{users: map[string]bool{}, lastUserKeys: map[string]bool{}}
+	err = readDump(location, func(variable model.VariableWithUser, line []byte) error {
+		state.size += int64(len(line))
+		state.records++
+		state.users[variable.UserId] = true
+		if variable.UserId != state.lastUser {
+			state.lastUser = variable.UserId
+			state.lastUserKeys = map[string]bool{}
+		}
+		state.lastUserKeys[variable.Key] = true
+		return nil
+	})
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	return state, err
+}
+
+// readDump calls f for every complete line of the dump; a last line without line break is ignored
+func readDump(location string, f func(variable model.VariableWithUser, line []byte) error) error {
+	file, err := os.Open(location)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	for lineNumber := 1; ; lineNumber++ {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		variable := model.VariableWithUser{}
+		err = json.Unmarshal(line, &variable)
+		if err != nil {
+			return fmt.Errorf("invalid dump line %v: %w", lineNumber, err)
+		}
+		if variable.UserId == "" || variable.Key == "" {
+			return fmt.Errorf("invalid dump line %v: missing user_id or key", lineNumber)
+		}
+		err = f(variable, line)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Restore writes all variables of the dump at location to the database.
+// with Options.Resume, variables which already exist in the database are skipped.
+func Restore(ctx context.Context, db database.Database, location string, options Options) (stats Stats, err error) {
+	progress := newProgress(options, "restore", &stats)
+	lastUser := ""
+	err = readDump(location, func(variable model.VariableWithUser, _ []byte) error {
+		if variable.UserId != lastUser {
+			lastUser = variable.UserId
+			stats.Users++
+		}
+		skipped, err := write(ctx, db, variable, options.Resume)
+		if err != nil {
+			return err
+		}
+		progress.add(skipped)
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+	progress.log()
+	return stats, nil
+}
+
+// VerifyDump compares the variable counts per user of the dump at location and the database;
+// variables of the dump which are expired by now are not counted
+func VerifyDump(ctx context.Context, db database.Database, location string) ([]Mismatch, error) {
+	now := configuration.TimeNow().Unix()
+	expected := map[string]int64{}
+	err := readDump(location, func(variable model.VariableWithUser, _ []byte) error {
+		if !variable.IsExpired(now) {
+			expected[variable.UserId]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return compareCounts(ctx, db, expected)
+}
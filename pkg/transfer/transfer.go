@@ -0,0 +1,226 @@
+/*
+ * Copyright (c) 2026 InfAI (CC SES)
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *    http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package transfer moves variables between database implementations, e.g. to migrate an installation from mongodb to postgres.
+// only the current versions of not expired variables are transferred; the history is not part of a transfer
+// and revisions restart at 1 in the target database.
+package transfer
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+
+	"github.com/SENERGY-Platform/process-io-api/pkg/database"
+	"github.com/SENERGY-Platform/process-io-api/pkg/model"
+)
+
+const defaultBatchSize = 1000
+const defaultProgressInterval = 10000
+
+type Options struct {
+	BatchSize        int          //variables read per list request; default 1000
+	ProgressInterval int          //a progress message is logged after every n transferred variables; default 10000
+	Resume           bool         //continue an interrupted transfer instead of starting from the beginning
+	Logger           *slog.Logger //default slog.Default()
+}
+
+func (this Options) getBatchSize() int {
+	if this.BatchSize <= 0 {
+		return defaultBatchSize
+	}
+	return this.BatchSize
+}
+
+func (this Options) getLogger() *slog.Logger {
+	if this.Logger == nil {
+		return slog.Default()
+	}
+	return this.Logger
+}
+
+// Stats summarizes a transfer
+type Stats struct {
+	Users     int   `json:"users"`
+	Variables int64 `json:"variables"` //transferred variables
+	Skipped   int64 `json:"skipped"`   //variables skipped because they were transferred by an earlier, interrupted run
+}
+
+// progress logs the transfer state every Options.ProgressInterval variables
+type progress struct {
+	logger   *slog.Logger
+	message  string
+	interval int64
+	stats    *Stats
+}
+
+func newProgress(options Options, message string, stats *Stats) *progress {
+	interval := int64(options.ProgressInterval)
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	return &progress{logger: options.getLogger(), message: message, interval: interval, stats: stats}
+}
+
+func (this *progress) add(skipped bool) {
+	if skipped {
+		this.stats.Skipped++
+	} else {
+		this.stats.Variables++
+	}
+	if (this.stats.Variables+this.stats.Skipped)%this.interval == 0 {
+		this.log()
+	}
+}
+
+func (this *progress) log() {
+	this.logger.Info(this.message, "users", this.stats.Users, "variables", this.stats.Variables, "skipped", this.stats.Skipped)
+}
+
+// forEachVariable calls f for all not expired variables of the user, ordered by key;
+// pages by cursor, so variables written or deleted in the meantime do not shift the following pages
+func forEachVariable(ctx context.Context, db database.Database, userId string, batchSize int, f func(variable model.VariableWithUser) error) error {
+	query := model.VariablesQueryOptions{
+		Limit: batchSize,
+		Sort:  "key.asc",
+	}
+	for {
+		list, err := db.ListVariables(ctx, userId, query)
+		if err != nil {
+			return err
+		}
+		for _, variable := range list {
+			err = f(model.VariableWithUser{VariableWithUnixTimestamp: variable, UserId: userId})
+			if err != nil {
+				return err
+			}
+		}
+		query.Cursor = model.NextVariablesCursor(query, list)
+		if query.Cursor == "" {
+			return nil
+		}
+	}
+}
+
+// exists checks if the target already contains the variable; used to skip variables on resume
+func exists(ctx context.Context, db database.Database, variable model.VariableWithUser) (bool, error) {
+	current, err := db.GetVariable(ctx, variable.UserId, variable.Key)
+	if err != nil {
+		return false, err
+	}
+	return current.Revision > 0, nil
+}
+
+// write stores the variable in the target; with resume, variables which already exist in the target are skipped
+func write(ctx context.Context, db database.Database, variable model.VariableWithUser, resume bool) (skipped bool, err error) {
+	if resume {
+		skipped, err = exists(ctx, db, variable)
+		if err != nil || skipped {
+			return skipped, err
+		}
+	}
+	variable.Revision = 0
+	return false, db.SetVariable(ctx, variable)
+}
+
+// Copy writes all variables of the source database to the target database
+func Copy(ctx context.Context, source database.Database, target database.Database, options Options) (stats Stats, err error) {
+	userIds, err := source.ListUserIds(ctx)
+	if err != nil {
+		return stats, err
+	}
+	progress := newProgress(options, "copy", &stats)
+	for _, userId := range userIds {
+		err = forEachVariable(ctx, source, userId, options.getBatchSize(), func(variable model.VariableWithUser) error {
+			skipped, err := write(ctx, target, variable, options.Resume)
+			if err != nil {
+				return err
+			}
+			progress.add(skipped)
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+		stats.Users++
+	}
+	progress.log()
+	return stats, nil
+}
+
+// Mismatch is a user whose variable count differs between the compared sides of a transfer
+type Mismatch struct {
+	UserId   string `json:"user_id"`
+	Expected int64  `json:"expected"`
+	Actual   int64  `json:"actual"`
+}
+
+// compareCounts compares the expected variable count per user with the count of the database;
+// users which are only known to the database are compared with an expected count of 0
+func compareCounts(ctx context.Context, db database.Database, expected map[string]int64) (result []Mismatch, err error) {
+	userIds, err := db.ListUserIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	users := map[string]bool{}
+	for _, userId := range userIds {
+		users[userId] = true
+	}
+	for userId := range expected {
+		users[userId] = true
+	}
+	for userId := range users {
+		count, err := db.CountVariables(ctx, userId, model.VariablesQueryOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if count.Count != expected[userId] {
+			result = append(result, Mismatch{UserId: userId, Expected: expected[userId], Actual: count.Count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].UserId < result[j].UserId
+	})
+	return result, nil
+}
+
+// countVariables returns the number of not expired variables per user
+func countVariables(ctx context.Context, db database.Database) (result map[string]int64, err error) {
+	userIds, err := db.ListUserIds(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result = map[string]int64{}
+	for _, userId := range userIds {
+		count, err := db.CountVariables(ctx, userId, model.VariablesQueryOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if count.Count > 0 {
+			result[userId] = count.Count
+		}
+	}
+	return result, nil
+}
+
+// Verify compares the variable counts per user of the source and the target database
+func Verify(ctx context.Context, source database.Database, target database.Database) ([]Mismatch, error) {
+	expected, err := countVariables(ctx, source)
+	if err != nil {
+		return nil, err
+	}
+	return compareCounts(ctx, target, expected)
+}